@@ -17,6 +17,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	zpav1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
 )
 
 // CustomApplicationSegmentParameters that are not part of the ZPA API
@@ -38,6 +40,57 @@ type CustomApplicationSegmentParameters struct {
 	// so set external ID.
 	// +optional
 	ServerGroupSelector *xpv1.Selector `json:"serverGroupSelector,omitempty"`
+
+	// ImportByName imports an existing ZPA object with the same name when no
+	// external name is set, instead of creating a new one.
+	// +optional
+	ImportByName *bool `json:"importByName,omitempty"`
+
+	// ObserveOnly only observes the ZPA object and reports its drift. The
+	// object is never created, updated, deleted or late initialized, and
+	// deleting the resource leaves it in ZPA. Requires the provider to run
+	// with --enable-observe-only.
+	// +optional
+	ObserveOnly *bool `json:"observeOnly,omitempty"`
+
+	// NameConflictPolicy decides what happens when creating the object fails
+	// because ZPA already has an object with the same name. Fail reports the
+	// conflict in the Synced condition with reason NameConflict, Adopt uses
+	// the existing object.
+	// Defaults to Fail.
+	// +optional
+	// +kubebuilder:validation:Enum=Fail;Adopt
+	NameConflictPolicy string `json:"nameConflictPolicy,omitempty"`
+
+	// IgnoreDrift lists JSONPath-like paths of parameters, e.g.
+	// defaultIdleTimeout or spec.forProvider.defaultIdleTimeout, whose value
+	// is owned outside of Crossplane. They are not compared with ZPA and
+	// updates keep their value in ZPA.
+	// +optional
+	IgnoreDrift []string `json:"ignoreDrift,omitempty"`
+
+	// ReportHealth queries the server groups, connectors and servers of the
+	// ApplicationSegment on every poll to report its health in the Healthy
+	// condition and in status.atProvider.health. The health does not reflect
+	// healthReporting, as ZPA does not expose the results of the health
+	// checks of single applications. Defaults to false.
+	// +optional
+	ReportHealth *bool `json:"reportHealth,omitempty"`
+
+	// DeletionOptions control how the ApplicationSegment is deleted in ZPA.
+	// +optional
+	DeletionOptions *DeletionOptions `json:"deletionOptions,omitempty"`
+}
+
+// DeletionOptions control how an ApplicationSegment is deleted in ZPA.
+type DeletionOptions struct {
+	// Force deletes the ApplicationSegment even if access policy rules
+	// reference it, which removes it from these rules. Without force the
+	// deletion waits until no policy rule references the ApplicationSegment.
+	// Releases before this field existed always forced the deletion.
+	// +optional
+	// +kubebuilder:default=false
+	Force *bool `json:"force,omitempty"`
 }
 
 // A ApplicationSegmentParameters defines desired state of a ApplicationSegmentSegment
@@ -74,7 +127,10 @@ type ApplicationSegmentParameters struct {
 	// +kubebuilder:validation:Enum=DEFAULT;NONE
 	HealthCheckType string `json:"healthCheckType,omitempty"`
 
-	// health reporting
+	// HealthReporting configures when ZPA checks the health of the
+	// applications of the ApplicationSegment. ZPA shows the results in its
+	// portal only and does not expose them in its API, so they are not part
+	// of the health reported by reportHealth.
 	// +kubebuilder:validation:Enum=NONE;ON_ACCESS;CONTINUOUS
 	HealthReporting string `json:"healthReporting,omitempty"`
 
@@ -96,9 +152,23 @@ type ApplicationSegmentParameters struct {
 	SegmentGroupID *string `json:"segmentGroupID,omitempty"`
 
 	// tcp port ranges
-	TCPPortRanges []string `json:"tcpPortRanges,omitempty"`
+	TCPPortRange []PortRange `json:"tcpPortRange,omitempty"`
 
 	// udp port ranges
+	UDPPortRange []PortRange `json:"udpPortRange,omitempty"`
+
+	// TCPPortRanges are the TCP ports in the ZPA wire format, a flat list of
+	// alternating from and to ports, e.g. ["443", "443"]. They are only used
+	// if tcpPortRange is empty.
+	// Deprecated: Use tcpPortRange.
+	// +optional
+	TCPPortRanges []string `json:"tcpPortRanges,omitempty"`
+
+	// UDPPortRanges are the UDP ports in the ZPA wire format, a flat list of
+	// alternating from and to ports. They are only used if udpPortRange is
+	// empty.
+	// Deprecated: Use udpPortRange.
+	// +optional
 	UDPPortRanges []string `json:"udpPortRanges,omitempty"`
 
 	// server groups ids
@@ -112,6 +182,19 @@ type ApplicationSegmentParameters struct {
 	Name string `json:"name"`
 }
 
+// PortRange is an inclusive range of ports.
+type PortRange struct {
+	// From is the first port of the range.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	From int32 `json:"from"`
+
+	// To is the last port of the range, it must not be lower than From.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	To int32 `json:"to"`
+}
+
 // A ApplicationSegmentSpec defines the desired state of a ApplicationSegment.
 type ApplicationSegmentSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
@@ -130,8 +213,67 @@ type Observation struct {
 	ModifiedBy         string                       `json:"modifiedBy,omitempty"`
 	ModifiedTime       string                       `json:"modifiedTime,omitempty"`
 	ID                 string                       `json:"id,omitempty"`
+	SegmentGroupName   string                       `json:"segmentGroupName,omitempty"`
 	ServerGroup        []AppServerGroup             `json:"serverGroup,omitempty"`
+	ClientlessApps     []AppObservation             `json:"clientlessApps,omitempty"`
+	InspectionApps     []AppObservation             `json:"inspectionApps,omitempty"`
 	ApplicationSegment ApplicationSegmentParameters `json:"applicationSegment,omitempty"`
+
+	// LastDrift is the last difference detected between the desired state
+	// and the object in ZPA.
+	// +optional
+	LastDrift *zpav1alpha1.Drift `json:"lastDrift,omitempty"`
+
+	// Health is the health of the server groups of the ApplicationSegment,
+	// reported when spec.forProvider.reportHealth is true.
+	// +optional
+	Health *ApplicationHealth `json:"health,omitempty"`
+}
+
+// ApplicationHealth is the health of an ApplicationSegment.
+type ApplicationHealth struct {
+	// HealthyServerGroups is the number of healthy server groups.
+	HealthyServerGroups int `json:"healthyServerGroups"`
+
+	// ServerGroups is the health of each server group.
+	// +optional
+	ServerGroups []ServerGroupHealth `json:"serverGroups,omitempty"`
+}
+
+// ServerGroupHealth is the health of a server group of an
+// ApplicationSegment. A server group is healthy when it is enabled, at least
+// one of its connectors is connected to ZPA and it has at least one enabled
+// server or uses dynamic discovery.
+type ServerGroupHealth struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Healthy bool   `json:"healthy"`
+
+	// Connectors is the number of connectors in the connector groups of
+	// the server group.
+	Connectors int `json:"connectors"`
+
+	// ConnectedConnectors is the number of these connectors that are
+	// connected to ZPA.
+	ConnectedConnectors int `json:"connectedConnectors"`
+
+	// Servers is the number of servers in the server group.
+	Servers int `json:"servers"`
+
+	// EnabledServers is the number of these servers that are enabled.
+	EnabledServers int `json:"enabledServers"`
+}
+
+// AppObservation are the observable fields of a clientless or inspection app
+// of an ApplicationSegment.
+type AppObservation struct {
+	ID                  string `json:"id,omitempty"`
+	AppID               string `json:"appId,omitempty"`
+	Name                string `json:"name,omitempty"`
+	Domain              string `json:"domain,omitempty"`
+	ApplicationPort     string `json:"applicationPort,omitempty"`
+	ApplicationProtocol string `json:"applicationProtocol,omitempty"`
+	Enabled             bool   `json:"enabled,omitempty"`
 }
 
 // AppServerGroup defines desired state of a AppServerGroup
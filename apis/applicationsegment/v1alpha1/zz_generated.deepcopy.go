@@ -22,10 +22,26 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apisv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppObservation) DeepCopyInto(out *AppObservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppObservation.
+func (in *AppObservation) DeepCopy() *AppObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(AppObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppServerGroup) DeepCopyInto(out *AppServerGroup) {
 	*out = *in
@@ -46,6 +62,26 @@ func (in *AppServerGroup) DeepCopy() *AppServerGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationHealth) DeepCopyInto(out *ApplicationHealth) {
+	*out = *in
+	if in.ServerGroups != nil {
+		in, out := &in.ServerGroups, &out.ServerGroups
+		*out = make([]ServerGroupHealth, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationHealth.
+func (in *ApplicationHealth) DeepCopy() *ApplicationHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSegment) DeepCopyInto(out *ApplicationSegment) {
 	*out = *in
@@ -144,6 +180,16 @@ func (in *ApplicationSegmentParameters) DeepCopyInto(out *ApplicationSegmentPara
 		*out = new(string)
 		**out = **in
 	}
+	if in.TCPPortRange != nil {
+		in, out := &in.TCPPortRange, &out.TCPPortRange
+		*out = make([]PortRange, len(*in))
+		copy(*out, *in)
+	}
+	if in.UDPPortRange != nil {
+		in, out := &in.UDPPortRange, &out.UDPPortRange
+		*out = make([]PortRange, len(*in))
+		copy(*out, *in)
+	}
 	if in.TCPPortRanges != nil {
 		in, out := &in.TCPPortRanges, &out.TCPPortRanges
 		*out = make([]string, len(*in))
@@ -230,6 +276,31 @@ func (in *CustomApplicationSegmentParameters) DeepCopyInto(out *CustomApplicatio
 		*out = new(v1.Selector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ImportByName != nil {
+		in, out := &in.ImportByName, &out.ImportByName
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ObserveOnly != nil {
+		in, out := &in.ObserveOnly, &out.ObserveOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IgnoreDrift != nil {
+		in, out := &in.IgnoreDrift, &out.IgnoreDrift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReportHealth != nil {
+		in, out := &in.ReportHealth, &out.ReportHealth
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeletionOptions != nil {
+		in, out := &in.DeletionOptions, &out.DeletionOptions
+		*out = new(DeletionOptions)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomApplicationSegmentParameters.
@@ -242,6 +313,26 @@ func (in *CustomApplicationSegmentParameters) DeepCopy() *CustomApplicationSegme
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionOptions) DeepCopyInto(out *DeletionOptions) {
+	*out = *in
+	if in.Force != nil {
+		in, out := &in.Force, &out.Force
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionOptions.
+func (in *DeletionOptions) DeepCopy() *DeletionOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Observation) DeepCopyInto(out *Observation) {
 	*out = *in
@@ -252,7 +343,27 @@ func (in *Observation) DeepCopyInto(out *Observation) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ClientlessApps != nil {
+		in, out := &in.ClientlessApps, &out.ClientlessApps
+		*out = make([]AppObservation, len(*in))
+		copy(*out, *in)
+	}
+	if in.InspectionApps != nil {
+		in, out := &in.InspectionApps, &out.InspectionApps
+		*out = make([]AppObservation, len(*in))
+		copy(*out, *in)
+	}
 	in.ApplicationSegment.DeepCopyInto(&out.ApplicationSegment)
+	if in.LastDrift != nil {
+		in, out := &in.LastDrift, &out.LastDrift
+		*out = new(apisv1alpha1.Drift)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(ApplicationHealth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observation.
@@ -264,3 +375,33 @@ func (in *Observation) DeepCopy() *Observation {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortRange) DeepCopyInto(out *PortRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortRange.
+func (in *PortRange) DeepCopy() *PortRange {
+	if in == nil {
+		return nil
+	}
+	out := new(PortRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerGroupHealth) DeepCopyInto(out *ServerGroupHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerGroupHealth.
+func (in *ServerGroupHealth) DeepCopy() *ServerGroupHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerGroupHealth)
+	in.DeepCopyInto(out)
+	return out
+}
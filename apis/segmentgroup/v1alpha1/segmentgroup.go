@@ -17,10 +17,40 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	zpav1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
 )
 
 // CustomSegmentParameters that are not part of the ZPA API
-type CustomSegmentParameters struct{}
+type CustomSegmentParameters struct {
+	// ImportByName imports an existing ZPA object with the same name when no
+	// external name is set, instead of creating a new one.
+	// +optional
+	ImportByName *bool `json:"importByName,omitempty"`
+
+	// ObserveOnly only observes the ZPA object and reports its drift. The
+	// object is never created, updated, deleted or late initialized, and
+	// deleting the resource leaves it in ZPA. Requires the provider to run
+	// with --enable-observe-only.
+	// +optional
+	ObserveOnly *bool `json:"observeOnly,omitempty"`
+
+	// NameConflictPolicy decides what happens when creating the object fails
+	// because ZPA already has an object with the same name. Fail reports the
+	// conflict in the Synced condition with reason NameConflict, Adopt uses
+	// the existing object.
+	// Defaults to Fail.
+	// +optional
+	// +kubebuilder:validation:Enum=Fail;Adopt
+	NameConflictPolicy string `json:"nameConflictPolicy,omitempty"`
+
+	// IgnoreDrift lists JSONPath-like paths of parameters, e.g. description
+	// or spec.forProvider.tcpKeepAliveEnabled, whose value is owned outside
+	// of Crossplane. They are not compared with ZPA and updates keep their
+	// value in ZPA.
+	// +optional
+	IgnoreDrift []string `json:"ignoreDrift,omitempty"`
+}
 
 // SegmentGroupParameters defines desired state of a Segment
 type SegmentGroupParameters struct {
@@ -63,11 +93,28 @@ type SegmentGroupStatus struct {
 
 // Observation are the observable fields of a SegmentGroup.
 type Observation struct {
-	CreationTime   string `json:"creationTime,omitempty"`
-	ModifiedBy     string `json:"modifiedBy,omitempty"`
-	ModifiedTime   string `json:"modifiedTime,omitempty"`
-	ID             string `json:"id,omitempty"`
-	PolicyMigrated bool   `json:"policyMigrated,omitempty"`
+	CreationTime        string   `json:"creationTime,omitempty"`
+	ModifiedBy          string   `json:"modifiedBy,omitempty"`
+	ModifiedTime        string   `json:"modifiedTime,omitempty"`
+	ID                  string   `json:"id,omitempty"`
+	PolicyMigrated      bool     `json:"policyMigrated,omitempty"`
+	Name                string   `json:"name,omitempty"`
+	ConfigSpace         string   `json:"configSpace,omitempty"`
+	Description         string   `json:"description,omitempty"`
+	Enabled             bool     `json:"enabled,omitempty"`
+	TCPKeepAliveEnabled string   `json:"tcpKeepAliveEnabled,omitempty"`
+	Applications        []NameID `json:"applications,omitempty"`
+
+	// LastDrift is the last difference detected between the desired state
+	// and the object in ZPA.
+	// +optional
+	LastDrift *zpav1alpha1.Drift `json:"lastDrift,omitempty"`
+}
+
+// NameID is the ID and name of a ZPA object.
+type NameID struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
 }
 
 // +kubebuilder:object:root=true
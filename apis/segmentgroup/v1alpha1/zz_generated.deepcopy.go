@@ -22,12 +22,28 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apisv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomSegmentParameters) DeepCopyInto(out *CustomSegmentParameters) {
 	*out = *in
+	if in.ImportByName != nil {
+		in, out := &in.ImportByName, &out.ImportByName
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ObserveOnly != nil {
+		in, out := &in.ObserveOnly, &out.ObserveOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IgnoreDrift != nil {
+		in, out := &in.IgnoreDrift, &out.IgnoreDrift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomSegmentParameters.
@@ -40,9 +56,34 @@ func (in *CustomSegmentParameters) DeepCopy() *CustomSegmentParameters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NameID) DeepCopyInto(out *NameID) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NameID.
+func (in *NameID) DeepCopy() *NameID {
+	if in == nil {
+		return nil
+	}
+	out := new(NameID)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Observation) DeepCopyInto(out *Observation) {
 	*out = *in
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]NameID, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastDrift != nil {
+		in, out := &in.LastDrift, &out.LastDrift
+		*out = new(apisv1alpha1.Drift)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observation.
@@ -117,7 +158,7 @@ func (in *SegmentGroupList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SegmentGroupParameters) DeepCopyInto(out *SegmentGroupParameters) {
 	*out = *in
-	out.CustomSegmentParameters = in.CustomSegmentParameters
+	in.CustomSegmentParameters.DeepCopyInto(&out.CustomSegmentParameters)
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
@@ -166,7 +207,7 @@ func (in *SegmentGroupSpec) DeepCopy() *SegmentGroupSpec {
 func (in *SegmentGroupStatus) DeepCopyInto(out *SegmentGroupStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SegmentGroupStatus.
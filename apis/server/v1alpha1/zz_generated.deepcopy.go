@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apisv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
 	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -29,17 +30,20 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomServerParameters) DeepCopyInto(out *CustomServerParameters) {
 	*out = *in
-	if in.ServerGroupRefs != nil {
-		in, out := &in.ServerGroupRefs, &out.ServerGroupRefs
-		*out = make([]v1.Reference, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ImportByName != nil {
+		in, out := &in.ImportByName, &out.ImportByName
+		*out = new(bool)
+		**out = **in
 	}
-	if in.ServerGroupSelector != nil {
-		in, out := &in.ServerGroupSelector, &out.ServerGroupSelector
-		*out = new(v1.Selector)
-		(*in).DeepCopyInto(*out)
+	if in.ObserveOnly != nil {
+		in, out := &in.ObserveOnly, &out.ObserveOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IgnoreDrift != nil {
+		in, out := &in.IgnoreDrift, &out.IgnoreDrift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
@@ -56,6 +60,16 @@ func (in *CustomServerParameters) DeepCopy() *CustomServerParameters {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Observation) DeepCopyInto(out *Observation) {
 	*out = *in
+	if in.ServerGroups != nil {
+		in, out := &in.ServerGroups, &out.ServerGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastDrift != nil {
+		in, out := &in.LastDrift, &out.LastDrift
+		*out = new(apisv1alpha1.Drift)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observation.
@@ -131,10 +145,22 @@ func (in *ServerList) DeepCopyObject() runtime.Object {
 func (in *ServerParameters) DeepCopyInto(out *ServerParameters) {
 	*out = *in
 	in.CustomServerParameters.DeepCopyInto(&out.CustomServerParameters)
-	if in.DynamicDiscovery != nil {
-		in, out := &in.DynamicDiscovery, &out.DynamicDiscovery
-		*out = new(bool)
-		**out = **in
+	if in.ServerGroups != nil {
+		in, out := &in.ServerGroups, &out.ServerGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServerGroupRefs != nil {
+		in, out := &in.ServerGroupRefs, &out.ServerGroupRefs
+		*out = make([]v1.Reference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServerGroupSelector != nil {
+		in, out := &in.ServerGroupSelector, &out.ServerGroupSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
@@ -146,11 +172,6 @@ func (in *ServerParameters) DeepCopyInto(out *ServerParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.ServerGroups != nil {
-		in, out := &in.ServerGroups, &out.ServerGroups
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerParameters.
@@ -184,7 +205,7 @@ func (in *ServerSpec) DeepCopy() *ServerSpec {
 func (in *ServerStatus) DeepCopyInto(out *ServerStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerStatus.
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by zpagen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	zpav1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+)
+
+// ServerParameters are the configurable fields of a Server.
+type ServerParameters struct {
+	CustomServerParameters `json:",inline"`
+
+	// address
+	Address string `json:"address,omitempty"`
+
+	// app server group ids
+	// +crossplane:generate:reference:type=github.com/crossplane-contrib/provider-zpa/apis/servergroup/v1alpha1.ServerGroup
+	// +crossplane:generate:reference:refFieldName=ServerGroupRefs
+	// +crossplane:generate:reference:selectorFieldName=ServerGroupSelector
+	ServerGroups []string `json:"serverGroups,omitempty"`
+
+	// ServerGroupRefs are references to ServerGroups used to set serverGroups.
+	// +optional
+	ServerGroupRefs []xpv1.Reference `json:"serverGroupRefs,omitempty"`
+
+	// ServerGroupSelector selects references to ServerGroups.
+	// +optional
+	ServerGroupSelector *xpv1.Selector `json:"serverGroupSelector,omitempty"`
+
+	// config space
+	// +kubebuilder:validation:Enum=DEFAULT;SIEM
+	ConfigSpace string `json:"configSpace,omitempty"`
+
+	// description
+	Description string `json:"description,omitempty"`
+
+	// enabled
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// name
+	// +kubebuilder:validation:Required
+	Name *string `json:"name"`
+}
+
+// A ServerSpec defines the desired state of a Server.
+type ServerSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ServerParameters `json:"forProvider"`
+}
+
+// A ServerStatus represents the status of a Server.
+type ServerStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          Observation `json:"atProvider,omitempty"`
+}
+
+// Observation are the observable fields of a Server.
+type Observation struct {
+	Address      string   `json:"address,omitempty"`
+	ServerGroups []string `json:"serverGroups,omitempty"`
+	ConfigSpace  string   `json:"configSpace,omitempty"`
+	CreationTime string   `json:"creationTime,omitempty"`
+	Description  string   `json:"description,omitempty"`
+	Enabled      bool     `json:"enabled,omitempty"`
+	ID           string   `json:"id,omitempty"`
+	ModifiedBy   string   `json:"modifiedBy,omitempty"`
+	ModifiedTime string   `json:"modifiedTime,omitempty"`
+	Name         string   `json:"name,omitempty"`
+
+	// LastDrift is the last difference detected between the desired state
+	// and the object in ZPA.
+	// +optional
+	LastDrift *zpav1alpha1.Drift `json:"lastDrift,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Server is the schema for ZPA Servers API
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,zpa}
+type Server struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ServerSpec   `json:"spec"`
+	Status ServerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ServerList contains a list of Server
+type ServerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Server `json:"items"`
+}
@@ -22,12 +22,78 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apisv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomServerGroupParameters) DeepCopyInto(out *CustomServerGroupParameters) {
+	*out = *in
+	if in.ImportByName != nil {
+		in, out := &in.ImportByName, &out.ImportByName
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ObserveOnly != nil {
+		in, out := &in.ObserveOnly, &out.ObserveOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IgnoreDrift != nil {
+		in, out := &in.IgnoreDrift, &out.IgnoreDrift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomServerGroupParameters.
+func (in *CustomServerGroupParameters) DeepCopy() *CustomServerGroupParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomServerGroupParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NameID) DeepCopyInto(out *NameID) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NameID.
+func (in *NameID) DeepCopy() *NameID {
+	if in == nil {
+		return nil
+	}
+	out := new(NameID)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Observation) DeepCopyInto(out *Observation) {
 	*out = *in
+	if in.AppConnectorGroups != nil {
+		in, out := &in.AppConnectorGroups, &out.AppConnectorGroups
+		*out = make([]NameID, len(*in))
+		copy(*out, *in)
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]NameID, len(*in))
+		copy(*out, *in)
+	}
+	if in.Servers != nil {
+		in, out := &in.Servers, &out.Servers
+		*out = make([]NameID, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastDrift != nil {
+		in, out := &in.LastDrift, &out.LastDrift
+		*out = new(apisv1alpha1.Drift)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Observation.
@@ -102,6 +168,7 @@ func (in *ServerGroupList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerGroupParameters) DeepCopyInto(out *ServerGroupParameters) {
 	*out = *in
+	in.CustomServerGroupParameters.DeepCopyInto(&out.CustomServerGroupParameters)
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
@@ -150,7 +217,7 @@ func (in *ServerGroupSpec) DeepCopy() *ServerGroupSpec {
 func (in *ServerGroupStatus) DeepCopyInto(out *ServerGroupStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
-	out.AtProvider = in.AtProvider
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerGroupStatus.
@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Crossplane Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A Drift is a difference between the desired state of a managed resource
+// and its object in ZPA.
+type Drift struct {
+	// DetectedTime is the time the drift was detected.
+	DetectedTime metav1.Time `json:"detectedTime"`
+
+	// ModifiedBy is the ZPA user that last modified the object.
+	// +optional
+	ModifiedBy string `json:"modifiedBy,omitempty"`
+
+	// ModifiedTime is the time the object was last modified in ZPA.
+	// +optional
+	ModifiedTime string `json:"modifiedTime,omitempty"`
+
+	// Fields that differ from the desired state.
+	// +kubebuilder:validation:MaxItems=20
+	Fields []FieldDrift `json:"fields"`
+
+	// Truncated is true if more fields differ than are listed.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// A FieldDrift is a field whose value in ZPA differs from the desired value.
+type FieldDrift struct {
+	// Path of the field, e.g. serverGroups[0].
+	Path string `json:"path"`
+
+	// Desired value of the field.
+	// +optional
+	Desired string `json:"desired,omitempty"`
+
+	// Observed value of the field in ZPA.
+	// +optional
+	Observed string `json:"observed,omitempty"`
+}
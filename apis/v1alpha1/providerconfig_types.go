@@ -37,6 +37,12 @@ type ProviderConfigSpec struct {
 	// Host address of the ZPA instance used by the provider
 	// +kubebuilder:validation:Required
 	Host string `json:"host"`
+
+	// CABundle is a PEM encoded bundle of the certificate authorities the
+	// certificate of the host is verified with, e.g. of a TLS inspecting
+	// proxy. Defaults to the certificate authorities of the system.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
 }
 
 // ProviderCredentials required to authenticate.
@@ -20,11 +20,13 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gopkg.in/alecthomas/kingpin.v2"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -42,6 +44,12 @@ import (
 	"github.com/crossplane-contrib/provider-zpa/pkg/features"
 )
 
+// Values of the --missing-crds flag.
+const (
+	missingCRDsFail    = "fail"
+	missingCRDsDisable = "disable"
+)
+
 func main() {
 	var (
 		app              = kingpin.New(filepath.Base(os.Args[0]), "AWS support for Crossplane.").DefaultEnvars()
@@ -53,6 +61,9 @@ func main() {
 
 		namespace                  = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 		enableExternalSecretStores = app.Flag("enable-external-secret-stores", "Enable support for ExternalSecretStores.").Default("false").Envar("ENABLE_EXTERNAL_SECRET_STORES").Bool()
+		enableObserveOnly          = app.Flag("enable-observe-only", "Enable support for observe only managed resources.").Default("false").Envar("ENABLE_OBSERVE_ONLY").Bool()
+		webhookTLSCertDir          = app.Flag("webhook-tls-cert-dir", "The directory of TLS certificate that will be used by the webhook server. There should be tls.crt and tls.key files.").Envar("WEBHOOK_TLS_CERT_DIR").String()
+		missingCRDs                = app.Flag("missing-crds", "What to do if the CRD of a kind the provider reconciles is not installed: fail to start, or disable the controllers that need it.").Default(missingCRDsFail).Envar("MISSING_CRDS").Enum(missingCRDsFail, missingCRDsDisable)
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -85,6 +96,8 @@ func main() {
 		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
 		LeaseDuration:              func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:              func() *time.Duration { d := 50 * time.Second; return &d }(),
+
+		CertDir: *webhookTLSCertDir,
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add zpa APIs to scheme")
@@ -97,7 +110,24 @@ func main() {
 		Features:                &feature.Flags{},
 	}
 
+	// Controllers whose CRDs are missing would only log errors, so check
+	// before starting them.
+	missing, err := controller.MissingKinds(mgr.GetRESTMapper(), controller.Kinds()...)
+	kingpin.FatalIfError(err, "Cannot check installed CRDs")
+	if len(missing) > 0 {
+		if *missingCRDs == missingCRDsFail {
+			kingpin.Fatalf("CRDs of %s are not installed, install them or run with --missing-crds=%s", kindNames(missing), missingCRDsDisable)
+		}
+		log.Info("Disabling controllers whose CRDs are not installed", "kinds", kindNames(missing))
+	}
+
 	if *enableExternalSecretStores {
+		missingStore, err := controller.MissingKinds(mgr.GetRESTMapper(), v1alpha1.StoreConfigGroupVersionKind)
+		kingpin.FatalIfError(err, "Cannot check installed CRDs")
+		if len(missingStore) > 0 {
+			kingpin.Fatalf("CRD of %s is not installed, which is needed by --enable-external-secret-stores", kindNames(missingStore))
+		}
+
 		o.Features.Enable(features.EnableAlphaExternalSecretStores)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaExternalSecretStores)
 
@@ -114,6 +144,23 @@ func main() {
 		})), "cannot create default store config")
 	}
 
-	kingpin.FatalIfError(controller.Setup(mgr, o), "Cannot setup zpa controllers")
+	if *enableObserveOnly {
+		o.Features.Enable(features.EnableAlphaObserveOnly)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaObserveOnly)
+	}
+
+	kingpin.FatalIfError(controller.SetupWithout(mgr, o, missing...), "Cannot setup zpa controllers")
+	if *webhookTLSCertDir != "" {
+		kingpin.FatalIfError(controller.SetupWebhooksWithout(mgr, missing...), "Cannot setup zpa webhooks")
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
+
+// kindNames returns the supplied kinds as a comma separated list.
+func kindNames(kinds []schema.GroupVersionKind) string {
+	names := make([]string, len(kinds))
+	for i, k := range kinds {
+		names[i] = k.GroupKind().String()
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// zpa-import exports the objects of a ZPA tenant that the provider manages as
+// managed resources. Applying them adopts the whole tenant: every resource
+// has the ID of its object as external name, and references the resources of
+// the other objects it uses instead of their IDs.
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-zpa/pkg/tenant"
+)
+
+func main() {
+	var (
+		app            = kingpin.New(filepath.Base(os.Args[0]), "Export the objects of a ZPA tenant as provider-zpa managed resources.").DefaultEnvars()
+		kubeconfig     = app.Flag("kubeconfig", "Kubeconfig of the cluster of the ProviderConfig. Defaults to the in-cluster config or $KUBECONFIG.").String()
+		providerConfig = app.Flag("provider-config", "ProviderConfig of the tenant, which the exported resources use.").Default("default").String()
+		deletionPolicy = app.Flag("deletion-policy", "Deletion policy of the exported resources.").Default(string(xpv1.DeletionOrphan)).Enum(string(xpv1.DeletionOrphan), string(xpv1.DeletionDelete))
+		outputDir      = app.Flag("output-dir", "Directory to write a manifest per kind to, instead of writing all resources to stdout.").ExistingDir()
+		timeout        = app.Flag("timeout", "Timeout of the export.").Default("10m").Duration()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	c, err := tenant.Connect(ctx, *kubeconfig, *providerConfig)
+	kingpin.FatalIfError(err, "Cannot connect to ZPA")
+
+	t, err := tenant.Read(ctx, c)
+	kingpin.FatalIfError(err, "Cannot read ZPA tenant")
+
+	mgs := t.Resources(xpv1.ResourceSpec{
+		ProviderConfigReference: &xpv1.Reference{Name: *providerConfig},
+		DeletionPolicy:          xpv1.DeletionPolicy(*deletionPolicy),
+	})
+	kingpin.FatalIfError(write(mgs, *outputDir), "Cannot write manifests")
+}
+
+// write writes the manifests of the supplied resources to a file per kind in
+// the supplied directory, or to stdout if it is empty.
+func write(mgs []resource.Managed, dir string) error {
+	kinds := make([]string, 0)
+	manifests := map[string]*bytes.Buffer{}
+	for _, mg := range mgs {
+		b, err := manifest(mg)
+		if err != nil {
+			return err
+		}
+		kind := mg.GetObjectKind().GroupVersionKind().Kind
+		if manifests[kind] == nil {
+			kinds = append(kinds, kind)
+			manifests[kind] = &bytes.Buffer{}
+		}
+		manifests[kind].WriteString("---\n")
+		manifests[kind].Write(b)
+	}
+
+	for _, kind := range kinds {
+		if dir == "" {
+			if _, err := io.Copy(os.Stdout, manifests[kind]); err != nil {
+				return err
+			}
+			continue
+		}
+		path := filepath.Join(dir, strings.ToLower(kind)+"s.yaml")
+		if err := ioutil.WriteFile(path, manifests[kind].Bytes(), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifest returns the manifest of the supplied resource, without status and
+// other fields set by the API server.
+func manifest(mg resource.Managed) ([]byte, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+	if err != nil {
+		return nil, err
+	}
+	delete(u, "status")
+	unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+	return yaml.Marshal(u)
+}
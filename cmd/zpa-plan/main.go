@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// zpa-plan prints what the provider would change in a ZPA tenant to reconcile
+// the managed resources of a directory of manifests, without changing the
+// tenant. The tenant is read from ZPA, or from a snapshot recorded earlier.
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/crossplane-contrib/provider-zpa/pkg/plan"
+	"github.com/crossplane-contrib/provider-zpa/pkg/tenant"
+)
+
+func main() {
+	var (
+		app             = kingpin.New(filepath.Base(os.Args[0]), "Plan the changes provider-zpa would make to a ZPA tenant.").DefaultEnvars()
+		dir             = app.Arg("manifests", "Directory of the manifests of the managed resources.").Required().ExistingDir()
+		kubeconfig      = app.Flag("kubeconfig", "Kubeconfig of the cluster of the ProviderConfig. Defaults to the in-cluster config or $KUBECONFIG.").String()
+		providerConfig  = app.Flag("provider-config", "ProviderConfig of the tenant.").Default("default").String()
+		snapshot        = app.Flag("snapshot", "Read the tenant from this snapshot, e.g. written by zpa-snapshot save, instead of from ZPA.").ExistingFile()
+		record          = app.Flag("record", "Write the tenant read from ZPA to this snapshot.").String()
+		deleteUnmanaged = app.Flag("delete-unmanaged", "Plan to delete objects that no manifest manages, as if the manifests described the whole tenant.").Bool()
+		timeout         = app.Flag("timeout", "Timeout of reading the tenant.").Default("10m").Duration()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	mgs, err := plan.ReadManifests(*dir)
+	kingpin.FatalIfError(err, "Cannot read manifests")
+
+	var t *tenant.Tenant
+	if *snapshot != "" {
+		s, err := tenant.ReadSnapshot(*snapshot)
+		kingpin.FatalIfError(err, "Cannot read snapshot")
+		t, err = s.Tenant()
+		kingpin.FatalIfError(err, "Cannot read snapshot")
+	} else {
+		c, err := tenant.Connect(ctx, *kubeconfig, *providerConfig)
+		kingpin.FatalIfError(err, "Cannot connect to ZPA")
+		t, err = tenant.Read(ctx, c)
+		kingpin.FatalIfError(err, "Cannot read ZPA tenant")
+	}
+
+	if *record != "" {
+		s, err := t.Snapshot()
+		kingpin.FatalIfError(err, "Cannot take snapshot")
+		kingpin.FatalIfError(s.Write(*record), "Cannot write snapshot")
+	}
+
+	p, err := plan.New(ctx, t, mgs, plan.Options{DeleteUnmanaged: *deleteUnmanaged})
+	kingpin.FatalIfError(err, "Cannot plan changes")
+	kingpin.FatalIfError(p.Print(os.Stdout), "Cannot print plan")
+}
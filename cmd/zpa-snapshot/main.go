@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// zpa-snapshot saves the objects of a ZPA tenant that the provider manages to
+// a snapshot, and restores them into the same or another tenant.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/crossplane-contrib/provider-zpa/pkg/tenant"
+)
+
+func main() {
+	var (
+		app            = kingpin.New(filepath.Base(os.Args[0]), "Save and restore snapshots of a ZPA tenant.").DefaultEnvars()
+		kubeconfig     = app.Flag("kubeconfig", "Kubeconfig of the cluster of the ProviderConfig. Defaults to the in-cluster config or $KUBECONFIG.").String()
+		providerConfig = app.Flag("provider-config", "ProviderConfig of the tenant.").Default("default").String()
+		timeout        = app.Flag("timeout", "Timeout of saving or restoring the tenant.").Default("30m").Duration()
+
+		save     = app.Command("save", "Save the tenant to a snapshot.")
+		saveFile = save.Arg("snapshot", "File to write the snapshot to.").Required().String()
+
+		restore     = app.Command("restore", "Create the objects of a snapshot that do not exist in the tenant.")
+		restoreFile = restore.Arg("snapshot", "File to read the snapshot from.").Required().ExistingFile()
+	)
+	cmd := kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	switch cmd {
+	case save.FullCommand():
+		c, err := tenant.Connect(ctx, *kubeconfig, *providerConfig)
+		kingpin.FatalIfError(err, "Cannot connect to ZPA")
+		t, err := tenant.Read(ctx, c)
+		kingpin.FatalIfError(err, "Cannot read ZPA tenant")
+		s, err := t.Snapshot()
+		kingpin.FatalIfError(err, "Cannot take snapshot")
+		kingpin.FatalIfError(s.Write(*saveFile), "Cannot save snapshot")
+
+	case restore.FullCommand():
+		s, err := tenant.ReadSnapshot(*restoreFile)
+		kingpin.FatalIfError(err, "Cannot read snapshot")
+		c, err := tenant.Connect(ctx, *kubeconfig, *providerConfig)
+		kingpin.FatalIfError(err, "Cannot connect to ZPA")
+		restored, err := s.Restore(ctx, c)
+		for _, r := range restored {
+			action := "kept"
+			if r.Created {
+				action = "created"
+			}
+			fmt.Printf("%s %s %s (%s)\n", action, r.Kind, r.Name, r.ID)
+		}
+		kingpin.FatalIfError(err, "Cannot restore snapshot")
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// verifycrds checks that a directory contains a CRD for every kind the
+// provider registers, so that installing the package installs all CRDs the
+// controllers need. It is run by go generate after the CRDs are generated.
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane-contrib/provider-zpa/apis"
+)
+
+func main() {
+	var (
+		app = kingpin.New(filepath.Base(os.Args[0]), "Verify that there is a CRD for every kind the provider registers.").DefaultEnvars()
+		dir = app.Arg("crds", "Directory of the generated CRDs.").Required().ExistingDir()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	s := runtime.NewScheme()
+	kingpin.FatalIfError(apis.AddToScheme(s), "Cannot add zpa APIs to scheme")
+
+	crds, err := readCRDs(*dir)
+	kingpin.FatalIfError(err, "Cannot read CRDs")
+
+	missing := make([]string, 0)
+	for gvk, t := range s.AllKnownTypes() {
+		if !isCustomResource(gvk, t) || crds[gvk] {
+			continue
+		}
+		missing = append(missing, gvk.String())
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		kingpin.Fatalf("%s contains no CRD that serves %s", *dir, strings.Join(missing, "; "))
+	}
+}
+
+// isCustomResource returns true if the supplied type registered for the
+// supplied kind is a custom resource, rather than a list of them or one of
+// the option types every API group registers.
+func isCustomResource(gvk schema.GroupVersionKind, t reflect.Type) bool {
+	if strings.HasSuffix(gvk.Kind, "List") {
+		return false
+	}
+	_, ok := reflect.New(t).Interface().(metav1.Object)
+	return ok
+}
+
+// readCRDs returns the kinds served by the CRDs in the supplied directory.
+func readCRDs(dir string) (map[schema.GroupVersionKind]bool, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := map[schema.GroupVersionKind]bool{}
+	for _, f := range files {
+		b, err := ioutil.ReadFile(filepath.Clean(f))
+		if err != nil {
+			return nil, err
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := yaml.Unmarshal(b, crd); err != nil {
+			return nil, errors.Wrapf(err, "cannot parse %s", f)
+		}
+		for _, v := range crd.Spec.Versions {
+			if v.Served {
+				kinds[schema.GroupVersionKind{Group: crd.Spec.Group, Version: v.Name, Kind: crd.Spec.Names.Kind}] = true
+			}
+		}
+	}
+	return kinds, nil
+}
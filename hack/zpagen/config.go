@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// A Config lists the kinds to generate.
+type Config struct {
+	Kinds []KindConfig `json:"kinds"`
+}
+
+// A KindConfig configures the generation of a kind. Fields are referred to by
+// their JSON name in the ZPA API.
+type KindConfig struct {
+	// Kind is the name of the kind, e.g. Server.
+	Kind string `json:"kind"`
+
+	// Group is the directory of the kind below apis and pkg/controller.
+	Group string `json:"group"`
+
+	// Model is the type of the objects of the kind in the ZPA API models,
+	// e.g. ApplicationServer.
+	Model string `json:"model"`
+
+	// ID is the field of the model that holds the ID of an object.
+	ID string `json:"id"`
+
+	// Client is the package of the ZPA API client whose operations manage
+	// the objects, e.g. app_server_controller.
+	Client string `json:"client"`
+
+	// Operations are the names of the operations of the client.
+	Operations Operations `json:"operations"`
+
+	// References are the fields that hold IDs of objects of other kinds.
+	References []Reference `json:"references,omitempty"`
+
+	// ReadOnly are the fields, in addition to the ID, creation and
+	// modification fields, that are observed but not configurable.
+	ReadOnly []string `json:"readOnly,omitempty"`
+
+	// LateInitialize are the parameters that are set from ZPA when unset.
+	LateInitialize []string `json:"lateInitialize,omitempty"`
+
+	// NoDrift are the parameters that are not compared with ZPA, e.g.
+	// because ZPA does not let them be updated.
+	NoDrift []string `json:"noDrift,omitempty"`
+
+	// Custom are the methods of the Kind, and the Setup function, that are
+	// written by hand rather than generated.
+	Custom []string `json:"custom,omitempty"`
+}
+
+// Operations are the names of the operations that manage objects of a kind.
+type Operations struct {
+	Get    string `json:"get"`
+	List   string `json:"list"`
+	Create string `json:"create"`
+	Update string `json:"update"`
+	Delete string `json:"delete"`
+}
+
+// A Reference is a field that holds the IDs of objects of another kind.
+type Reference struct {
+	// Field is the field of the model.
+	Field string `json:"field"`
+
+	// Name is the name of the parameter. Defaults to the name of the field.
+	Name string `json:"name,omitempty"`
+
+	// Type is the referenced kind, e.g.
+	// github.com/crossplane-contrib/provider-zpa/apis/servergroup/v1alpha1.ServerGroup
+	Type string `json:"type"`
+}
+
+func readConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{}
+	if err := yaml.UnmarshalStrict(b, c); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse %s", path)
+	}
+	return c, nil
+}
+
+func (k KindConfig) custom(method string) bool {
+	return contains(k.Custom, method)
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// readOnly are the fields of every model that are observed but not
+// configurable.
+var readOnly = []string{"creationTime", "modifiedBy", "modifiedTime"}
+
+// A kind is a KindConfig together with what was looked up in the ZPA API
+// client about it.
+type kind struct {
+	KindConfig
+
+	// Package is the name of the controller package.
+	Package string
+
+	// ClientField is the field of the ZPA API client that holds the client
+	// of the operations.
+	ClientField string
+
+	// Fields are the fields of the model, in the order of the model.
+	Fields []field
+
+	// IDField is the field of the model that holds the ID.
+	IDField string
+
+	// NamePointer is true if the name of the model is a *string.
+	NamePointer bool
+
+	// Modified is true if the model tells who last modified an object and
+	// when.
+	Modified bool
+
+	Get, List, Create, Update, Delete operation
+}
+
+// A field of a model and the parameter and observation it maps to.
+type field struct {
+	modelField
+
+	// Param and ParamJSON are the Go and JSON name of the parameter.
+	Param     string
+	ParamJSON string
+
+	Configurable bool
+	LateInit     bool
+	Drift        bool
+
+	Ref *reference
+}
+
+// A reference is a field that holds the IDs of objects of another kind.
+type reference struct {
+	Type          string
+	Kind          string
+	RefField      string
+	SelectorField string
+	Slice         bool
+}
+
+// supported are the types of model fields that are mapped to parameters and
+// observations. Fields of other types, e.g. nested objects, are skipped.
+var supported = map[string]bool{
+	"string":   true,
+	"*string":  true,
+	"bool":     true,
+	"int32":    true,
+	"int64":    true,
+	"[]string": true,
+}
+
+// newKind looks up the supplied kind in the supplied models and client
+// package.
+func newKind(c KindConfig, models, client *source, pkg string) (*kind, error) {
+	k := &kind{KindConfig: c, Package: pkg, ClientField: camel(c.Client)}
+
+	mfs, err := models.modelFields(c.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	for _, mf := range mfs {
+		known[mf.JSON] = true
+		if !supported[mf.Type] {
+			fmt.Fprintf(os.Stderr, "zpagen: skipping field %s of model %s of unsupported type %s\n", mf.JSON, c.Model, mf.Type)
+			continue
+		}
+
+		f := field{modelField: mf, Param: mf.Name, ParamJSON: mf.JSON}
+		switch mf.JSON {
+		case c.ID:
+			k.IDField = mf.Name
+		case "name":
+			k.NamePointer = mf.Type == "*string"
+		case "modifiedBy", "modifiedTime":
+			k.Modified = true
+		}
+		f.Configurable = mf.JSON != c.ID && !contains(readOnly, mf.JSON) && !contains(c.ReadOnly, mf.JSON)
+		f.LateInit = f.Configurable && contains(c.LateInitialize, mf.JSON)
+		f.Drift = f.Configurable && !contains(c.NoDrift, mf.JSON)
+		k.Fields = append(k.Fields, f)
+	}
+
+	for _, l := range [][]string{{c.ID}, c.ReadOnly, c.LateInitialize, c.NoDrift} {
+		for _, name := range l {
+			if !known[name] {
+				return nil, errors.Errorf("model %s has no field %s", c.Model, name)
+			}
+		}
+	}
+	if k.IDField == "" {
+		return nil, errors.Errorf("ID %s of model %s is not a string", c.ID, c.Model)
+	}
+
+	for _, r := range c.References {
+		if err := k.reference(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, k.operations(client)
+}
+
+// reference maps the field of the supplied reference to a parameter that is
+// resolved from references to, or a selector of, objects of another kind.
+func (k *kind) reference(r Reference) error {
+	for i := range k.Fields {
+		f := &k.Fields[i]
+		if f.JSON != r.Field {
+			continue
+		}
+		if !f.Configurable || (f.Type != "string" && f.Type != "[]string") {
+			return errors.Errorf("field %s of model %s cannot be a reference", r.Field, k.Model)
+		}
+
+		if r.Name != "" {
+			f.ParamJSON = r.Name
+			f.Param = camel(r.Name)
+		}
+		ref := &reference{Type: r.Type, Kind: r.Type[strings.LastIndex(r.Type, ".")+1:], Slice: f.Type == "[]string"}
+		ref.RefField = ref.Kind + "Ref"
+		if ref.Slice {
+			ref.RefField += "s"
+		}
+		ref.SelectorField = ref.Kind + "Selector"
+		f.Ref = ref
+		return nil
+	}
+	return errors.Errorf("model %s has no field %s", k.Model, r.Field)
+}
+
+// operations looks up the configured operations in the supplied client
+// package.
+func (k *kind) operations(client *source) error {
+	var err error
+	ops := k.KindConfig.Operations
+	if k.Get, err = client.operation(ops.Get, k.Model, true, false); err != nil {
+		return err
+	}
+	if k.List, err = client.operation(ops.List, k.Model, false, false); err != nil {
+		return err
+	}
+	for _, p := range []string{"Page", "Pagesize", "Search"} {
+		if _, err := client.fieldType(ops.List+"Params", p); err != nil {
+			return errors.Wrapf(err, "%s is not paged", ops.List)
+		}
+	}
+	if k.Create, err = client.operation(ops.Create, k.Model, false, true); err != nil {
+		return err
+	}
+	if k.Update, err = client.operation(ops.Update, k.Model, true, true); err != nil {
+		return err
+	}
+	k.Delete, err = client.operation(ops.Delete, k.Model, true, false)
+	return err
+}
+
+// Params are the configurable fields.
+func (k *kind) Params() []field {
+	params := make([]field, 0, len(k.Fields))
+	for _, f := range k.Fields {
+		if f.Configurable {
+			params = append(params, f)
+		}
+	}
+	return params
+}
+
+// Renamed maps the JSON names of the parameters that are named differently
+// in the model to their name there.
+func (k *kind) Renamed() map[string]string {
+	renamed := map[string]string{}
+	for _, f := range k.Params() {
+		if f.ParamJSON != f.JSON {
+			renamed[f.ParamJSON] = f.JSON
+		}
+	}
+	return renamed
+}
+
+// Generated returns true if the supplied method, or the Setup function, is
+// not written by hand.
+func (k *kind) Generated(method string) bool {
+	return !k.custom(method)
+}
+
+// ParamType is the Go type of the parameter. Scalars other than strings are
+// pointers, so that unset parameters can be told apart from zero values.
+func (f field) ParamType() string {
+	switch f.Type {
+	case "bool", "int32", "int64":
+		return "*" + f.Type
+	}
+	return f.Type
+}
+
+// ObservationType is the Go type of the observation.
+func (f field) ObservationType() string {
+	return strings.TrimPrefix(f.Type, "*")
+}
+
+// Tag is the struct tag of the parameter.
+func (f field) Tag() string {
+	if f.Required {
+		return fmt.Sprintf("`json:\"%s\"`", f.ParamJSON)
+	}
+	return fmt.Sprintf("`json:\"%s,omitempty\"`", f.ParamJSON)
+}
+
+// ToAPI returns the value of the field of the model from the parameters p.
+func (f field) ToAPI(p string) string {
+	switch f.Type {
+	case "bool", "int32", "int64":
+		return fmt.Sprintf("zpaclient.%sValue(%s.%s)", title(f.Type), p, f.Param)
+	}
+	return p + "." + f.Param
+}
+
+// FromAPI returns the value of the parameter from the model obj.
+func (f field) FromAPI(obj string) string {
+	switch f.Type {
+	case "bool", "int32", "int64":
+		return fmt.Sprintf("zpaclient.%s(%s.%s)", title(f.Type), obj, f.Name)
+	}
+	return obj + "." + f.Name
+}
+
+// Observe returns the value of the observation from the model obj.
+func (f field) Observe(obj string) string {
+	if f.Type == "*string" {
+		return fmt.Sprintf("zpaclient.StringValue(%s.%s)", obj, f.Name)
+	}
+	return obj + "." + f.Name
+}
+
+// Differs returns a condition that is true if the parameters p desire
+// another value than that of the model obj.
+func (f field) Differs(p, obj string) string {
+	switch f.Type {
+	case "*string":
+		return fmt.Sprintf("!zpaclient.IsEqualString(%s.%s, %s.%s)", p, f.Param, obj, f.Name)
+	case "bool", "int32", "int64":
+		return fmt.Sprintf("zpaclient.%sValue(%s.%s) != %s.%s", title(f.Type), p, f.Param, obj, f.Name)
+	case "[]string":
+		return fmt.Sprintf("!zpaclient.IsEqualStringArrayContent(%s.%s, %s.%s)", p, f.Param, obj, f.Name)
+	}
+	return fmt.Sprintf("%s.%s != %s.%s", p, f.Param, obj, f.Name)
+}
+
+// Unset returns a condition that is true if the parameters p do not set
+// the parameter.
+func (f field) Unset(p string) string {
+	if f.Type == "string" {
+		return fmt.Sprintf("%s.%s == \"\"", p, f.Param)
+	}
+	return fmt.Sprintf("%s.%s == nil", p, f.Param)
+}
+
+// camel returns the exported Go name of the supplied JSON or package name,
+// e.g. AppServerController for app_server_controller.
+func camel(s string) string {
+	b := strings.Builder{}
+	for _, w := range strings.Split(s, "_") {
+		b.WriteString(title(w))
+	}
+	return b.String()
+}
+
+func title(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func lower(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
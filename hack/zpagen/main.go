@@ -0,0 +1,198 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// zpagen generates managed resources from the models of the ZPA API client.
+// For every kind in its config it generates the API types of the kind and the
+// adapter.Kind that reconciles them, i.e. the conversion of the resources to
+// and from the ZPA API, their comparison and the calls of the ZPA API client.
+//
+// Fields of the model that are strings, booleans, integers or lists of
+// strings become parameters and observations, other fields are skipped.
+// Parameters that ZPA does not model, e.g. importByName, are written by hand
+// in the Custom<Kind>Parameters type, and so are the methods of the Kind the
+// config lists as custom, e.g. a Delete that first removes dependencies.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const (
+	generated = "// Code generated by zpagen. DO NOT EDIT."
+	filename  = "zz_generated.zpa.go"
+	version   = "v1alpha1"
+	module    = "github.com/crossplane-contrib/provider-zpa"
+)
+
+// An importSpec is an import of a generated file. It is only imported if the
+// file uses it.
+type importSpec struct {
+	name, path string
+}
+
+var (
+	typesImports = [][]importSpec{
+		{{"metav1", "k8s.io/apimachinery/pkg/apis/meta/v1"}},
+		{{"xpv1", "github.com/crossplane/crossplane-runtime/apis/common/v1"}},
+		{{"zpav1alpha1", module + "/apis/v1alpha1"}},
+	}
+	controllerImports = func(k *kind) [][]importSpec {
+		return [][]importSpec{
+			{{"context", "context"}},
+			{{"ctrl", "sigs.k8s.io/controller-runtime"}},
+			{
+				{"controller", "github.com/crossplane/crossplane-runtime/pkg/controller"},
+				{"meta", "github.com/crossplane/crossplane-runtime/pkg/meta"},
+			},
+			{
+				{k.Client, clientPath + "/" + k.Client},
+				{"models", modelsPath},
+			},
+			{
+				{"v1alpha1", module + "/apis/" + k.Group + "/" + version},
+				{"zpav1alpha1", module + "/apis/v1alpha1"},
+				{"zpaclient", module + "/pkg/client"},
+				{"adapter", module + "/pkg/controller/adapter"},
+			},
+		}
+	}
+)
+
+func main() {
+	var (
+		app         = kingpin.New(filepath.Base(os.Args[0]), "Generate managed resources from the models of the ZPA API client.").DefaultEnvars()
+		config      = app.Arg("config", "Config of the kinds to generate.").Required().ExistingFile()
+		header      = app.Flag("header-file", "File whose content is prepended to generated files.").Required().ExistingFile()
+		apis        = app.Flag("apis", "Directory of the API groups.").Default(".").ExistingDir()
+		controllers = app.Flag("controllers", "Directory of the controllers.").Default("../pkg/controller").ExistingDir()
+	)
+	kingpin.MustParse(app.Parse(os.Args[1:]))
+
+	c, err := readConfig(*config)
+	kingpin.FatalIfError(err, "Cannot read config")
+
+	b, err := ioutil.ReadFile(filepath.Clean(*header))
+	kingpin.FatalIfError(err, "Cannot read header file")
+
+	kingpin.FatalIfError(generate(c, string(b), *apis, *controllers), "Cannot generate kinds")
+}
+
+// generate generates the types of the kinds of the supplied config below the
+// supplied directory of the API groups, and their controllers below the
+// supplied directory of the controllers.
+func generate(c *Config, header, apis, controllers string) error {
+	models, err := parse(modelsPath)
+	if err != nil {
+		return errors.Wrap(err, "cannot parse ZPA API models")
+	}
+
+	for _, kc := range c.Kinds {
+		client, err := parse(clientPath + "/" + kc.Client)
+		if err != nil {
+			return errors.Wrapf(err, "cannot parse ZPA API client of %s", kc.Kind)
+		}
+
+		dir := filepath.Join(controllers, kc.Group)
+		pkg, err := packageName(dir, kc.Group)
+		if err != nil {
+			return errors.Wrapf(err, "cannot determine package of %s", dir)
+		}
+
+		k, err := newKind(kc, models, client, pkg)
+		if err != nil {
+			return errors.Wrapf(err, "cannot generate %s", kc.Kind)
+		}
+
+		if err := render(filepath.Join(apis, kc.Group, version, filename), header, version, typesTemplate, typesImports, k); err != nil {
+			return errors.Wrapf(err, "cannot generate types of %s", kc.Kind)
+		}
+		if err := render(filepath.Join(dir, filename), header, pkg, controllerTemplate, controllerImports(k), k); err != nil {
+			return errors.Wrapf(err, "cannot generate controller of %s", kc.Kind)
+		}
+	}
+	return nil
+}
+
+// packageName returns the name of the Go package in the supplied directory,
+// or the supplied default if it has none yet.
+func packageName(dir, def string) (string, error) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi os.FileInfo) bool {
+		return fi.Name() != filename && !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.PackageClauseOnly)
+	if os.IsNotExist(err) {
+		return def, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	for name := range pkgs {
+		return name, nil
+	}
+	return def, nil
+}
+
+// render renders the supplied template of the supplied kind to the supplied
+// file, importing those of the supplied groups of imports that it uses.
+func render(path, header, pkg string, t *template.Template, imports [][]importSpec, k *kind) error {
+	body := &bytes.Buffer{}
+	if err := t.Execute(body, k); err != nil {
+		return err
+	}
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "%s\n%s\n\npackage %s\n\nimport (\n", header, generated, pkg)
+	for _, group := range imports {
+		used := 0
+		for _, i := range group {
+			if !regexp.MustCompile(`\b` + i.name + `\.`).Match(body.Bytes()) {
+				continue
+			}
+			if i.name == filepath.Base(i.path) {
+				fmt.Fprintf(b, "\t%q\n", i.path)
+			} else {
+				fmt.Fprintf(b, "\t%s %q\n", i.name, i.path)
+			}
+			used++
+		}
+		if used > 0 {
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString(")\n")
+	b.Write(body.Bytes())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return errors.Wrapf(err, "cannot format %s", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return errors.Wrapf(ioutil.WriteFile(path, src, 0644), "cannot write %s", path) // nolint:gosec
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestGenerate(t *testing.T) {
+	c, err := readConfig(filepath.Join("testdata", "zpagen.yaml"))
+	if err != nil {
+		t.Fatalf("readConfig(...): %v", err)
+	}
+	header, err := ioutil.ReadFile(filepath.Join("testdata", "header.txt"))
+	if err != nil {
+		t.Fatalf("cannot read header: %v", err)
+	}
+
+	dir := t.TempDir()
+	apis, controllers := filepath.Join(dir, "apis"), filepath.Join(dir, "controller")
+	if err := generate(c, string(header), apis, controllers); err != nil {
+		t.Fatalf("generate(...): %v", err)
+	}
+
+	cases := map[string]struct {
+		reason    string
+		generated string
+		golden    string
+	}{
+		"Types": {
+			reason:    "The API types of the kind should match the golden file.",
+			generated: filepath.Join(apis, "server", version, filename),
+			golden:    filepath.Join("testdata", "types.go.golden"),
+		},
+		"Controller": {
+			reason:    "The Kind and Setup of the kind should match the golden file.",
+			generated: filepath.Join(controllers, "server", filename),
+			golden:    filepath.Join("testdata", "controller.go.golden"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ioutil.ReadFile(filepath.Clean(tc.generated))
+			if err != nil {
+				t.Fatalf("cannot read generated file: %v", err)
+			}
+			if *update {
+				if err := ioutil.WriteFile(tc.golden, got, 0600); err != nil {
+					t.Fatalf("cannot update golden file: %v", err)
+				}
+			}
+			want, err := ioutil.ReadFile(tc.golden)
+			if err != nil {
+				t.Fatalf("cannot read golden file: %v", err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want, +got:\n%s\nRun go test ./hack/zpagen -update to accept the change.", tc.reason, diff)
+			}
+		})
+	}
+}
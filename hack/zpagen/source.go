@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	clientPath = "github.com/haarchri/zpa-go-client/pkg/client"
+	modelsPath = "github.com/haarchri/zpa-go-client/pkg/models"
+)
+
+// A source is a package of the ZPA API client, parsed from its source code.
+type source struct {
+	types map[string]*ast.TypeSpec
+}
+
+// parse parses the package with the supplied import path.
+func parse(path string) (*source, error) {
+	pkg, err := build.Import(path, ".", build.FindOnly)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot find %s", path)
+	}
+
+	pkgs, err := parser.ParseDir(token.NewFileSet(), pkg.Dir, nil, parser.ParseComments)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot parse %s", path)
+	}
+
+	s := &source{types: map[string]*ast.TypeSpec{}}
+	for _, p := range pkgs {
+		for _, f := range p.Files {
+			for _, d := range f.Decls {
+				g, ok := d.(*ast.GenDecl)
+				if !ok || g.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range g.Specs {
+					t := spec.(*ast.TypeSpec)
+					s.types[t.Name.Name] = t
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// structType returns the struct type with the supplied name.
+func (s *source) structType(name string) (*ast.StructType, error) {
+	t, ok := s.types[name]
+	if !ok {
+		return nil, errors.Errorf("type %s does not exist", name)
+	}
+	st, ok := t.Type.(*ast.StructType)
+	if !ok {
+		return nil, errors.Errorf("type %s is not a struct", name)
+	}
+	return st, nil
+}
+
+// fieldType returns the type of the field with the supplied name of the
+// struct type with the supplied name.
+func (s *source) fieldType(typ, field string) (string, error) {
+	st, err := s.structType(typ)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range st.Fields.List {
+		for _, n := range f.Names {
+			if n.Name == field {
+				return types.ExprString(f.Type), nil
+			}
+		}
+	}
+	return "", errors.Errorf("type %s has no field %s", typ, field)
+}
+
+// results returns the number of results of the method with the supplied name
+// of the interface type with the supplied name.
+func (s *source) results(typ, method string) (int, error) {
+	t, ok := s.types[typ]
+	if !ok {
+		return 0, errors.Errorf("type %s does not exist", typ)
+	}
+	it, ok := t.Type.(*ast.InterfaceType)
+	if !ok {
+		return 0, errors.Errorf("type %s is not an interface", typ)
+	}
+	for _, m := range it.Methods.List {
+		for _, n := range m.Names {
+			if n.Name == method {
+				return m.Type.(*ast.FuncType).Results.NumFields(), nil
+			}
+		}
+	}
+	return 0, errors.Errorf("interface %s has no method %s", typ, method)
+}
+
+// A modelField is a field of a model of the ZPA API.
+type modelField struct {
+	Name      string
+	JSON      string
+	Type      string
+	OmitEmpty bool
+	Required  bool
+	Enum      []string
+	Doc       []string
+}
+
+// modelFields returns the fields of the model with the supplied name.
+func (s *source) modelFields(model string) ([]modelField, error) {
+	st, err := s.structType(model)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]modelField, 0, len(st.Fields.List))
+	for _, f := range st.Fields.List {
+		if len(f.Names) != 1 || f.Tag == nil {
+			continue
+		}
+		tag := strings.Split(reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("json"), ",")
+		mf := modelField{
+			Name:      f.Names[0].Name,
+			JSON:      tag[0],
+			Type:      types.ExprString(f.Type),
+			OmitEmpty: contains(tag[1:], "omitempty"),
+		}
+		parseDoc(&mf, f.Doc)
+		fields = append(fields, mf)
+	}
+	return fields, nil
+}
+
+// validations are the validations go-swagger appends to the description of a
+// field.
+var validations = map[string]bool{
+	"Required": true, "Enum": true, "Format": true, "Read Only": true, "Example": true, "Pattern": true,
+	"Maximum": true, "Minimum": true, "Max Length": true, "Min Length": true, "Max Items": true, "Min Items": true,
+}
+
+// parseDoc sets the description of the supplied field, and the validations
+// that apply to it, from the supplied comment.
+func parseDoc(f *modelField, doc *ast.CommentGroup) {
+	if doc == nil {
+		return
+	}
+	for _, l := range strings.Split(doc.Text(), "\n") {
+		l = strings.TrimSpace(l)
+		key, value, _ := strings.Cut(l, ": ")
+		switch {
+		case key == "Required":
+			f.Required = value == "true"
+		case key == "Enum":
+			f.Enum = strings.Fields(strings.Trim(value, "[]"))
+		case validations[key]:
+		case l != "":
+			f.Doc = append(f.Doc, l)
+		}
+	}
+}
+
+// An operation of the ZPA API client.
+type operation struct {
+	Name string
+
+	// ID is the parameter that holds the ID of the object.
+	ID string
+
+	// Body is the parameter that holds the object.
+	Body string
+
+	// Results is the number of results of the operation.
+	Results int
+}
+
+// operation returns the operation with the supplied name of the client
+// package. Its parameters are looked up by type: the ID is the only string
+// parameter besides the CustomerID, the body the only one of the model type.
+func (s *source) operation(name, model string, id, body bool) (operation, error) {
+	o := operation{Name: name}
+	st, err := s.structType(name + "Params")
+	if err != nil {
+		return o, err
+	}
+
+	for _, f := range st.Fields.List {
+		if len(f.Names) != 1 || !f.Names[0].IsExported() {
+			continue
+		}
+		n, t := f.Names[0].Name, types.ExprString(f.Type)
+		switch {
+		case id && t == "string" && n != "CustomerID":
+			if o.ID != "" {
+				return o, errors.Errorf("%s has more than one ID parameter", name)
+			}
+			o.ID = n
+		case body && t == "*models."+model:
+			o.Body = n
+		}
+	}
+	if id && o.ID == "" {
+		return o, errors.Errorf("%s has no ID parameter", name)
+	}
+	if body && o.Body == "" {
+		return o, errors.Errorf("%s has no %s parameter", name, model)
+	}
+
+	o.Results, err = s.results("ClientService", name)
+	return o, err
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"strings"
+	"text/template"
+)
+
+var funcs = template.FuncMap{
+	"lower": lower,
+	"join":  strings.Join,
+}
+
+// typesTemplate renders the API types of a kind.
+var typesTemplate = template.Must(template.New("types").Funcs(funcs).Parse(`
+// {{ .Kind }}Parameters are the configurable fields of a {{ .Kind }}.
+type {{ .Kind }}Parameters struct {
+	Custom{{ .Kind }}Parameters ` + "`" + `json:",inline"` + "`" + `
+{{ range .Params }}
+	{{- $param := .ParamJSON }}
+	{{- range .Doc }}
+	// {{ . }}
+	{{- end }}
+	{{- if .Enum }}
+	// +kubebuilder:validation:Enum={{ join .Enum ";" }}
+	{{- end }}
+	{{- if .Required }}
+	// +kubebuilder:validation:Required
+	{{- end }}
+	{{- with .Ref }}
+	// +crossplane:generate:reference:type={{ .Type }}
+	// +crossplane:generate:reference:refFieldName={{ .RefField }}
+	// +crossplane:generate:reference:selectorFieldName={{ .SelectorField }}
+	{{- end }}
+	{{ .Param }} {{ .ParamType }} {{ .Tag }}
+	{{ with .Ref }}
+	// {{ .RefField }} {{ if .Slice }}are references to {{ .Kind }}s{{ else }}is a reference to a {{ .Kind }}{{ end }} used to set {{ $param }}.
+	// +optional
+	{{ .RefField }} {{ if .Slice }}[]xpv1.Reference{{ else }}*xpv1.Reference{{ end }} ` + "`" + `json:"{{ lower .RefField }},omitempty"` + "`" + `
+
+	// {{ .SelectorField }} selects references to {{ .Kind }}s.
+	// +optional
+	{{ .SelectorField }} *xpv1.Selector ` + "`" + `json:"{{ lower .SelectorField }},omitempty"` + "`" + `
+	{{ end }}
+{{- end }}
+}
+
+// A {{ .Kind }}Spec defines the desired state of a {{ .Kind }}.
+type {{ .Kind }}Spec struct {
+	xpv1.ResourceSpec ` + "`" + `json:",inline"` + "`" + `
+	ForProvider       {{ .Kind }}Parameters ` + "`" + `json:"forProvider"` + "`" + `
+}
+
+// A {{ .Kind }}Status represents the status of a {{ .Kind }}.
+type {{ .Kind }}Status struct {
+	xpv1.ResourceStatus ` + "`" + `json:",inline"` + "`" + `
+	AtProvider          Observation ` + "`" + `json:"atProvider,omitempty"` + "`" + `
+}
+
+// Observation are the observable fields of a {{ .Kind }}.
+type Observation struct {
+{{- range .Fields }}
+	{{ .Param }} {{ .ObservationType }} ` + "`" + `json:"{{ .ParamJSON }},omitempty"` + "`" + `
+{{- end }}
+
+	// LastDrift is the last difference detected between the desired state
+	// and the object in ZPA.
+	// +optional
+	LastDrift *zpav1alpha1.Drift ` + "`" + `json:"lastDrift,omitempty"` + "`" + `
+}
+
+// +kubebuilder:object:root=true
+
+// A {{ .Kind }} is the schema for ZPA {{ .Kind }}s API
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,zpa}
+type {{ .Kind }} struct {
+	metav1.TypeMeta   ` + "`" + `json:",inline"` + "`" + `
+	metav1.ObjectMeta ` + "`" + `json:"metadata,omitempty"` + "`" + `
+
+	Spec   {{ .Kind }}Spec   ` + "`" + `json:"spec"` + "`" + `
+	Status {{ .Kind }}Status ` + "`" + `json:"status,omitempty"` + "`" + `
+}
+
+// +kubebuilder:object:root=true
+
+// {{ .Kind }}List contains a list of {{ .Kind }}
+type {{ .Kind }}List struct {
+	metav1.TypeMeta ` + "`" + `json:",inline"` + "`" + `
+	metav1.ListMeta ` + "`" + `json:"metadata,omitempty"` + "`" + `
+	Items           []{{ .Kind }} ` + "`" + `json:"items"` + "`" + `
+}
+`))
+
+// controllerTemplate renders the Kind of a kind, i.e. the conversion of its
+// resources to and from the ZPA API, their comparison and the calls of the
+// ZPA API client.
+var controllerTemplate = template.Must(template.New("controller").Funcs(funcs).Parse(`
+{{- if .Generated "Setup" }}
+// Setup{{ .Kind }} adds a controller that reconciles {{ .Kind }}s.
+func Setup{{ .Kind }}(mgr ctrl.Manager, o controller.Options) error {
+	return adapter.Setup[*v1alpha1.{{ .Kind }}, *models.{{ .Model }}](mgr, o, kind{}, v1alpha1.{{ .Kind }}GroupVersionKind, &v1alpha1.{{ .Kind }}{})
+}
+{{ end }}
+// NewKind returns the kind that reconciles {{ .Kind }}s.
+func NewKind() adapter.Kind[*v1alpha1.{{ .Kind }}, *models.{{ .Model }}] {
+	return kind{}
+}
+
+// kind reconciles {{ .Kind }}s with ZPA {{ .Model }}s.
+type kind struct{}
+
+{{ if .Generated "Kind" -}}
+func (kind) Kind() string { return v1alpha1.{{ .Kind }}Kind }
+{{ end }}
+{{- if .Generated "Fields" }}
+// Fields are the JSON names in the ZPA API of the fields Update sets from the
+// parameters of a {{ .Kind }}.
+func (kind) Fields() adapter.Fields {
+	return adapter.Fields{
+		Modelled: []string{
+		{{- range .Params }}
+			"{{ .JSON }}",
+		{{- end }}
+		},
+		{{- with .Renamed }}
+		Renamed: map[string]string{
+		{{- range $param, $field := . }}
+			"{{ $param }}": "{{ $field }}",
+		{{- end }}
+		},
+		{{- end }}
+	}
+}
+{{ end }}
+{{- if .Generated "ToAPI" }}
+func (kind) ToAPI(_ context.Context, _ *adapter.Client, cr *v1alpha1.{{ .Kind }}) (*models.{{ .Model }}, error) {
+	p := cr.Spec.ForProvider
+	obj := &models.{{ .Model }}{
+	{{- range .Params }}
+		{{ .Name }}: {{ .ToAPI "p" }},
+	{{- end }}
+	}
+	{{- range .Params }}
+	{{- if and (eq .Type "[]string") (not .OmitEmpty) }}
+	if obj.{{ .Name }} == nil {
+		obj.{{ .Name }} = make([]string, 0)
+	}
+	{{- end }}
+	{{- end }}
+	return obj, nil
+}
+{{ end }}
+{{- if .Generated "FromAPI" }}
+func (kind) FromAPI(cr *v1alpha1.{{ .Kind }}, obj *models.{{ .Model }}) {
+	cr.Status.AtProvider = generateObservation(obj)
+}
+{{ end }}
+{{- if .Generated "LateInitialize" }}
+func (kind) LateInitialize(cr *v1alpha1.{{ .Kind }}, obj *models.{{ .Model }}) {
+	{{- range .Params }}
+	{{- if .LateInit }}
+	if {{ .Unset "cr.Spec.ForProvider" }} {
+		cr.Spec.ForProvider.{{ .Param }} = {{ .FromAPI "obj" }}
+	}
+	{{- end }}
+	{{- end }}
+}
+{{ end }}
+{{- if .Generated "Compare" }}
+func (kind) Compare(cr *v1alpha1.{{ .Kind }}, obj *models.{{ .Model }}) adapter.Comparison {
+	return adapter.Comparison{
+		Drifted:  driftedFields(&cr.Spec.ForProvider, obj),
+		Desired:  &cr.Spec.ForProvider,
+		Observed: observedParameters(obj),
+		{{- if .Modified }}
+		ModifiedBy:   obj.ModifiedBy,
+		ModifiedTime: obj.ModifiedTime,
+		{{- end }}
+	}
+}
+{{ end }}
+{{- if .Generated "LastDrift" }}
+func (kind) LastDrift(cr *v1alpha1.{{ .Kind }}) *zpav1alpha1.Drift {
+	return cr.Status.AtProvider.LastDrift
+}
+{{ end }}
+{{- if .Generated "SetLastDrift" }}
+func (kind) SetLastDrift(cr *v1alpha1.{{ .Kind }}, d *zpav1alpha1.Drift) {
+	cr.Status.AtProvider.LastDrift = d
+}
+{{ end }}
+{{- if .Generated "Get" }}
+func (kind) Get(ctx context.Context, c *adapter.Client, id string) (*models.{{ .Model }}, error) {
+	req := &{{ .Client }}.{{ .Get.Name }}Params{
+		Context:    ctx,
+		{{ .Get.ID }}: id,
+		CustomerID: c.CustomerID,
+	}
+	resp, err := c.{{ .ClientField }}.{{ .Get.Name }}(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Payload, nil
+}
+{{ end }}
+{{- if .Generated "List" }}
+func (kind) List(ctx context.Context, c *adapter.Client, name string) ([]zpaclient.NamedObject, error) {
+	objs := make([]zpaclient.NamedObject, 0)
+	for page := int32(1); ; page++ {
+		req := &{{ .Client }}.{{ .List.Name }}Params{
+			Context:    ctx,
+			CustomerID: c.CustomerID,
+			Page:       page,
+			Pagesize:   zpaclient.ListPageSize,
+			Search:     name,
+		}
+		resp, err := c.{{ .ClientField }}.{{ .List.Name }}(req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range resp.Payload.List {
+			objs = append(objs, zpaclient.NamedObject{ID: obj.{{ .IDField }}, Name: {{ if .NamePointer }}zpaclient.StringValue(obj.Name){{ else }}obj.Name{{ end }}})
+		}
+
+		if page >= resp.Payload.TotalPages {
+			break
+		}
+	}
+
+	return objs, nil
+}
+{{ end }}
+{{- if .Generated "Create" }}
+func (kind) Create(ctx context.Context, c *adapter.Client, obj *models.{{ .Model }}) (string, error) {
+	req := &{{ .Client }}.{{ .Create.Name }}Params{
+		Context:    ctx,
+		CustomerID: c.CustomerID,
+		{{ .Create.Body }}: obj,
+	}
+	resp, err := c.{{ .ClientField }}.{{ .Create.Name }}(req)
+	if err != nil {
+		return "", err
+	}
+	return resp.Payload.{{ .IDField }}, nil
+}
+{{ end }}
+{{- if .Generated "Update" }}
+func (kind) Update(ctx context.Context, c *adapter.Client, id string, obj *models.{{ .Model }}) error {
+	req := &{{ .Client }}.{{ .Update.Name }}Params{
+		Context:    ctx,
+		CustomerID: c.CustomerID,
+		{{ .Update.ID }}: id,
+		{{ .Update.Body }}: obj,
+	}
+	{{ if eq .Update.Results 3 }}_, _, err{{ else }}_, err{{ end }} := c.{{ .ClientField }}.{{ .Update.Name }}(req)
+	return err
+}
+{{ end }}
+{{- if .Generated "Delete" }}
+func (kind) Delete(ctx context.Context, c *adapter.Client, _ *v1alpha1.{{ .Kind }}, id string) error {
+	req := &{{ .Client }}.{{ .Delete.Name }}Params{
+		Context:    ctx,
+		{{ .Delete.ID }}: id,
+		CustomerID: c.CustomerID,
+	}
+	_, err := c.{{ .ClientField }}.{{ .Delete.Name }}(req)
+	return err
+}
+{{ end }}
+{{- if .Generated "Import" }}
+func (kind) Import(obj *models.{{ .Model }}) *v1alpha1.{{ .Kind }} {
+	cr := &v1alpha1.{{ .Kind }}{Spec: v1alpha1.{{ .Kind }}Spec{ForProvider: *observedParameters(obj)}}
+	meta.SetExternalName(cr, obj.{{ .IDField }})
+	return cr
+}
+{{ end }}
+// generateObservation generates observation for the input object models.{{ .Model }}
+func generateObservation(obj *models.{{ .Model }}) v1alpha1.Observation {
+	return v1alpha1.Observation{
+	{{- range .Fields }}
+		{{ .Param }}: {{ .Observe "obj" }},
+	{{- end }}
+	}
+}
+
+// observedParameters returns the parameters of the supplied ZPA object.
+func observedParameters(obj *models.{{ .Model }}) *v1alpha1.{{ .Kind }}Parameters {
+	return &v1alpha1.{{ .Kind }}Parameters{
+	{{- range .Params }}
+		{{ .Param }}: {{ .FromAPI "obj" }},
+	{{- end }}
+	}
+}
+
+// driftedFields returns the modifiable fields whose desired value differs from
+// the value in ZPA.
+func driftedFields(p *v1alpha1.{{ .Kind }}Parameters, obj *models.{{ .Model }}) []string { // nolint:gocyclo
+	fields := make([]string, 0)
+	{{- range .Params }}
+	{{- if .Drift }}
+
+	if {{ .Differs "p" "obj" }} {
+		fields = append(fields, "{{ .ParamJSON }}")
+	}
+	{{- end }}
+	{{- end }}
+
+	return fields
+}
+`))
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Condition types of ZPA managed resources in addition to the ones defined by
+// crossplane-runtime.
+const (
+	// TypeDeletionBlocked indicates whether the deletion of a managed
+	// resource waits for other objects that still depend on it.
+	TypeDeletionBlocked xpv1.ConditionType = "DeletionBlocked"
+
+	// TypeHealthy indicates whether the servers behind a managed resource
+	// are reachable through ZPA.
+	TypeHealthy xpv1.ConditionType = "Healthy"
+)
+
+// Reasons of the Synced condition of a managed resource that is not synced
+// with ZPA, which are more specific than ReconcileError.
+const (
+	// ReasonNameConflict indicates that the object of the managed resource
+	// cannot be created because another object with the same name exists.
+	ReasonNameConflict xpv1.ConditionReason = "NameConflict"
+
+	// ReasonNotFoundByName indicates that the managed resource imports by
+	// name, but no object has its name.
+	ReasonNotFoundByName xpv1.ConditionReason = "NotFoundByName"
+
+	// ReasonAmbiguousName indicates that the managed resource imports by
+	// name, but more than one object has its name.
+	ReasonAmbiguousName xpv1.ConditionReason = "AmbiguousName"
+
+	// The other reasons indicate the class of the error ZPA returned.
+	ReasonNotFound       xpv1.ConditionReason = "NotFound"
+	ReasonConflict       xpv1.ConditionReason = "Conflict"
+	ReasonInvalidRequest xpv1.ConditionReason = "InvalidRequest"
+	ReasonNotAuthorized  xpv1.ConditionReason = "NotAuthorized"
+	ReasonThrottled      xpv1.ConditionReason = "Throttled"
+)
+
+var errorClassReasons = map[ErrorClass]xpv1.ConditionReason{
+	ErrorClassNotFound:   ReasonNotFound,
+	ErrorClassConflict:   ReasonConflict,
+	ErrorClassValidation: ReasonInvalidRequest,
+	ErrorClassAuth:       ReasonNotAuthorized,
+	ErrorClassThrottled:  ReasonThrottled,
+}
+
+// Reason returns the reason of the Synced condition of a managed resource
+// whose reconcile failed with the supplied error. It is empty for errors
+// that were not returned by the ZPA API, which keep the reason
+// ReconcileError.
+func Reason(err error) xpv1.ConditionReason {
+	switch {
+	case IsNameConflict(err):
+		return ReasonNameConflict
+	case IsNotFoundByName(err):
+		return ReasonNotFoundByName
+	case IsAmbiguousName(err):
+		return ReasonAmbiguousName
+	}
+	return errorClassReasons[Classify(err)]
+}
+
+// Reasons the deletion of a managed resource is blocked.
+const (
+	ReasonReferencedByPolicyRules xpv1.ConditionReason = "ReferencedByPolicyRules"
+	ReasonHasDependents           xpv1.ConditionReason = "HasDependents"
+	ReasonNotBlocked              xpv1.ConditionReason = "NotBlocked"
+)
+
+// Reasons a managed resource is or is not healthy.
+const (
+	ReasonAvailable        xpv1.ConditionReason = "Available"
+	ReasonUnavailable      xpv1.ConditionReason = "Unavailable"
+	ReasonHealthCheckError xpv1.ConditionReason = "HealthCheckError"
+)
+
+// ReferencedByPolicyRules returns a condition that indicates the managed
+// resource cannot be deleted because the supplied policy rules reference it.
+func ReferencedByPolicyRules(rules []NamedObject) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionBlocked,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonReferencedByPolicyRules,
+		Message:            "referenced by policy rules " + describe(rules),
+	}
+}
+
+// HasDependents returns a condition that indicates the managed resource is
+// not deleted because the supplied dependents still use it.
+func HasDependents(dependents []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionBlocked,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHasDependents,
+		Message:            "still used by " + strings.Join(dependents, ", "),
+	}
+}
+
+// DeletionNotBlocked returns a condition that indicates nothing blocks the
+// deletion of the managed resource anymore.
+func DeletionNotBlocked() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionBlocked,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotBlocked,
+	}
+}
+
+// UnblockDeletion sets the DeletionBlocked condition of the supplied managed
+// resource to false if its deletion was blocked, so that a later failure to
+// delete it is not mistaken for the stale blocker.
+func UnblockDeletion(cr resource.Conditioned) {
+	if cr.GetCondition(TypeDeletionBlocked).Status == corev1.ConditionTrue {
+		cr.SetConditions(DeletionNotBlocked())
+	}
+}
+
+// DescribeDependents returns a human readable description of the supplied
+// managed resources and ZPA objects of the supplied kind that depend on a
+// managed resource. ZPA objects that belong to one of the managed resources
+// are described only once.
+func DescribeDependents(kind string, mgs []resource.Managed, objs []NamedObject) []string {
+	out := make([]string, 0, len(mgs)+len(objs))
+	managed := make(map[string]bool, len(mgs))
+	for _, mg := range mgs {
+		out = append(out, fmt.Sprintf("%s %s", kind, mg.GetName()))
+		managed[meta.GetExternalName(mg)] = true
+	}
+	for _, o := range objs {
+		if !managed[o.ID] {
+			out = append(out, fmt.Sprintf("ZPA %s %q (%s)", kind, o.Name, o.ID))
+		}
+	}
+	return out
+}
+
+// DescribePolicyRules describes the supplied policy rules as dependents.
+func DescribePolicyRules(rules []NamedObject) []string {
+	out := make([]string, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, fmt.Sprintf("policy rule %q (%s)", r.Name, r.ID))
+	}
+	return out
+}
+
+// SameProviderConfig returns whether the supplied managed resources use the
+// same ProviderConfig, i.e. whether their objects are in the same ZPA tenant.
+func SameProviderConfig(a, b resource.Managed) bool {
+	return providerConfigName(a) == providerConfigName(b)
+}
+
+func providerConfigName(mg resource.Managed) string {
+	if ref := mg.GetProviderConfigReference(); ref != nil {
+		return ref.Name
+	}
+	return ""
+}
+
+// describe returns a human readable list of the supplied objects.
+func describe(objs []NamedObject) string {
+	s := make([]string, len(objs))
+	for i, o := range objs {
+		s[i] = fmt.Sprintf("%q (%s)", o.Name, o.ID)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Healthy returns a condition that indicates the servers behind the managed
+// resource are reachable through ZPA.
+func Healthy() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAvailable,
+	}
+}
+
+// Unhealthy returns a condition that indicates the servers behind the
+// managed resource are not reachable through ZPA for the supplied reasons.
+func Unhealthy(reasons []string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnavailable,
+		Message:            strings.Join(reasons, "; "),
+	}
+}
+
+// HealthUnknown returns a condition that indicates the health of the managed
+// resource could not be determined.
+func HealthUnknown(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonHealthCheckError,
+		Message:            err.Error(),
+	}
+}
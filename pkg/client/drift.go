@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+)
+
+const (
+	// MaxDriftFields is the maximum number of fields stored in a Drift.
+	MaxDriftFields = 20
+
+	// maxDriftValueLength caps the length of the values stored in a Drift.
+	maxDriftValueLength = 128
+)
+
+// diffOptions make Diff treat values as equal that the ZPA API does not
+// distinguish, e.g. unset and false.
+var diffOptions = []cmp.Option{
+	cmp.Transformer("BoolValue", BoolValue),
+	cmp.Transformer("StringValue", StringValue),
+	cmpopts.SortSlices(func(a, b string) bool { return a < b }),
+	cmpopts.EquateEmpty(),
+}
+
+// Diff returns the differences between the desired and the observed
+// parameters of a managed resource. Only differences within the supplied top
+// level fields, named by their JSON name, are returned.
+func Diff(desired, observed interface{}, fields []string, opts ...cmp.Option) []v1alpha1.FieldDrift {
+	r := &diffReporter{fields: make(map[string]bool, len(fields))}
+	for _, f := range fields {
+		r.fields[f] = true
+	}
+
+	cmp.Equal(desired, observed, append(append([]cmp.Option{cmp.Reporter(r)}, diffOptions...), opts...)...)
+
+	// Report fields that are considered different even though cmp did not
+	// find a difference, e.g. because they are compared case insensitive.
+	for _, f := range fields {
+		if !r.reported[f] {
+			r.diffs = append(r.diffs, v1alpha1.FieldDrift{Path: f})
+		}
+	}
+	return r.diffs
+}
+
+// NewDrift returns a Drift of the supplied differences that is bounded to
+// MaxDriftFields fields.
+func NewDrift(diffs []v1alpha1.FieldDrift, modifiedBy, modifiedTime string) *v1alpha1.Drift {
+	d := &v1alpha1.Drift{
+		DetectedTime: metav1.Now(),
+		ModifiedBy:   modifiedBy,
+		ModifiedTime: modifiedTime,
+		Fields:       diffs,
+	}
+	if len(d.Fields) > MaxDriftFields {
+		d.Fields, d.Truncated = d.Fields[:MaxDriftFields], true
+	}
+	return d
+}
+
+// ReportDrift returns the drift to store in the status of the supplied managed
+// resource and records an event describing it if it differs from the last
+// drift. The last drift is returned if the object did not change since, so
+// that its DetectedTime is kept and no event is recorded while the same drift
+// persists.
+func ReportDrift(r event.Recorder, mg resource.Managed, last, d *v1alpha1.Drift) *v1alpha1.Drift {
+	if last != nil && last.ModifiedTime == d.ModifiedTime && last.Truncated == d.Truncated && cmp.Equal(last.Fields, d.Fields) {
+		return last
+	}
+	r.Event(mg, DriftDetected(d))
+	return d
+}
+
+// diffReporter collects the differences found by cmp.
+type diffReporter struct {
+	fields   map[string]bool
+	reported map[string]bool
+	path     cmp.Path
+	diffs    []v1alpha1.FieldDrift
+}
+
+func (r *diffReporter) PushStep(ps cmp.PathStep) {
+	r.path = append(r.path, ps)
+}
+
+func (r *diffReporter) PopStep() {
+	r.path = r.path[:len(r.path)-1]
+}
+
+func (r *diffReporter) Report(rs cmp.Result) {
+	if rs.Equal() {
+		return
+	}
+
+	top, path := fieldPath(r.path)
+	if !r.fields[top] {
+		return
+	}
+	if r.reported == nil {
+		r.reported = make(map[string]bool)
+	}
+	r.reported[top] = true
+
+	vx, vy := r.path.Last().Values()
+	r.diffs = append(r.diffs, v1alpha1.FieldDrift{
+		Path:     path,
+		Desired:  formatValue(vx),
+		Observed: formatValue(vy),
+	})
+}
+
+// fieldPath returns the JSON name of the top level field and the JSON path
+// of the supplied path, e.g. serverGroups and serverGroups[0].
+func fieldPath(p cmp.Path) (string, string) {
+	top := ""
+	b := &strings.Builder{}
+	for i, ps := range p {
+		switch s := ps.(type) {
+		case cmp.StructField:
+			name := jsonName(p[i-1].Type(), s.Name())
+			if name == "" {
+				continue
+			}
+			if top == "" {
+				top = name
+			}
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(name)
+		case cmp.SliceIndex:
+			k, ky := s.SplitKeys()
+			if k < 0 {
+				k = ky
+			}
+			fmt.Fprintf(b, "[%d]", k)
+		case cmp.MapIndex:
+			fmt.Fprintf(b, "[%v]", s.Key())
+		}
+	}
+	return top, b.String()
+}
+
+// jsonName returns the JSON name of the named field of the supplied struct
+// type, or an empty string for inlined fields.
+func jsonName(t reflect.Type, field string) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	f, ok := t.FieldByName(field)
+	if !ok {
+		return field
+	}
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" && f.Anonymous {
+		return ""
+	}
+	if name == "" {
+		return field
+	}
+	return name
+}
+
+func formatValue(v reflect.Value) string {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || !v.CanInterface() {
+		return ""
+	}
+	s := fmt.Sprint(v.Interface())
+	if len(s) > maxDriftValueLength {
+		s = s[:maxDriftValueLength] + "..."
+	}
+	return s
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+)
+
+type server struct {
+	Port    int    `json:"port,omitempty"`
+	Address string `json:"address,omitempty"`
+}
+
+type parameters struct {
+	Name         string   `json:"name"`
+	Description  *string  `json:"description,omitempty"`
+	Enabled      *bool    `json:"enabled,omitempty"`
+	Server       server   `json:"server,omitempty"`
+	ServerGroups []string `json:"serverGroups,omitempty"`
+}
+
+func TestDiff(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		desired  parameters
+		observed parameters
+		fields   []string
+		opts     []cmp.Option
+		want     []v1alpha1.FieldDrift
+	}{
+		"Equal": {
+			reason:   "Equal parameters should not differ.",
+			desired:  parameters{Name: "example"},
+			observed: parameters{Name: "example"},
+		},
+		"Value": {
+			reason:   "A differing field should be reported by its JSON name with both values.",
+			desired:  parameters{Name: "example"},
+			observed: parameters{Name: "other"},
+			fields:   []string{"name"},
+			want:     []v1alpha1.FieldDrift{{Path: "name", Desired: "example", Observed: "other"}},
+		},
+		"OtherFields": {
+			reason:   "Differences of fields that were not supplied should not be reported.",
+			desired:  parameters{Name: "example", Description: String("desired")},
+			observed: parameters{Name: "other", Description: String("observed")},
+			fields:   []string{"description"},
+			want:     []v1alpha1.FieldDrift{{Path: "description", Desired: "desired", Observed: "observed"}},
+		},
+		"Nested": {
+			reason:   "A differing nested field should be reported by its JSON path.",
+			desired:  parameters{Server: server{Port: 443, Address: "10.0.0.1"}},
+			observed: parameters{Server: server{Port: 8443, Address: "10.0.0.1"}},
+			fields:   []string{"server"},
+			want:     []v1alpha1.FieldDrift{{Path: "server.port", Desired: "443", Observed: "8443"}},
+		},
+		"SliceOrder": {
+			reason:   "Slices that only differ in their order should not differ.",
+			desired:  parameters{ServerGroups: []string{"1", "2"}},
+			observed: parameters{ServerGroups: []string{"2", "1"}},
+		},
+		"Slice": {
+			reason:   "A differing element of a slice should be reported by its index.",
+			desired:  parameters{ServerGroups: []string{"1", "2"}},
+			observed: parameters{ServerGroups: []string{"1", "3"}},
+			fields:   []string{"serverGroups"},
+			want:     []v1alpha1.FieldDrift{{Path: "serverGroups[1]", Desired: "2", Observed: "3"}},
+		},
+		"UnsetAndZero": {
+			reason:   "Unset values should not differ from false, empty strings and empty slices.",
+			desired:  parameters{Enabled: Bool(false), Description: String(""), ServerGroups: []string{}},
+			observed: parameters{},
+		},
+		"NotFoundByCmp": {
+			reason:   "A supplied field that is only equal to cmp, e.g. because it is compared case insensitive, should be reported without values.",
+			desired:  parameters{Name: "Example"},
+			observed: parameters{Name: "example"},
+			fields:   []string{"name"},
+			opts:     []cmp.Option{cmp.Comparer(strings.EqualFold)},
+			want:     []v1alpha1.FieldDrift{{Path: "name"}},
+		},
+		"LongValue": {
+			reason:   "Long values should be truncated.",
+			desired:  parameters{Name: strings.Repeat("a", maxDriftValueLength+1)},
+			observed: parameters{Name: "example"},
+			fields:   []string{"name"},
+			want:     []v1alpha1.FieldDrift{{Path: "name", Desired: strings.Repeat("a", maxDriftValueLength) + "...", Observed: "example"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Diff(tc.desired, tc.observed, tc.fields, tc.opts...)
+			if diff := cmp.Diff(tc.want, got, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nDiff(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNewDrift(t *testing.T) {
+	diffs := func(n int) []v1alpha1.FieldDrift {
+		d := make([]v1alpha1.FieldDrift, n)
+		for i := range d {
+			d[i] = v1alpha1.FieldDrift{Path: fmt.Sprintf("field%d", i)}
+		}
+		return d
+	}
+
+	cases := map[string]struct {
+		reason string
+		diffs  []v1alpha1.FieldDrift
+		want   *v1alpha1.Drift
+	}{
+		"Bounded": {
+			reason: "A drift of at most MaxDriftFields fields should keep all of them.",
+			diffs:  diffs(MaxDriftFields),
+			want:   &v1alpha1.Drift{ModifiedBy: "admin", ModifiedTime: "1650000000", Fields: diffs(MaxDriftFields)},
+		},
+		"Truncated": {
+			reason: "A drift of more than MaxDriftFields fields should be truncated.",
+			diffs:  diffs(MaxDriftFields + 1),
+			want:   &v1alpha1.Drift{ModifiedBy: "admin", ModifiedTime: "1650000000", Fields: diffs(MaxDriftFields), Truncated: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewDrift(tc.diffs, "admin", "1650000000")
+			if got.DetectedTime.IsZero() {
+				t.Errorf("\n%s\nNewDrift(...): want DetectedTime to be set", tc.reason)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(v1alpha1.Drift{}, "DetectedTime")); diff != "" {
+				t.Errorf("\n%s\nNewDrift(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// recorder records the reasons of the events it is asked to record.
+type recorder struct {
+	reasons []event.Reason
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event)    { r.reasons = append(r.reasons, e.Reason) }
+func (r *recorder) WithAnnotations(...string) event.Recorder { return r }
+
+func TestReportDrift(t *testing.T) {
+	detected := metav1.NewTime(time.Now().Add(-time.Hour))
+	fields := []v1alpha1.FieldDrift{{Path: "name", Desired: "example", Observed: "other"}}
+	last := &v1alpha1.Drift{DetectedTime: detected, ModifiedTime: "1650000000", Fields: fields}
+
+	type want struct {
+		drift  *v1alpha1.Drift
+		events []event.Reason
+	}
+
+	cases := map[string]struct {
+		reason string
+		last   *v1alpha1.Drift
+		d      *v1alpha1.Drift
+		want   want
+	}{
+		"New": {
+			reason: "A drift without a last drift should be stored and reported.",
+			d:      &v1alpha1.Drift{ModifiedTime: "1650000000", Fields: fields},
+			want: want{
+				drift:  &v1alpha1.Drift{ModifiedTime: "1650000000", Fields: fields},
+				events: []event.Reason{ReasonDriftDetected},
+			},
+		},
+		"Same": {
+			reason: "The same drift as the last one should keep the last drift and not be reported again.",
+			last:   last,
+			d:      &v1alpha1.Drift{ModifiedTime: "1650000000", Fields: fields},
+			want:   want{drift: last},
+		},
+		"Modified": {
+			reason: "A drift of an object that was modified since the last drift should be stored and reported.",
+			last:   last,
+			d:      &v1alpha1.Drift{ModifiedTime: "1650000100", Fields: fields},
+			want: want{
+				drift:  &v1alpha1.Drift{ModifiedTime: "1650000100", Fields: fields},
+				events: []event.Reason{ReasonDriftDetected},
+			},
+		},
+		"OtherFields": {
+			reason: "A drift of other fields than the last drift should be stored and reported.",
+			last:   last,
+			d:      &v1alpha1.Drift{ModifiedTime: "1650000000", Fields: []v1alpha1.FieldDrift{{Path: "description"}}},
+			want: want{
+				drift:  &v1alpha1.Drift{ModifiedTime: "1650000000", Fields: []v1alpha1.FieldDrift{{Path: "description"}}},
+				events: []event.Reason{ReasonDriftDetected},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{}
+			got := ReportDrift(r, &fake.Managed{}, tc.last, tc.d)
+			if diff := cmp.Diff(tc.want.drift, got); diff != "" {
+				t.Errorf("\n%s\nReportDrift(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.reasons); diff != "" {
+				t.Errorf("\n%s\nReportDrift(...): -want events, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
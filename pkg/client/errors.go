@@ -0,0 +1,300 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/pkg/errors"
+)
+
+// ErrorClass classifies an error returned by the ZPA API.
+type ErrorClass string
+
+// Classes of errors returned by the ZPA API.
+const (
+	ErrorClassUnknown    ErrorClass = "Unknown"
+	ErrorClassNotFound   ErrorClass = "NotFound"
+	ErrorClassConflict   ErrorClass = "Conflict"
+	ErrorClassValidation ErrorClass = "Validation"
+	ErrorClassAuth       ErrorClass = "Auth"
+	ErrorClassThrottled  ErrorClass = "Throttled"
+)
+
+var errorClassDescriptions = map[ErrorClass]string{
+	ErrorClassUnknown:    "request failed",
+	ErrorClassNotFound:   "not found",
+	ErrorClassConflict:   "conflict",
+	ErrorClassValidation: "invalid request",
+	ErrorClassAuth:       "not authorized",
+	ErrorClassThrottled:  "throttled",
+}
+
+const (
+	// maxRetryDelay caps the delay requested by the Retry-After header.
+	maxRetryDelay = 30 * time.Second
+
+	// maxReasonLength caps the length of a reason taken from a response body
+	// that is not a ZPA error envelope.
+	maxReasonLength = 256
+)
+
+// An APIError is returned by every ZPA operation that does not succeed. It
+// carries the decoded ZPA error envelope and unwraps to the error returned
+// by the generated client, e.g. GetApplicationUsingGET1BadRequest.
+type APIError struct {
+	// Operation is the ID of the failed operation.
+	Operation string
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// ID is the ZPA error code, e.g. resource.not.found.
+	ID string
+
+	// Reason is the human readable description of the error.
+	Reason string
+
+	// Params are the parameters of the error, e.g. the offending values.
+	Params []string
+
+	// RetryAfter is the delay requested by a throttled response.
+	RetryAfter time.Duration
+
+	// Class is the classification of the error.
+	Class ErrorClass
+
+	err error
+}
+
+func (e *APIError) Error() string {
+	msg := errorClassDescriptions[e.Class]
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	if len(e.Params) > 0 {
+		msg += " [" + strings.Join(e.Params, ", ") + "]"
+	}
+	if e.ID != "" {
+		return fmt.Sprintf("%s (%s, HTTP %d)", msg, e.ID, e.StatusCode)
+	}
+	return fmt.Sprintf("%s (HTTP %d)", msg, e.StatusCode)
+}
+
+// Unwrap returns the error returned by the generated client.
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// envelope is the body ZPA returns for failed operations.
+type envelope struct {
+	ID     string   `json:"id"`
+	Reason string   `json:"reason"`
+	Params []string `json:"params"`
+}
+
+func newAPIError(operation string, resp runtime.ClientResponse, body []byte, err error) *APIError {
+	e := &APIError{
+		Operation:  operation,
+		StatusCode: resp.Code(),
+		err:        err,
+	}
+
+	env := &envelope{}
+	if json.Unmarshal(body, env) == nil && (env.ID != "" || env.Reason != "") {
+		e.ID, e.Reason, e.Params = env.ID, env.Reason, env.Params
+	} else {
+		e.Reason = strings.TrimSpace(string(body))
+		if len(e.Reason) > maxReasonLength {
+			e.Reason = e.Reason[:maxReasonLength] + "..."
+		}
+	}
+
+	if s, err := strconv.Atoi(resp.GetHeader("Retry-After")); err == nil && s > 0 {
+		e.RetryAfter = time.Duration(s) * time.Second
+	}
+
+	e.Class = classify(e.StatusCode, e.ID)
+	return e
+}
+
+// classify returns the class of an error from its HTTP status code and ZPA
+// error code. The ZPA error code takes precedence, because ZPA reports some
+// errors, e.g. missing objects, with a generic 400 status code.
+func classify(status int, id string) ErrorClass { // nolint:gocyclo
+	id = strings.ToLower(id)
+	switch {
+	case strings.Contains(id, "not.found"):
+		return ErrorClassNotFound
+	case isDuplicate(id):
+		return ErrorClassConflict
+	case strings.Contains(id, "rate.limit"), strings.Contains(id, "too.many.requests"):
+		return ErrorClassThrottled
+	}
+
+	switch status {
+	case http.StatusNotFound:
+		return ErrorClassNotFound
+	case http.StatusConflict:
+		return ErrorClassConflict
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrorClassValidation
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorClassAuth
+	case http.StatusTooManyRequests:
+		return ErrorClassThrottled
+	}
+	return ErrorClassUnknown
+}
+
+// Classify returns the class of the supplied error. Errors that were not
+// returned by the ZPA API are of class ErrorClassUnknown.
+func Classify(err error) ErrorClass {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Class
+	}
+	var rtErr *runtime.APIError
+	if errors.As(err, &rtErr) {
+		return classify(rtErr.Code, "")
+	}
+	return ErrorClassUnknown
+}
+
+// IsNotFound returns whether the supplied error reports a missing object.
+func IsNotFound(err error) bool {
+	return Classify(err) == ErrorClassNotFound
+}
+
+// IsConflict returns whether the supplied error reports a conflict with an
+// existing object.
+func IsConflict(err error) bool {
+	return Classify(err) == ErrorClassConflict
+}
+
+// IsDuplicate returns whether the supplied error reports, by its ZPA error
+// code, that an object with the same name already exists. ZPA reports such
+// errors with a 400 or a 409 status code.
+func IsDuplicate(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && isDuplicate(strings.ToLower(apiErr.ID))
+}
+
+func isDuplicate(id string) bool {
+	return strings.Contains(id, "duplicate") || strings.Contains(id, "already.exist")
+}
+
+// IsValidation returns whether the supplied error reports an invalid request.
+func IsValidation(err error) bool {
+	return Classify(err) == ErrorClassValidation
+}
+
+// IsAuth returns whether the supplied error reports missing or insufficient
+// credentials.
+func IsAuth(err error) bool {
+	return Classify(err) == ErrorClassAuth
+}
+
+// IsThrottled returns whether the supplied error reports that too many
+// requests were sent.
+func IsThrottled(err error) bool {
+	return Classify(err) == ErrorClassThrottled
+}
+
+// RetryAfter returns how long to wait before retrying an operation that
+// failed with the supplied error, as requested by a throttled response. It is
+// zero for other errors, and for throttled responses that did not tell.
+func RetryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return 0
+	}
+	return retryDelay(apiErr)
+}
+
+func retryDelay(err *APIError) time.Duration {
+	if err.Class != ErrorClassThrottled {
+		return 0
+	}
+	if err.RetryAfter > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return err.RetryAfter
+}
+
+// NewErrorDecodingTransport returns a transport that returns an *APIError for
+// every failed operation. Throttled operations are not retried, so that the
+// reconciler that sent them is not blocked; it requeues them after
+// RetryAfter instead.
+func NewErrorDecodingTransport(t runtime.ClientTransport) runtime.ClientTransport {
+	return &errorDecodingTransport{transport: t}
+}
+
+type errorDecodingTransport struct {
+	transport runtime.ClientTransport
+}
+
+// Submit sends the operation and decodes the error it fails with.
+func (t *errorDecodingTransport) Submit(op *runtime.ClientOperation) (interface{}, error) {
+	op.Reader = &errorDecodingReader{requestReader: op.Reader, operation: op.ID}
+	return t.transport.Submit(op)
+}
+
+type errorDecodingReader struct {
+	// the original request reader
+	requestReader runtime.ClientResponseReader
+
+	// the ID of the operation
+	operation string
+}
+
+// ReadResponse decodes the error envelope of failed operations and passes
+// the response on to the original reader.
+func (r *errorDecodingReader) ReadResponse(resp runtime.ClientResponse, consumer runtime.Consumer) (interface{}, error) {
+	if resp.Code() >= 200 && resp.Code() < 300 {
+		return r.requestReader.ReadResponse(resp, consumer)
+	}
+
+	body, err := io.ReadAll(resp.Body())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.requestReader.ReadResponse(&bufferedResponse{ClientResponse: resp, body: body}, consumer)
+	if err == nil {
+		return res, nil
+	}
+	return res, newAPIError(r.operation, resp, body, err)
+}
+
+// bufferedResponse replays a response body that was already read.
+type bufferedResponse struct {
+	runtime.ClientResponse
+	body []byte
+}
+
+func (r *bufferedResponse) Body() io.ReadCloser {
+	return io.NopCloser(bytes.NewReader(r.body))
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestClassify(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		status int
+		id     string
+		want   ErrorClass
+	}{
+		"NotFoundID": {
+			reason: "ZPA reports missing objects with a 400 status code and a not found error code.",
+			status: http.StatusBadRequest,
+			id:     "resource.not.found",
+			want:   ErrorClassNotFound,
+		},
+		"DuplicateID": {
+			reason: "A duplicate error code should be a conflict regardless of the status code.",
+			status: http.StatusBadRequest,
+			id:     "duplicate.item",
+			want:   ErrorClassConflict,
+		},
+		"AlreadyExistsID": {
+			reason: "An already exists error code should be a conflict.",
+			status: http.StatusBadRequest,
+			id:     "segment.group.already.exists",
+			want:   ErrorClassConflict,
+		},
+		"RateLimitID": {
+			reason: "A rate limit error code should be throttled.",
+			status: http.StatusBadRequest,
+			id:     "api.rate.limit.exceeded",
+			want:   ErrorClassThrottled,
+		},
+		"UpperCaseID": {
+			reason: "Error codes should be matched regardless of their case.",
+			status: http.StatusBadRequest,
+			id:     "RESOURCE.NOT.FOUND",
+			want:   ErrorClassNotFound,
+		},
+		"NotFound": {
+			reason: "A 404 status code should be not found.",
+			status: http.StatusNotFound,
+			want:   ErrorClassNotFound,
+		},
+		"Conflict": {
+			reason: "A 409 status code should be a conflict.",
+			status: http.StatusConflict,
+			want:   ErrorClassConflict,
+		},
+		"BadRequest": {
+			reason: "A 400 status code with an unknown error code should be a validation error.",
+			status: http.StatusBadRequest,
+			id:     "invalid.port.range",
+			want:   ErrorClassValidation,
+		},
+		"Unprocessable": {
+			reason: "A 422 status code should be a validation error.",
+			status: http.StatusUnprocessableEntity,
+			want:   ErrorClassValidation,
+		},
+		"Unauthorized": {
+			reason: "A 401 status code should be an auth error.",
+			status: http.StatusUnauthorized,
+			want:   ErrorClassAuth,
+		},
+		"Forbidden": {
+			reason: "A 403 status code should be an auth error.",
+			status: http.StatusForbidden,
+			want:   ErrorClassAuth,
+		},
+		"TooManyRequests": {
+			reason: "A 429 status code should be throttled.",
+			status: http.StatusTooManyRequests,
+			want:   ErrorClassThrottled,
+		},
+		"ServerError": {
+			reason: "A 500 status code should be unknown.",
+			status: http.StatusInternalServerError,
+			want:   ErrorClassUnknown,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := classify(tc.status, tc.id)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nclassify(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   time.Duration
+	}{
+		"Throttled": {
+			reason: "A throttled error should be retried after the delay ZPA asked for.",
+			err:    &APIError{Class: ErrorClassThrottled, RetryAfter: 5 * time.Second},
+			want:   5 * time.Second,
+		},
+		"Wrapped": {
+			reason: "A wrapped throttled error should be retried after the delay ZPA asked for.",
+			err:    errors.Wrap(&APIError{Class: ErrorClassThrottled, RetryAfter: 5 * time.Second}, "cannot update"),
+			want:   5 * time.Second,
+		},
+		"Capped": {
+			reason: "The delay ZPA asked for should be capped.",
+			err:    &APIError{Class: ErrorClassThrottled, RetryAfter: time.Hour},
+			want:   maxRetryDelay,
+		},
+		"NoRetryAfter": {
+			reason: "A throttled error without a delay should not be retried after a delay.",
+			err:    &APIError{Class: ErrorClassThrottled},
+		},
+		"NotThrottled": {
+			reason: "An error that is not throttled should not be retried after a delay.",
+			err:    &APIError{Class: ErrorClassValidation, RetryAfter: 5 * time.Second},
+		},
+		"NotAPIError": {
+			reason: "An error that was not returned by the ZPA API should not be retried after a delay.",
+			err:    errors.New("boom"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := RetryAfter(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRetryAfter(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReason(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   xpv1.ConditionReason
+	}{
+		"NameConflict": {
+			reason: "A name conflict should have reason NameConflict.",
+			err:    errors.Wrap(&NameConflictError{Kind: "Test", Name: "example"}, "cannot create"),
+			want:   ReasonNameConflict,
+		},
+		"NotFoundByName": {
+			reason: "Importing by a name no object has should have reason NotFoundByName.",
+			err:    &NotFoundByNameError{Kind: "Test", Name: "example"},
+			want:   ReasonNotFoundByName,
+		},
+		"AmbiguousName": {
+			reason: "Importing by a name several objects have should have reason AmbiguousName.",
+			err:    &AmbiguousNameError{Kind: "Test", Name: "example", IDs: []string{"1", "2"}},
+			want:   ReasonAmbiguousName,
+		},
+		"Throttled": {
+			reason: "A wrapped throttled error should have reason Throttled.",
+			err:    errors.Wrap(&APIError{Class: ErrorClassThrottled}, "cannot update"),
+			want:   ReasonThrottled,
+		},
+		"Validation": {
+			reason: "A validation error should have reason InvalidRequest.",
+			err:    &APIError{Class: ErrorClassValidation},
+			want:   ReasonInvalidRequest,
+		},
+		"Unknown": {
+			reason: "An error of an unknown class should have no reason.",
+			err:    &APIError{Class: ErrorClassUnknown},
+		},
+		"NotAPIError": {
+			reason: "An error that was not returned by the ZPA API should have no reason.",
+			err:    errors.New("boom"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Reason(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nReason(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// response is a runtime.ClientResponse.
+type response struct {
+	code   int
+	header http.Header
+	body   string
+}
+
+func (r *response) Code() int                       { return r.code }
+func (r *response) Message() string                 { return http.StatusText(r.code) }
+func (r *response) GetHeader(name string) string    { return r.header.Get(name) }
+func (r *response) GetHeaders(name string) []string { return r.header.Values(name) }
+func (r *response) Body() io.ReadCloser             { return io.NopCloser(bytes.NewBufferString(r.body)) }
+
+// transport is a runtime.ClientTransport that reads the supplied response.
+type transport struct {
+	resp  *response
+	calls int
+}
+
+func (t *transport) Submit(op *runtime.ClientOperation) (interface{}, error) {
+	t.calls++
+	return op.Reader.ReadResponse(t.resp, runtime.JSONConsumer())
+}
+
+// reader reads a response like the readers of the generated client do.
+var reader = runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, _ runtime.Consumer) (interface{}, error) {
+	if resp.Code() >= 200 && resp.Code() < 300 {
+		b, err := io.ReadAll(resp.Body())
+		return string(b), err
+	}
+	return nil, runtime.NewAPIError("getSegmentGroup", resp.Message(), resp.Code())
+})
+
+func TestErrorDecodingTransport(t *testing.T) {
+	type want struct {
+		result interface{}
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		resp   *response
+		want   want
+	}{
+		"Success": {
+			reason: "A successful response should be passed on to the reader of the operation.",
+			resp:   &response{code: http.StatusOK, body: `{"id":"1"}`},
+			want:   want{result: `{"id":"1"}`},
+		},
+		"Envelope": {
+			reason: "The error envelope of a failed operation should be decoded.",
+			resp:   &response{code: http.StatusBadRequest, body: `{"id":"invalid.port.range","reason":"port out of range","params":["0"]}`},
+			want: want{err: &APIError{
+				Operation:  "getSegmentGroup",
+				StatusCode: http.StatusBadRequest,
+				ID:         "invalid.port.range",
+				Reason:     "port out of range",
+				Params:     []string{"0"},
+				Class:      ErrorClassValidation,
+			}},
+		},
+		"PlainBody": {
+			reason: "The body of a failed operation that is not an error envelope should be the reason.",
+			resp:   &response{code: http.StatusInternalServerError, body: "upstream unavailable\n"},
+			want: want{err: &APIError{
+				Operation:  "getSegmentGroup",
+				StatusCode: http.StatusInternalServerError,
+				Reason:     "upstream unavailable",
+				Class:      ErrorClassUnknown,
+			}},
+		},
+		"Throttled": {
+			reason: "A throttled operation should fail at once with the delay ZPA asked for.",
+			resp:   &response{code: http.StatusTooManyRequests, header: http.Header{"Retry-After": []string{"5"}}},
+			want: want{err: &APIError{
+				Operation:  "getSegmentGroup",
+				StatusCode: http.StatusTooManyRequests,
+				RetryAfter: 5 * time.Second,
+				Class:      ErrorClassThrottled,
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tr := &transport{resp: tc.resp}
+			got, err := NewErrorDecodingTransport(tr).Submit(&runtime.ClientOperation{ID: "getSegmentGroup", Reader: reader})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSubmit(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nSubmit(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(RetryAfter(tc.want.err), RetryAfter(err)); diff != "" {
+				t.Errorf("\n%s\nSubmit(...): -want retry after, +got:\n%s\n", tc.reason, diff)
+			}
+			if tr.calls != 1 {
+				t.Errorf("\n%s\nSubmit(...): want 1 call, got %d", tc.reason, tr.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+)
+
+// ReasonDriftDetected is the reason of events reporting that a managed
+// resource differs from its object in ZPA.
+const ReasonDriftDetected event.Reason = "DriftDetected"
+
+// DriftDetected returns an event that describes each field whose desired
+// value differs from the value in ZPA, and who last modified the object.
+func DriftDetected(d *v1alpha1.Drift) event.Event {
+	fields := make([]string, len(d.Fields))
+	for i, f := range d.Fields {
+		fields[i] = fmt.Sprintf("%s: %q in ZPA, %q desired", f.Path, f.Observed, f.Desired)
+	}
+	if d.Truncated {
+		fields = append(fields, "...")
+	}
+
+	msg := "Fields differ from ZPA: " + strings.Join(fields, "; ")
+	if d.ModifiedBy != "" {
+		msg += fmt.Sprintf(" (last modified by %s at %s)", d.ModifiedBy, d.ModifiedTime)
+	}
+	return event.Normal(ReasonDriftDetected, msg)
+}
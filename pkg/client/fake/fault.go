@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"net/http"
+)
+
+// A Fault makes the Server fail matching requests with a ZPA error.
+type Fault struct {
+	// Method of the requests to fail, e.g. PUT. Requests of all methods
+	// fail if empty.
+	Method string
+
+	// Collection of the requests to fail, e.g. Applications. Requests to all
+	// collections fail if empty.
+	Collection string
+
+	// StatusCode of the response, e.g. 429.
+	StatusCode int
+
+	// ID is the ZPA error code of the response. Defaults to a code matching
+	// the status code, e.g. resource.not.found for 404.
+	ID string
+
+	// Reason is the description of the error.
+	Reason string
+
+	// RetryAfter is sent in the Retry-After header, in seconds, if set.
+	RetryAfter int
+
+	// Times is the number of requests to fail. Requests fail until the
+	// fault is cleared if zero.
+	Times int
+}
+
+// Faults that can be injected into a Server.
+var (
+	// BadRequest fails requests like an invalid payload does.
+	BadRequest = Fault{StatusCode: http.StatusBadRequest, ID: "invalid.request", Reason: "Invalid request"}
+
+	// NotFound fails requests like a missing object does.
+	NotFound = Fault{StatusCode: http.StatusNotFound, ID: "resource.not.found", Reason: "Resource not found"}
+
+	// TooManyRequests fails requests like the ZPA rate limit does.
+	TooManyRequests = Fault{StatusCode: http.StatusTooManyRequests, ID: "too.many.requests", Reason: "Rate limit exceeded", RetryAfter: 1}
+
+	// InternalServerError fails requests like an outage of ZPA does.
+	InternalServerError = Fault{StatusCode: http.StatusInternalServerError, ID: "internal.error", Reason: "Internal server error"}
+)
+
+// For returns a copy of the fault that fails only requests of the supplied
+// method to the supplied collection. Empty values match all.
+func (f Fault) For(method, collection string) Fault {
+	f.Method, f.Collection = method, collection
+	return f
+}
+
+// Once returns a copy of the fault that fails only the supplied number of
+// requests.
+func (f Fault) Once(times int) Fault {
+	f.Times = times
+	return f
+}
+
+func (f *Fault) matches(method, collection string) bool {
+	return (f.Method == "" || f.Method == method) && (f.Collection == "" || f.Collection == collection)
+}
+
+func (f *Fault) envelope() *envelope {
+	id, reason := f.ID, f.Reason
+	if id == "" {
+		switch f.StatusCode {
+		case http.StatusBadRequest:
+			id = BadRequest.ID
+		case http.StatusNotFound:
+			id = NotFound.ID
+		case http.StatusTooManyRequests:
+			id = TooManyRequests.ID
+		default:
+			id = InternalServerError.ID
+		}
+	}
+	if reason == "" {
+		reason = http.StatusText(f.StatusCode)
+	}
+	return &envelope{ID: id, Reason: reason}
+}
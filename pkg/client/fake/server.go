@@ -0,0 +1,601 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides an in-process fake of the ZPA API for tests.
+package fake
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	httptransport "github.com/go-openapi/runtime/client"
+
+	zpa "github.com/haarchri/zpa-go-client/pkg/client"
+
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+)
+
+// Collections of ZPA objects served by a Server, named by their path in the
+// ZPA API.
+const (
+	Applications    = "application"
+	SegmentGroups   = "segmentGroup"
+	Servers         = "server"
+	ServerGroups    = "serverGroup"
+	ConnectorGroups = "appConnectorGroup"
+)
+
+// Defaults of a Server.
+const (
+	DefaultCustomerID   = "216196257331281920"
+	DefaultClientID     = "client-id"
+	DefaultClientSecret = "client-secret"
+	DefaultModifiedBy   = "72057594037928115"
+
+	// PortalModifiedBy is the user that modifies objects in Modify.
+	PortalModifiedBy = "72057594037928116"
+)
+
+const (
+	basePath        = "/mgmtconfig/v1/admin/customers/"
+	policyRulesPath = "policySet/rules/policyType/"
+	defaultPageSize = 20
+	maxPageSize     = 500
+)
+
+// PolicyRules returns the collection of the policy rules of the supplied
+// policy type, e.g. ACCESS_POLICY.
+func PolicyRules(policyType string) string {
+	return policyRulesPath + policyType
+}
+
+// A Request is a request received by a Server.
+type Request struct {
+	Method     string
+	Collection string
+	ID         string
+}
+
+// An Option configures a Server.
+type Option func(*Server)
+
+// WithCustomerID sets the ID of the tenant served by the Server.
+func WithCustomerID(id string) Option {
+	return func(s *Server) { s.customerID = id }
+}
+
+// WithCredentials sets the credentials accepted by /signin.
+func WithCredentials(clientID, clientSecret string) Option {
+	return func(s *Server) { s.clientID, s.clientSecret = clientID, clientSecret }
+}
+
+// WithLatency delays every response of the Server.
+func WithLatency(d time.Duration) Option {
+	return func(s *Server) { s.latency = d }
+}
+
+// WithConsistencyDelay makes writes visible to reads only after the supplied
+// delay, like the eventually consistent ZPA API does.
+func WithConsistencyDelay(d time.Duration) Option {
+	return func(s *Server) { s.delay = d }
+}
+
+// A Server is an in-process fake of the ZPA API of a single tenant. It serves
+// /signin and the CRUD operations of applications, segment groups, servers,
+// server groups and connector groups from memory, and lists policy rules.
+// Like ZPA it computes the applications of segment and server groups and the
+// servers of server groups from the objects that reference them.
+type Server struct {
+	// Server is the underlying TLS test server.
+	*httptest.Server
+
+	customerID   string
+	clientID     string
+	clientSecret string
+	token        string
+
+	mu       sync.Mutex
+	now      func() time.Time
+	store    *store
+	latency  time.Duration
+	delay    time.Duration
+	faults   []*Fault
+	requests []Request
+}
+
+// NewServer starts a Server. Callers should Close it when done.
+func NewServer(o ...Option) *Server {
+	s := &Server{
+		customerID:   DefaultCustomerID,
+		clientID:     DefaultClientID,
+		clientSecret: DefaultClientSecret,
+		token:        "fake-" + strconv.FormatInt(time.Now().UnixNano(), 36),
+		now:          time.Now,
+		store:        newStore(),
+	}
+	for _, fn := range o {
+		fn(s)
+	}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Host returns the host of the Server, to be used as the host of a
+// ProviderConfig.
+func (s *Server) Host() string {
+	return s.Listener.Addr().String()
+}
+
+// CustomerID returns the ID of the tenant served by the Server.
+func (s *Server) CustomerID() string {
+	return s.customerID
+}
+
+// CABundle returns the PEM encoded certificate of the Server, to be used as
+// the caBundle of a ProviderConfig.
+func (s *Server) CABundle() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+}
+
+// Config returns a Config that connects the ZPA client to the Server.
+func (s *Server) Config() *zpaclient.Config {
+	t := httptransport.NewWithClient(s.Host(), "/", zpa.DefaultSchemes, s.Client())
+	t.DefaultAuthentication = httptransport.BearerToken(s.token)
+	return &zpaclient.Config{
+		Transport:  zpaclient.NewErrorDecodingTransport(t),
+		CustomerID: s.customerID,
+		Host:       s.Host(),
+	}
+}
+
+// SetLatency delays every following response of the Server.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetConsistencyDelay makes following writes visible to reads only after
+// the supplied delay.
+func (s *Server) SetConsistencyDelay(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = d
+}
+
+// InjectFault makes the Server fail requests matching the supplied fault.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the API requests received by the Server so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Seed adds the supplied object to the collection and returns its id. The
+// object is visible immediately; an id is assigned if it has none.
+func (s *Server) Seed(collection string, o Object) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o = o.DeepCopy()
+	if o.ID() == "" {
+		o["id"] = s.store.newID()
+	}
+	by := str(o["modifiedBy"])
+	if by == "" {
+		by = DefaultModifiedBy
+	}
+	s.stamp(o, nil, by)
+	s.store.put(s.now(), collection, o.ID(), o, 0)
+	return o.ID()
+}
+
+// Modify changes the object with the supplied id like a change in the ZPA
+// portal would. The change is visible immediately. It returns false if the
+// object does not exist.
+func (s *Server) Modify(collection, id string, fn func(Object)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.store.current(collection, id)
+	if o == nil {
+		return false
+	}
+	prev := o.DeepCopy()
+	fn(o)
+	by := str(o["modifiedBy"])
+	if by == "" || by == str(prev["modifiedBy"]) {
+		by = PortalModifiedBy
+	}
+	s.stamp(o, prev, by)
+	s.store.put(s.now(), collection, id, o, 0)
+	return true
+}
+
+// Get returns the object with the supplied id as last written, or nil if it
+// does not exist.
+func (s *Server) Get(collection, id string) Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store.current(collection, id)
+}
+
+// List returns the objects of the collection as last written.
+func (s *Server) List(collection string) []Object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store.currentList(collection)
+}
+
+// envelope is the body ZPA returns for failed operations.
+type envelope struct {
+	ID     string   `json:"id"`
+	Reason string   `json:"reason"`
+	Params []string `json:"params,omitempty"`
+}
+
+type page struct {
+	TotalPages int      `json:"totalPages"`
+	List       []Object `json:"list"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	latency := s.latency
+	s.mu.Unlock()
+	if latency > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(latency):
+		}
+	}
+
+	if r.URL.Path == "/signin" {
+		s.signin(w, r)
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+s.token {
+		writeError(w, http.StatusUnauthorized, &envelope{ID: "authn.required", Reason: "Authentication required"})
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, basePath)
+	if rest == r.URL.Path || !strings.HasPrefix(rest, s.customerID+"/") {
+		writeError(w, http.StatusNotFound, &envelope{ID: NotFound.ID, Reason: "Unknown path " + r.URL.Path})
+		return
+	}
+	rest = strings.TrimPrefix(rest, s.customerID+"/")
+
+	collection, id := rest, ""
+	if !strings.HasPrefix(rest, policyRulesPath) {
+		if i := strings.Index(rest, "/"); i >= 0 {
+			collection, id = rest[:i], rest[i+1:]
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, Request{Method: r.Method, Collection: collection, ID: id})
+
+	if f := s.fault(r.Method, collection); f != nil {
+		if f.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(f.RetryAfter))
+		}
+		writeError(w, f.StatusCode, f.envelope())
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(collection, policyRulesPath) && r.Method == http.MethodGet:
+		s.list(w, r, collection)
+	case !knownCollection(collection):
+		writeError(w, http.StatusNotFound, &envelope{ID: NotFound.ID, Reason: "Unknown path " + r.URL.Path})
+	case id == "" && r.Method == http.MethodGet:
+		s.list(w, r, collection)
+	case id == "" && r.Method == http.MethodPost:
+		s.create(w, r, collection)
+	case id != "" && r.Method == http.MethodGet:
+		s.get(w, collection, id)
+	case id != "" && r.Method == http.MethodPut:
+		s.update(w, r, collection, id)
+	case id != "" && r.Method == http.MethodDelete:
+		s.delete(w, collection, id)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, &envelope{ID: "method.not.allowed", Reason: r.Method + " is not supported"})
+	}
+}
+
+func knownCollection(c string) bool {
+	switch c {
+	case Applications, SegmentGroups, Servers, ServerGroups, ConnectorGroups:
+		return true
+	}
+	return false
+}
+
+// fault returns the first injected fault matching the request, if any.
+func (s *Server) fault(method, collection string) *Fault {
+	for i, f := range s.faults {
+		if !f.matches(method, collection) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) signin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeError(w, http.StatusBadRequest, &envelope{ID: BadRequest.ID, Reason: "Invalid sign in request"})
+		return
+	}
+	if r.PostForm.Get("client_id") != s.clientID || r.PostForm.Get("client_secret") != s.clientSecret {
+		writeError(w, http.StatusUnauthorized, &envelope{ID: "invalid.credentials", Reason: "Invalid client credentials"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"token_type":   "Bearer",
+		"access_token": s.token,
+		"expires_in":   "3600",
+	})
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, collection string) {
+	objs := s.store.list(s.now(), collection)
+
+	if search := r.URL.Query().Get("search"); search != "" {
+		matched := objs[:0]
+		for _, o := range objs {
+			if strings.Contains(strings.ToLower(o.Name()), strings.ToLower(search)) {
+				matched = append(matched, o)
+			}
+		}
+		objs = matched
+	}
+
+	size, _ := strconv.Atoi(r.URL.Query().Get("pagesize"))
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	p, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if p <= 0 {
+		p = 1
+	}
+
+	res := &page{TotalPages: (len(objs) + size - 1) / size, List: []Object{}}
+	for i := (p - 1) * size; i < len(objs) && i < p*size; i++ {
+		res.List = append(res.List, s.render(collection, objs[i]))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) get(w http.ResponseWriter, collection, id string) {
+	o := s.store.get(s.now(), collection, id)
+	if o == nil {
+		writeNotFound(w, collection, id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.render(collection, o))
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request, collection string) {
+	o, ok := s.decode(w, r, collection, "")
+	if !ok {
+		return
+	}
+	o["id"] = s.store.newID()
+	s.stamp(o, nil, DefaultModifiedBy)
+	s.store.put(s.now(), collection, o.ID(), o, s.delay)
+	writeJSON(w, http.StatusCreated, s.render(collection, o))
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, collection, id string) {
+	prev := s.store.get(s.now(), collection, id)
+	if prev == nil {
+		writeNotFound(w, collection, id)
+		return
+	}
+	o, ok := s.decode(w, r, collection, id)
+	if !ok {
+		return
+	}
+	o["id"] = id
+	s.stamp(o, prev, DefaultModifiedBy)
+	s.store.put(s.now(), collection, id, o, s.delay)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) delete(w http.ResponseWriter, collection, id string) {
+	if s.store.get(s.now(), collection, id) == nil {
+		writeNotFound(w, collection, id)
+		return
+	}
+	s.store.put(s.now(), collection, id, nil, s.delay)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode reads the object in the request body and validates it like ZPA
+// does: the name is required and unique within the collection.
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, collection, id string) (Object, bool) {
+	o := Object{}
+	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+		writeError(w, http.StatusBadRequest, &envelope{ID: "invalid.json", Reason: err.Error()})
+		return nil, false
+	}
+	if o.Name() == "" {
+		writeError(w, http.StatusBadRequest, &envelope{ID: "name.required", Reason: "Name is required"})
+		return nil, false
+	}
+	for _, other := range s.store.currentList(collection) {
+		if other.ID() != id && other.Name() == o.Name() {
+			writeError(w, http.StatusBadRequest, &envelope{
+				ID:     "duplicate.item",
+				Reason: "Name already exists",
+				Params: []string{o.Name()},
+			})
+			return nil, false
+		}
+	}
+
+	// Relations are computed when the object is read.
+	for _, f := range computed[collection] {
+		delete(o, f)
+	}
+	return o, true
+}
+
+// stamp sets the read only fields ZPA maintains on every write.
+func (s *Server) stamp(o, prev Object, modifiedBy string) {
+	now := strconv.FormatInt(s.now().Unix(), 10)
+	o["creationTime"] = now
+	if prev != nil && prev["creationTime"] != nil {
+		o["creationTime"] = prev["creationTime"]
+	}
+	o["modifiedTime"] = now
+	o["modifiedBy"] = modifiedBy
+}
+
+// computed are the fields of each collection that are computed from other
+// objects when read.
+var computed = map[string][]string{
+	Applications:  {"segmentGroupName"},
+	SegmentGroups: {"applications"},
+	ServerGroups:  {"applications", "servers"},
+}
+
+// render returns the object with the fields ZPA computes from the objects
+// that reference it.
+func (s *Server) render(collection string, o Object) Object {
+	now := s.now()
+	switch collection {
+	case Applications:
+		if sg := s.store.get(now, SegmentGroups, str(o["segmentGroupId"])); sg != nil {
+			o["segmentGroupName"] = sg.Name()
+		}
+		for _, g := range objects(o["serverGroups"]) {
+			if sg := s.store.get(now, ServerGroups, g.ID()); sg != nil {
+				g["name"] = sg.Name()
+			}
+		}
+	case SegmentGroups:
+		apps := []interface{}{}
+		for _, a := range s.store.list(now, Applications) {
+			if str(a["segmentGroupId"]) == o.ID() {
+				apps = append(apps, map[string]interface{}{"id": a.ID(), "name": a.Name()})
+			}
+		}
+		o["applications"] = apps
+	case ServerGroups:
+		apps := []interface{}{}
+		for _, a := range s.store.list(now, Applications) {
+			for _, g := range objects(a["serverGroups"]) {
+				if g.ID() == o.ID() {
+					apps = append(apps, map[string]interface{}{"id": a.ID(), "name": a.Name()})
+					break
+				}
+			}
+		}
+		o["applications"] = apps
+
+		servers := []interface{}{}
+		for _, srv := range s.store.list(now, Servers) {
+			for _, g := range stringList(srv["appServerGroupIds"]) {
+				if g == o.ID() {
+					servers = append(servers, map[string]interface{}(srv))
+					break
+				}
+			}
+		}
+		o["servers"] = servers
+	}
+	return o
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// objects returns the objects of a JSON array.
+func objects(v interface{}) []Object {
+	l, _ := v.([]interface{})
+	out := make([]Object, 0, len(l))
+	for _, e := range l {
+		if m, ok := e.(map[string]interface{}); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// stringList returns the strings of a JSON array.
+func stringList(v interface{}) []string {
+	l, _ := v.([]interface{})
+	out := make([]string, 0, len(l))
+	for _, e := range l {
+		if s, ok := e.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func writeNotFound(w http.ResponseWriter, collection, id string) {
+	writeError(w, http.StatusNotFound, &envelope{
+		ID:     NotFound.ID,
+		Reason: fmt.Sprintf("%s %s not found", collection, id),
+		Params: []string{id},
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, e *envelope) {
+	writeJSON(w, status, e)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
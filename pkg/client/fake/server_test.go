@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// response is a response of a Server.
+type response struct {
+	Status     int
+	RetryAfter string
+	Body       Object
+}
+
+// do sends an authenticated request for the supplied path, relative to the
+// tenant, to the supplied Server.
+func do(t *testing.T, s *Server, method, path string, body Object) response {
+	t.Helper()
+
+	var b []byte
+	if body != nil {
+		b, _ = json.Marshal(body)
+	}
+	req, err := http.NewRequest(method, s.URL+basePath+s.customerID+"/"+path, bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("http.NewRequest(...): %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close() // nolint:errcheck
+
+	r := response{Status: resp.StatusCode, RetryAfter: resp.Header.Get("Retry-After")}
+	if resp.StatusCode != http.StatusNoContent {
+		r.Body = Object{}
+		if err := json.NewDecoder(resp.Body).Decode(&r.Body); err != nil {
+			t.Fatalf("%s %s: cannot decode response: %v", method, path, err)
+		}
+	}
+	return r
+}
+
+// clock is a time that tests advance explicitly.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func (c *clock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestInjectFault(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	id := s.Seed(SegmentGroups, Object{"name": "Web Apps"})
+
+	s.InjectFault(TooManyRequests.For(http.MethodGet, SegmentGroups).Once(2))
+	s.InjectFault(BadRequest.For(http.MethodPut, ""))
+
+	throttled := response{
+		Status:     http.StatusTooManyRequests,
+		RetryAfter: "1",
+		Body:       Object{"id": TooManyRequests.ID, "reason": TooManyRequests.Reason},
+	}
+	for i := 0; i < 2; i++ {
+		if diff := cmp.Diff(throttled, do(t, s, http.MethodGet, SegmentGroups+"/"+id, nil)); diff != "" {
+			t.Errorf("GET %d: -want, +got:\n%s\n", i, diff)
+		}
+	}
+	if got := do(t, s, http.MethodGet, SegmentGroups+"/"+id, nil); got.Status != http.StatusOK {
+		t.Errorf("GET after the fault was used up: want status %d, got %d", http.StatusOK, got.Status)
+	}
+
+	if got := do(t, s, http.MethodGet, Applications, nil); got.Status != http.StatusOK {
+		t.Errorf("GET of another collection: want status %d, got %d", http.StatusOK, got.Status)
+	}
+
+	invalid := response{
+		Status: http.StatusBadRequest,
+		Body:   Object{"id": BadRequest.ID, "reason": BadRequest.Reason},
+	}
+	if diff := cmp.Diff(invalid, do(t, s, http.MethodPut, SegmentGroups+"/"+id, Object{"name": "Web Apps"})); diff != "" {
+		t.Errorf("PUT: -want, +got:\n%s\n", diff)
+	}
+
+	s.ClearFaults()
+	if got := do(t, s, http.MethodPut, SegmentGroups+"/"+id, Object{"name": "Web Apps"}); got.Status != http.StatusNoContent {
+		t.Errorf("PUT after the faults were cleared: want status %d, got %d", http.StatusNoContent, got.Status)
+	}
+}
+
+func TestFaultEnvelope(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		fault  Fault
+		want   *envelope
+	}{
+		"Explicit": {
+			reason: "The error code and reason of the fault should be sent.",
+			fault:  Fault{StatusCode: http.StatusBadRequest, ID: "invalid.port.range", Reason: "Port out of range"},
+			want:   &envelope{ID: "invalid.port.range", Reason: "Port out of range"},
+		},
+		"NotFound": {
+			reason: "A 404 without an error code should be sent as a missing resource.",
+			fault:  Fault{StatusCode: http.StatusNotFound},
+			want:   &envelope{ID: NotFound.ID, Reason: "Not Found"},
+		},
+		"TooManyRequests": {
+			reason: "A 429 without an error code should be sent as a rate limit.",
+			fault:  Fault{StatusCode: http.StatusTooManyRequests},
+			want:   &envelope{ID: TooManyRequests.ID, Reason: "Too Many Requests"},
+		},
+		"Other": {
+			reason: "Other status codes without an error code should be sent as internal errors.",
+			fault:  Fault{StatusCode: http.StatusBadGateway},
+			want:   &envelope{ID: InternalServerError.ID, Reason: "Bad Gateway"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.fault.envelope()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nenvelope(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLatency(t *testing.T) {
+	latency := 50 * time.Millisecond
+
+	s := NewServer(WithLatency(latency))
+	defer s.Close()
+
+	start := time.Now()
+	do(t, s, http.MethodGet, SegmentGroups, nil)
+	if elapsed := time.Since(start); elapsed < latency {
+		t.Errorf("GET with latency %s: responded after %s", latency, elapsed)
+	}
+
+	s.SetLatency(0)
+	start = time.Now()
+	do(t, s, http.MethodGet, SegmentGroups, nil)
+	if elapsed := time.Since(start); elapsed >= latency {
+		t.Errorf("GET without latency: responded after %s", elapsed)
+	}
+}
+
+func TestConsistencyDelay(t *testing.T) {
+	delay := time.Minute
+	c := &clock{now: time.Unix(1650000000, 0)}
+
+	s := NewServer(WithConsistencyDelay(delay))
+	defer s.Close()
+	s.now = c.Now
+
+	created := do(t, s, http.MethodPost, SegmentGroups, Object{"name": "Web Apps", "description": "created"})
+	if created.Status != http.StatusCreated {
+		t.Fatalf("POST: want status %d, got %d", http.StatusCreated, created.Status)
+	}
+	id := created.Body.ID()
+
+	if got := do(t, s, http.MethodGet, SegmentGroups+"/"+id, nil); got.Status != http.StatusNotFound {
+		t.Errorf("GET before the creation is visible: want status %d, got %d", http.StatusNotFound, got.Status)
+	}
+	if got := do(t, s, http.MethodGet, SegmentGroups, nil); got.Body["totalPages"] != float64(0) {
+		t.Errorf("GET list before the creation is visible: want no pages, got %v", got.Body["totalPages"])
+	}
+	if got := s.Get(SegmentGroups, id); got.Name() != "Web Apps" {
+		t.Errorf("Get(...) before the creation is visible: want the object as last written, got %v", got)
+	}
+
+	c.Advance(delay)
+	if got := do(t, s, http.MethodGet, SegmentGroups+"/"+id, nil); got.Body["description"] != "created" {
+		t.Errorf("GET after the creation is visible: want description %q, got %v", "created", got.Body["description"])
+	}
+
+	do(t, s, http.MethodPut, SegmentGroups+"/"+id, Object{"name": "Web Apps", "description": "updated"})
+	if got := do(t, s, http.MethodGet, SegmentGroups+"/"+id, nil); got.Body["description"] != "created" {
+		t.Errorf("GET before the update is visible: want description %q, got %v", "created", got.Body["description"])
+	}
+
+	c.Advance(delay)
+	if got := do(t, s, http.MethodGet, SegmentGroups+"/"+id, nil); got.Body["description"] != "updated" {
+		t.Errorf("GET after the update is visible: want description %q, got %v", "updated", got.Body["description"])
+	}
+
+	s.SetConsistencyDelay(0)
+	do(t, s, http.MethodDelete, SegmentGroups+"/"+id, nil)
+	if got := do(t, s, http.MethodGet, SegmentGroups+"/"+id, nil); got.Status != http.StatusNotFound {
+		t.Errorf("GET after the deletion without delay: want status %d, got %d", http.StatusNotFound, got.Status)
+	}
+}
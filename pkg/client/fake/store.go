@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// An Object is a ZPA object as it is sent over the wire, e.g. an application
+// segment with its id, name and segmentGroupId.
+type Object map[string]interface{}
+
+// ID returns the id of the object.
+func (o Object) ID() string {
+	s, _ := o["id"].(string)
+	return s
+}
+
+// Name returns the name of the object.
+func (o Object) Name() string {
+	s, _ := o["name"].(string)
+	return s
+}
+
+// DeepCopy returns a copy of the object that shares no values with it.
+func (o Object) DeepCopy() Object {
+	if o == nil {
+		return nil
+	}
+	b, _ := json.Marshal(o)
+	out := Object{}
+	_ = json.Unmarshal(b, &out)
+	return out
+}
+
+// A record is an object with the version that reads return until the last
+// write becomes visible.
+type record struct {
+	// current is the object as last written, or nil if it was deleted.
+	current Object
+
+	// previous is the object as it was visible before the last write, or nil
+	// if it did not exist.
+	previous Object
+
+	// visibleAt is when the last write becomes visible to reads.
+	visibleAt time.Time
+}
+
+func (r *record) visible(now time.Time) Object {
+	if now.Before(r.visibleAt) {
+		return r.previous
+	}
+	return r.current
+}
+
+// store keeps the objects of all collections. It is not safe for concurrent
+// use; the Server serializes access.
+type store struct {
+	nextID      int64
+	collections map[string]map[string]*record
+}
+
+func newStore() *store {
+	return &store{nextID: 72058000000000000, collections: map[string]map[string]*record{}}
+}
+
+func (s *store) newID() string {
+	s.nextID++
+	return strconv.FormatInt(s.nextID, 10)
+}
+
+// get returns the object with the supplied id as visible to reads.
+func (s *store) get(now time.Time, collection, id string) Object {
+	r, ok := s.collections[collection][id]
+	if !ok {
+		return nil
+	}
+	return r.visible(now).DeepCopy()
+}
+
+// current returns the object with the supplied id as last written.
+func (s *store) current(collection, id string) Object {
+	r, ok := s.collections[collection][id]
+	if !ok {
+		return nil
+	}
+	return r.current.DeepCopy()
+}
+
+// list returns the objects of the collection that are visible to reads,
+// ordered by id.
+func (s *store) list(now time.Time, collection string) []Object {
+	out := make([]Object, 0, len(s.collections[collection]))
+	for _, r := range s.collections[collection] {
+		if o := r.visible(now); o != nil {
+			out = append(out, o.DeepCopy())
+		}
+	}
+	sortByID(out)
+	return out
+}
+
+// currentList returns the objects of the collection as last written, ordered
+// by id.
+func (s *store) currentList(collection string) []Object {
+	out := make([]Object, 0, len(s.collections[collection]))
+	for _, r := range s.collections[collection] {
+		if r.current != nil {
+			out = append(out, r.current.DeepCopy())
+		}
+	}
+	sortByID(out)
+	return out
+}
+
+// put writes the supplied object, which becomes visible to reads after the
+// supplied delay. A nil object deletes the object with the supplied id.
+func (s *store) put(now time.Time, collection, id string, o Object, delay time.Duration) {
+	c, ok := s.collections[collection]
+	if !ok {
+		c = map[string]*record{}
+		s.collections[collection] = c
+	}
+	r, ok := c[id]
+	if !ok {
+		r = &record{}
+		c[id] = r
+	}
+	r.previous = r.visible(now)
+	r.current = o.DeepCopy()
+	r.visibleAt = now.Add(delay)
+
+	if r.current == nil && delay == 0 {
+		delete(c, id)
+	}
+}
+
+func sortByID(objs []Object) {
+	sort.Slice(objs, func(i, j int) bool {
+		a, _ := strconv.ParseInt(objs[i].ID(), 10, 64)
+		b, _ := strconv.ParseInt(objs[j].ID(), 10, 64)
+		if a != b {
+			return a < b
+		}
+		return objs[i].ID() < objs[j].ID()
+	})
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"strings"
+)
+
+// IgnoredField returns the top level parameter named by the supplied
+// JSONPath-like path. The path may be relative to spec.forProvider, e.g.
+// defaultIdleTimeout, or absolute, e.g. $.spec.forProvider.serverGroups[0].
+// Paths within a parameter select the whole parameter.
+func IgnoredField(path string) string {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	path = strings.TrimPrefix(path, ".")
+	path = strings.TrimPrefix(path, "spec.forProvider.")
+	if i := strings.IndexAny(path, ".["); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// WithoutIgnored returns the supplied fields except those selected by the
+// supplied ignoreDrift paths.
+func WithoutIgnored(fields, ignoreDrift []string) []string {
+	if len(ignoreDrift) == 0 {
+		return fields
+	}
+	ignored := make(map[string]bool, len(ignoreDrift))
+	for _, p := range ignoreDrift {
+		ignored[IgnoredField(p)] = true
+	}
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if !ignored[f] {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// APIFields returns the JSON names in the ZPA API of the parameters selected
+// by the supplied ignoreDrift paths. Parameters named differently in the ZPA
+// API are renamed according to the supplied map.
+func APIFields(ignoreDrift []string, renamed map[string]string) []string {
+	out := make([]string, 0, len(ignoreDrift))
+	for _, p := range ignoreDrift {
+		f := IgnoredField(p)
+		if r, ok := renamed[f]; ok {
+			f = r
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// UnknownIgnored returns the supplied ignoreDrift paths that select none of
+// the supplied parameters, e.g. because of a typo.
+func UnknownIgnored(ignoreDrift, parameters []string) []string {
+	known := make(map[string]bool, len(parameters))
+	for _, p := range parameters {
+		known[p] = true
+	}
+	out := make([]string, 0)
+	for _, p := range ignoreDrift {
+		if !known[IgnoredField(p)] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
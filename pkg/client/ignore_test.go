@@ -0,0 +1,188 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIgnoredField(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		path   string
+		want   string
+	}{
+		"Relative": {
+			reason: "A path relative to spec.forProvider should select its parameter.",
+			path:   "defaultIdleTimeout",
+			want:   "defaultIdleTimeout",
+		},
+		"Absolute": {
+			reason: "A path starting with $ should be relative to the resource.",
+			path:   "$.spec.forProvider.defaultIdleTimeout",
+			want:   "defaultIdleTimeout",
+		},
+		"LeadingDot": {
+			reason: "A path starting with a dot should be relative to the resource.",
+			path:   ".spec.forProvider.description",
+			want:   "description",
+		},
+		"ForProvider": {
+			reason: "A path starting with spec.forProvider should be relative to the resource.",
+			path:   "spec.forProvider.description",
+			want:   "description",
+		},
+		"Index": {
+			reason: "A path of an element of a parameter should select the whole parameter.",
+			path:   "$.spec.forProvider.serverGroups[0]",
+			want:   "serverGroups",
+		},
+		"Nested": {
+			reason: "A path of a field within a parameter should select the whole parameter.",
+			path:   "tcpPortRange.from",
+			want:   "tcpPortRange",
+		},
+		"Whitespace": {
+			reason: "Surrounding whitespace should be ignored.",
+			path:   " description ",
+			want:   "description",
+		},
+		"Empty": {
+			reason: "An empty path should select no parameter.",
+			path:   "",
+			want:   "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IgnoredField(tc.path)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIgnoredField(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithoutIgnored(t *testing.T) {
+	fields := []string{"name", "description", "serverGroups"}
+
+	cases := map[string]struct {
+		reason      string
+		ignoreDrift []string
+		want        []string
+	}{
+		"NoneIgnored": {
+			reason: "All fields should be kept if none are ignored.",
+			want:   fields,
+		},
+		"Ignored": {
+			reason:      "Fields selected by ignoreDrift paths in any form should be dropped.",
+			ignoreDrift: []string{"description", "$.spec.forProvider.serverGroups[1]"},
+			want:        []string{"name"},
+		},
+		"Unknown": {
+			reason:      "ignoreDrift paths of fields that are not supplied should be ignored.",
+			ignoreDrift: []string{"bypassType"},
+			want:        fields,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := WithoutIgnored(fields, tc.ignoreDrift)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nWithoutIgnored(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPIFields(t *testing.T) {
+	renamed := map[string]string{"tcpPortRange": "tcpPortRanges", "segmentGroupID": "segmentGroupId"}
+
+	cases := map[string]struct {
+		reason      string
+		ignoreDrift []string
+		want        []string
+	}{
+		"None": {
+			reason: "No ignoreDrift paths should select no fields.",
+			want:   []string{},
+		},
+		"SameName": {
+			reason:      "Parameters named alike in the ZPA API should keep their name.",
+			ignoreDrift: []string{"$.spec.forProvider.description"},
+			want:        []string{"description"},
+		},
+		"Renamed": {
+			reason:      "Parameters named differently in the ZPA API should be renamed.",
+			ignoreDrift: []string{"tcpPortRange[0].from", ".spec.forProvider.segmentGroupID"},
+			want:        []string{"tcpPortRanges", "segmentGroupId"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := APIFields(tc.ignoreDrift, renamed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nAPIFields(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUnknownIgnored(t *testing.T) {
+	parameters := []string{"name", "description", "tcpPortRange"}
+
+	cases := map[string]struct {
+		reason      string
+		ignoreDrift []string
+		want        []string
+	}{
+		"None": {
+			reason: "No ignoreDrift paths should be unknown.",
+			want:   []string{},
+		},
+		"Known": {
+			reason:      "Paths of parameters in any form, and of fields within them, should be known.",
+			ignoreDrift: []string{"description", "$.spec.forProvider.name", "tcpPortRange[0].from"},
+			want:        []string{},
+		},
+		"Typo": {
+			reason:      "Paths that select none of the parameters should be unknown.",
+			ignoreDrift: []string{"description", "spec.forProvider.descripton"},
+			want:        []string{"spec.forProvider.descripton"},
+		},
+		"NotForProvider": {
+			reason:      "Paths outside of spec.forProvider should be unknown.",
+			ignoreDrift: []string{"forProvider.description", "$.status.atProvider.description"},
+			want:        []string{"forProvider.description", "$.status.atProvider.description"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := UnknownIgnored(tc.ignoreDrift, parameters)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nUnknownIgnored(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ListPageSize is the maximum page size accepted by the ZPA list endpoints.
+const ListPageSize = 500
+
+const (
+	errNoObjectNamed        = "cannot import %s: no object named %q exists"
+	errMultipleObjectsNamed = "cannot import %s: multiple objects named %q exist with IDs %s"
+)
+
+// Policies for creating an object when ZPA already has one with the same name.
+const (
+	// NameConflictPolicyFail reports the conflict and does not create the
+	// object.
+	NameConflictPolicyFail = "Fail"
+
+	// NameConflictPolicyAdopt adopts the existing object.
+	NameConflictPolicyAdopt = "Adopt"
+)
+
+// NamedObject is the ID and name of an object returned by a ZPA list endpoint.
+type NamedObject struct {
+	ID   string
+	Name string
+}
+
+// NameConflictError is returned when an object cannot be created because ZPA
+// already has an object of the same kind and name.
+type NameConflictError struct {
+	Kind string
+	Name string
+	IDs  []string
+}
+
+func (e *NameConflictError) Error() string {
+	return fmt.Sprintf("%s named %q already exists with ID %s", e.Kind, e.Name, strings.Join(e.IDs, ", "))
+}
+
+// IsNameConflict returns whether the supplied error is a NameConflictError.
+func IsNameConflict(err error) bool {
+	var conflict *NameConflictError
+	return errors.As(err, &conflict)
+}
+
+// NotFoundByNameError is returned when an object cannot be imported by name
+// because ZPA has no object of the same kind and name.
+type NotFoundByNameError struct {
+	Kind string
+	Name string
+}
+
+func (e *NotFoundByNameError) Error() string {
+	return fmt.Sprintf(errNoObjectNamed, e.Kind, e.Name)
+}
+
+// IsNotFoundByName returns whether the supplied error is a
+// NotFoundByNameError.
+func IsNotFoundByName(err error) bool {
+	var notFound *NotFoundByNameError
+	return errors.As(err, &notFound)
+}
+
+// AmbiguousNameError is returned when an object cannot be imported by name
+// because ZPA has more than one object of the same kind and name.
+type AmbiguousNameError struct {
+	Kind string
+	Name string
+	IDs  []string
+}
+
+func (e *AmbiguousNameError) Error() string {
+	return fmt.Sprintf(errMultipleObjectsNamed, e.Kind, e.Name, strings.Join(e.IDs, ", "))
+}
+
+// IsAmbiguousName returns whether the supplied error is an
+// AmbiguousNameError.
+func IsAmbiguousName(err error) bool {
+	var ambiguous *AmbiguousNameError
+	return errors.As(err, &ambiguous)
+}
+
+// FindIDByName returns the ID of the only object with exactly the supplied
+// name. It returns a NotFoundByNameError if none of the objects match, and an
+// AmbiguousNameError if more than one do.
+func FindIDByName(kind, name string, objs []NamedObject) (string, error) {
+	ids := idsByName(name, objs)
+	switch len(ids) {
+	case 0:
+		return "", &NotFoundByNameError{Kind: kind, Name: name}
+	case 1:
+		return ids[0], nil
+	default:
+		return "", &AmbiguousNameError{Kind: kind, Name: name, IDs: ids}
+	}
+}
+
+// ResolveNameConflict is used after creating an object failed with createErr.
+// If one of the supplied objects has the same name it returns its ID when the
+// policy allows to adopt it, or a NameConflictError otherwise. If there is no
+// object with the same name createErr is returned.
+func ResolveNameConflict(kind, name, policy string, objs []NamedObject, createErr error) (string, error) {
+	ids := idsByName(name, objs)
+	if len(ids) == 0 {
+		return "", createErr
+	}
+	if policy != NameConflictPolicyAdopt || len(ids) > 1 {
+		return "", &NameConflictError{Kind: kind, Name: name, IDs: ids}
+	}
+	return ids[0], nil
+}
+
+func idsByName(name string, objs []NamedObject) []string {
+	ids := make([]string, 0, 1)
+	for _, o := range objs {
+		if o.Name == name {
+			ids = append(ids, o.ID)
+		}
+	}
+	return ids
+}
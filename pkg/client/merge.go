@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+const errOverlay = "cannot merge update with the object in ZPA"
+
+// Overlay overwrites the supplied payload, a ZPA API model, with live, the
+// same model as read from ZPA, except for the supplied fields which keep
+// their value in the payload. Fields are named by their JSON name in the ZPA
+// API. Fields that ZPA supports but the provider does not model thus keep
+// their live value when the payload is sent.
+func Overlay(payload, live interface{}, fields []string) error {
+	p, err := toMap(payload)
+	if err != nil {
+		return errors.Wrap(err, errOverlay)
+	}
+	merged, err := toMap(live)
+	if err != nil {
+		return errors.Wrap(err, errOverlay)
+	}
+
+	for _, f := range fields {
+		if v, ok := p[f]; ok {
+			merged[f] = v
+		} else {
+			delete(merged, f)
+		}
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return errors.Wrap(err, errOverlay)
+	}
+	v := reflect.ValueOf(payload).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	return errors.Wrap(json.Unmarshal(b, payload), errOverlay)
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]interface{}{}
+	return m, json.Unmarshal(b, &m)
+}
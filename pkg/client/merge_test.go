@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// model is a ZPA API model.
+type model struct {
+	Name           string   `json:"name,omitempty"`
+	Enabled        bool     `json:"enabled,omitempty"`
+	DoubleEncrypt  *bool    `json:"doubleEncrypt,omitempty"`
+	IdleTimeout    int      `json:"idleTimeout,omitempty"`
+	Description    *string  `json:"description,omitempty"`
+	ServerGroups   []string `json:"serverGroups,omitempty"`
+	ConfigSpace    string   `json:"configSpace,omitempty"`
+	IcmpAccessType string   `json:"icmpAccessType,omitempty"`
+	PassiveHealth  *bool    `json:"passiveHealthEnabled,omitempty"`
+}
+
+func TestOverlay(t *testing.T) {
+	modelled := []string{"name", "enabled", "doubleEncrypt", "idleTimeout", "description", "serverGroups"}
+	live := func() *model {
+		return &model{
+			Name:           "example",
+			Enabled:        true,
+			DoubleEncrypt:  Bool(true),
+			IdleTimeout:    600,
+			Description:    String("from ZPA"),
+			ServerGroups:   []string{"1", "2"},
+			ConfigSpace:    "DEFAULT",
+			IcmpAccessType: "PING",
+			PassiveHealth:  Bool(true),
+		}
+	}
+
+	type want struct {
+		payload *model
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		payload *model
+		live    interface{}
+		fields  []string
+		want    want
+	}{
+		"Modelled": {
+			reason:  "Modelled fields should keep their value in the payload.",
+			payload: &model{Name: "desired", Enabled: true, DoubleEncrypt: Bool(true), IdleTimeout: 300, Description: String("desired"), ServerGroups: []string{"3"}},
+			live:    live(),
+			fields:  modelled,
+			want: want{payload: &model{
+				Name:           "desired",
+				Enabled:        true,
+				DoubleEncrypt:  Bool(true),
+				IdleTimeout:    300,
+				Description:    String("desired"),
+				ServerGroups:   []string{"3"},
+				ConfigSpace:    "DEFAULT",
+				IcmpAccessType: "PING",
+				PassiveHealth:  Bool(true),
+			}},
+		},
+		"ZeroValues": {
+			reason:  "Modelled fields set to false or zero in the payload should not take their live value.",
+			payload: &model{Name: "example", Enabled: false, DoubleEncrypt: Bool(false), IdleTimeout: 0},
+			live:    live(),
+			fields:  modelled,
+			want: want{payload: &model{
+				Name:           "example",
+				DoubleEncrypt:  Bool(false),
+				ConfigSpace:    "DEFAULT",
+				IcmpAccessType: "PING",
+				PassiveHealth:  Bool(true),
+			}},
+		},
+		"OmittedKeys": {
+			reason:  "Modelled fields omitted from the payload should be omitted from the merged payload, too.",
+			payload: &model{Name: "example"},
+			live:    live(),
+			fields:  modelled,
+			want: want{payload: &model{
+				Name:           "example",
+				ConfigSpace:    "DEFAULT",
+				IcmpAccessType: "PING",
+				PassiveHealth:  Bool(true),
+			}},
+		},
+		"Unmodelled": {
+			reason:  "Fields the provider does not model should keep their live value, even if the payload sets them.",
+			payload: &model{Name: "example", ConfigSpace: "SIEM", IcmpAccessType: "NONE"},
+			live:    live(),
+			fields:  []string{"name"},
+			want:    want{payload: live()},
+		},
+		"Ignored": {
+			reason:  "Modelled fields that are not supplied, e.g. because their drift is ignored, should keep their live value.",
+			payload: &model{Name: "example", Description: String("desired")},
+			live:    live(),
+			fields:  []string{"name"},
+			want:    want{payload: live()},
+		},
+		"LiveError": {
+			reason:  "A live object that cannot be encoded should be an error.",
+			payload: &model{Name: "example"},
+			live:    make(chan int),
+			fields:  modelled,
+			want: want{
+				payload: &model{Name: "example"},
+				err:     errors.Wrap(&json.UnsupportedTypeError{Type: reflect.TypeOf(make(chan int))}, errOverlay),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Overlay(tc.payload, tc.live, tc.fields)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nOverlay(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.payload, tc.payload); diff != "" {
+				t.Errorf("\n%s\nOverlay(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
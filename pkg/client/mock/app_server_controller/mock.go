@@ -0,0 +1,149 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/haarchri/zpa-go-client/pkg/client/app_server_controller (interfaces: ClientService)
+
+// Package app_server_controller is a generated GoMock package.
+package app_server_controller
+
+import (
+	reflect "reflect"
+
+	runtime "github.com/go-openapi/runtime"
+	gomock "github.com/golang/mock/gomock"
+	app_server_controller "github.com/haarchri/zpa-go-client/pkg/client/app_server_controller"
+)
+
+// MockClientService is a mock of ClientService interface.
+type MockClientService struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientServiceMockRecorder
+}
+
+// MockClientServiceMockRecorder is the mock recorder for MockClientService.
+type MockClientServiceMockRecorder struct {
+	mock *MockClientService
+}
+
+// NewMockClientService creates a new mock instance.
+func NewMockClientService(ctrl *gomock.Controller) *MockClientService {
+	mock := &MockClientService{ctrl: ctrl}
+	mock.recorder = &MockClientServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientService) EXPECT() *MockClientServiceMockRecorder {
+	return m.recorder
+}
+
+// AddAppServerUsingPOST1 mocks base method.
+func (m *MockClientService) AddAppServerUsingPOST1(arg0 *app_server_controller.AddAppServerUsingPOST1Params, arg1 ...app_server_controller.ClientOption) (*app_server_controller.AddAppServerUsingPOST1Created, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddAppServerUsingPOST1", varargs...)
+	ret0, _ := ret[0].(*app_server_controller.AddAppServerUsingPOST1Created)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAppServerUsingPOST1 indicates an expected call of AddAppServerUsingPOST1.
+func (mr *MockClientServiceMockRecorder) AddAppServerUsingPOST1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAppServerUsingPOST1", reflect.TypeOf((*MockClientService)(nil).AddAppServerUsingPOST1), varargs...)
+}
+
+// DeleteAppServerUsingDELETE1 mocks base method.
+func (m *MockClientService) DeleteAppServerUsingDELETE1(arg0 *app_server_controller.DeleteAppServerUsingDELETE1Params, arg1 ...app_server_controller.ClientOption) (*app_server_controller.DeleteAppServerUsingDELETE1NoContent, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteAppServerUsingDELETE1", varargs...)
+	ret0, _ := ret[0].(*app_server_controller.DeleteAppServerUsingDELETE1NoContent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAppServerUsingDELETE1 indicates an expected call of DeleteAppServerUsingDELETE1.
+func (mr *MockClientServiceMockRecorder) DeleteAppServerUsingDELETE1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppServerUsingDELETE1", reflect.TypeOf((*MockClientService)(nil).DeleteAppServerUsingDELETE1), varargs...)
+}
+
+// GetAllAppServersUsingGET1 mocks base method.
+func (m *MockClientService) GetAllAppServersUsingGET1(arg0 *app_server_controller.GetAllAppServersUsingGET1Params, arg1 ...app_server_controller.ClientOption) (*app_server_controller.GetAllAppServersUsingGET1OK, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAllAppServersUsingGET1", varargs...)
+	ret0, _ := ret[0].(*app_server_controller.GetAllAppServersUsingGET1OK)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllAppServersUsingGET1 indicates an expected call of GetAllAppServersUsingGET1.
+func (mr *MockClientServiceMockRecorder) GetAllAppServersUsingGET1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllAppServersUsingGET1", reflect.TypeOf((*MockClientService)(nil).GetAllAppServersUsingGET1), varargs...)
+}
+
+// GetAppServerUsingGET1 mocks base method.
+func (m *MockClientService) GetAppServerUsingGET1(arg0 *app_server_controller.GetAppServerUsingGET1Params, arg1 ...app_server_controller.ClientOption) (*app_server_controller.GetAppServerUsingGET1OK, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAppServerUsingGET1", varargs...)
+	ret0, _ := ret[0].(*app_server_controller.GetAppServerUsingGET1OK)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAppServerUsingGET1 indicates an expected call of GetAppServerUsingGET1.
+func (mr *MockClientServiceMockRecorder) GetAppServerUsingGET1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppServerUsingGET1", reflect.TypeOf((*MockClientService)(nil).GetAppServerUsingGET1), varargs...)
+}
+
+// SetTransport mocks base method.
+func (m *MockClientService) SetTransport(arg0 runtime.ClientTransport) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTransport", arg0)
+}
+
+// SetTransport indicates an expected call of SetTransport.
+func (mr *MockClientServiceMockRecorder) SetTransport(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransport", reflect.TypeOf((*MockClientService)(nil).SetTransport), arg0)
+}
+
+// UpdateAppServerUsingPUT1 mocks base method.
+func (m *MockClientService) UpdateAppServerUsingPUT1(arg0 *app_server_controller.UpdateAppServerUsingPUT1Params, arg1 ...app_server_controller.ClientOption) (*app_server_controller.UpdateAppServerUsingPUT1Created, *app_server_controller.UpdateAppServerUsingPUT1NoContent, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateAppServerUsingPUT1", varargs...)
+	ret0, _ := ret[0].(*app_server_controller.UpdateAppServerUsingPUT1Created)
+	ret1, _ := ret[1].(*app_server_controller.UpdateAppServerUsingPUT1NoContent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateAppServerUsingPUT1 indicates an expected call of UpdateAppServerUsingPUT1.
+func (mr *MockClientServiceMockRecorder) UpdateAppServerUsingPUT1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppServerUsingPUT1", reflect.TypeOf((*MockClientService)(nil).UpdateAppServerUsingPUT1), varargs...)
+}
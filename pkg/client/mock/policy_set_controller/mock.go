@@ -0,0 +1,209 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/haarchri/zpa-go-client/pkg/client/policy_set_controller (interfaces: ClientService)
+
+// Package policy_set_controller is a generated GoMock package.
+package policy_set_controller
+
+import (
+	reflect "reflect"
+
+	runtime "github.com/go-openapi/runtime"
+	gomock "github.com/golang/mock/gomock"
+	policy_set_controller "github.com/haarchri/zpa-go-client/pkg/client/policy_set_controller"
+)
+
+// MockClientService is a mock of ClientService interface.
+type MockClientService struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientServiceMockRecorder
+}
+
+// MockClientServiceMockRecorder is the mock recorder for MockClientService.
+type MockClientServiceMockRecorder struct {
+	mock *MockClientService
+}
+
+// NewMockClientService creates a new mock instance.
+func NewMockClientService(ctrl *gomock.Controller) *MockClientService {
+	mock := &MockClientService{ctrl: ctrl}
+	mock.recorder = &MockClientServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientService) EXPECT() *MockClientServiceMockRecorder {
+	return m.recorder
+}
+
+// AddRuleToPolicySetUsingPOST1 mocks base method.
+func (m *MockClientService) AddRuleToPolicySetUsingPOST1(arg0 *policy_set_controller.AddRuleToPolicySetUsingPOST1Params, arg1 ...policy_set_controller.ClientOption) (*policy_set_controller.AddRuleToPolicySetUsingPOST1Created, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddRuleToPolicySetUsingPOST1", varargs...)
+	ret0, _ := ret[0].(*policy_set_controller.AddRuleToPolicySetUsingPOST1Created)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRuleToPolicySetUsingPOST1 indicates an expected call of AddRuleToPolicySetUsingPOST1.
+func (mr *MockClientServiceMockRecorder) AddRuleToPolicySetUsingPOST1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRuleToPolicySetUsingPOST1", reflect.TypeOf((*MockClientService)(nil).AddRuleToPolicySetUsingPOST1), varargs...)
+}
+
+// DeleteRuleInPolicySetUsingDELETE1 mocks base method.
+func (m *MockClientService) DeleteRuleInPolicySetUsingDELETE1(arg0 *policy_set_controller.DeleteRuleInPolicySetUsingDELETE1Params, arg1 ...policy_set_controller.ClientOption) (*policy_set_controller.DeleteRuleInPolicySetUsingDELETE1NoContent, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteRuleInPolicySetUsingDELETE1", varargs...)
+	ret0, _ := ret[0].(*policy_set_controller.DeleteRuleInPolicySetUsingDELETE1NoContent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteRuleInPolicySetUsingDELETE1 indicates an expected call of DeleteRuleInPolicySetUsingDELETE1.
+func (mr *MockClientServiceMockRecorder) DeleteRuleInPolicySetUsingDELETE1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRuleInPolicySetUsingDELETE1", reflect.TypeOf((*MockClientService)(nil).DeleteRuleInPolicySetUsingDELETE1), varargs...)
+}
+
+// GetBypassPolicySetUsingGET1 mocks base method.
+func (m *MockClientService) GetBypassPolicySetUsingGET1(arg0 *policy_set_controller.GetBypassPolicySetUsingGET1Params, arg1 ...policy_set_controller.ClientOption) (*policy_set_controller.GetBypassPolicySetUsingGET1OK, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetBypassPolicySetUsingGET1", varargs...)
+	ret0, _ := ret[0].(*policy_set_controller.GetBypassPolicySetUsingGET1OK)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBypassPolicySetUsingGET1 indicates an expected call of GetBypassPolicySetUsingGET1.
+func (mr *MockClientServiceMockRecorder) GetBypassPolicySetUsingGET1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBypassPolicySetUsingGET1", reflect.TypeOf((*MockClientService)(nil).GetBypassPolicySetUsingGET1), varargs...)
+}
+
+// GetGlobalPolicySetUsingGET1 mocks base method.
+func (m *MockClientService) GetGlobalPolicySetUsingGET1(arg0 *policy_set_controller.GetGlobalPolicySetUsingGET1Params, arg1 ...policy_set_controller.ClientOption) (*policy_set_controller.GetGlobalPolicySetUsingGET1OK, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetGlobalPolicySetUsingGET1", varargs...)
+	ret0, _ := ret[0].(*policy_set_controller.GetGlobalPolicySetUsingGET1OK)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGlobalPolicySetUsingGET1 indicates an expected call of GetGlobalPolicySetUsingGET1.
+func (mr *MockClientServiceMockRecorder) GetGlobalPolicySetUsingGET1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGlobalPolicySetUsingGET1", reflect.TypeOf((*MockClientService)(nil).GetGlobalPolicySetUsingGET1), varargs...)
+}
+
+// GetPolicyRulesByPageUsingGET1 mocks base method.
+func (m *MockClientService) GetPolicyRulesByPageUsingGET1(arg0 *policy_set_controller.GetPolicyRulesByPageUsingGET1Params, arg1 ...policy_set_controller.ClientOption) (*policy_set_controller.GetPolicyRulesByPageUsingGET1OK, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetPolicyRulesByPageUsingGET1", varargs...)
+	ret0, _ := ret[0].(*policy_set_controller.GetPolicyRulesByPageUsingGET1OK)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicyRulesByPageUsingGET1 indicates an expected call of GetPolicyRulesByPageUsingGET1.
+func (mr *MockClientServiceMockRecorder) GetPolicyRulesByPageUsingGET1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicyRulesByPageUsingGET1", reflect.TypeOf((*MockClientService)(nil).GetPolicyRulesByPageUsingGET1), varargs...)
+}
+
+// GetReauthPolicySetUsingGET1 mocks base method.
+func (m *MockClientService) GetReauthPolicySetUsingGET1(arg0 *policy_set_controller.GetReauthPolicySetUsingGET1Params, arg1 ...policy_set_controller.ClientOption) (*policy_set_controller.GetReauthPolicySetUsingGET1OK, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetReauthPolicySetUsingGET1", varargs...)
+	ret0, _ := ret[0].(*policy_set_controller.GetReauthPolicySetUsingGET1OK)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReauthPolicySetUsingGET1 indicates an expected call of GetReauthPolicySetUsingGET1.
+func (mr *MockClientServiceMockRecorder) GetReauthPolicySetUsingGET1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReauthPolicySetUsingGET1", reflect.TypeOf((*MockClientService)(nil).GetReauthPolicySetUsingGET1), varargs...)
+}
+
+// GetRuleInPolicySetUsingGET1 mocks base method.
+func (m *MockClientService) GetRuleInPolicySetUsingGET1(arg0 *policy_set_controller.GetRuleInPolicySetUsingGET1Params, arg1 ...policy_set_controller.ClientOption) (*policy_set_controller.GetRuleInPolicySetUsingGET1OK, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetRuleInPolicySetUsingGET1", varargs...)
+	ret0, _ := ret[0].(*policy_set_controller.GetRuleInPolicySetUsingGET1OK)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuleInPolicySetUsingGET1 indicates an expected call of GetRuleInPolicySetUsingGET1.
+func (mr *MockClientServiceMockRecorder) GetRuleInPolicySetUsingGET1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuleInPolicySetUsingGET1", reflect.TypeOf((*MockClientService)(nil).GetRuleInPolicySetUsingGET1), varargs...)
+}
+
+// SetTransport mocks base method.
+func (m *MockClientService) SetTransport(arg0 runtime.ClientTransport) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTransport", arg0)
+}
+
+// SetTransport indicates an expected call of SetTransport.
+func (mr *MockClientServiceMockRecorder) SetTransport(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransport", reflect.TypeOf((*MockClientService)(nil).SetTransport), arg0)
+}
+
+// UpdateRuleToPolicySetUsingPUT1 mocks base method.
+func (m *MockClientService) UpdateRuleToPolicySetUsingPUT1(arg0 *policy_set_controller.UpdateRuleToPolicySetUsingPUT1Params, arg1 ...policy_set_controller.ClientOption) (*policy_set_controller.UpdateRuleToPolicySetUsingPUT1Created, *policy_set_controller.UpdateRuleToPolicySetUsingPUT1NoContent, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateRuleToPolicySetUsingPUT1", varargs...)
+	ret0, _ := ret[0].(*policy_set_controller.UpdateRuleToPolicySetUsingPUT1Created)
+	ret1, _ := ret[1].(*policy_set_controller.UpdateRuleToPolicySetUsingPUT1NoContent)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateRuleToPolicySetUsingPUT1 indicates an expected call of UpdateRuleToPolicySetUsingPUT1.
+func (mr *MockClientServiceMockRecorder) UpdateRuleToPolicySetUsingPUT1(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRuleToPolicySetUsingPUT1", reflect.TypeOf((*MockClientService)(nil).UpdateRuleToPolicySetUsingPUT1), varargs...)
+}
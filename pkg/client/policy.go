@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	zpa "github.com/haarchri/zpa-go-client/pkg/client"
+	"github.com/haarchri/zpa-go-client/pkg/client/policy_set_controller"
+	"github.com/haarchri/zpa-go-client/pkg/models"
+)
+
+const errListPolicyRules = "cannot list %s rules"
+
+// Object types of policy rule operands that reference ZPA objects.
+const (
+	ObjectTypeApplication  = "APP"
+	ObjectTypeSegmentGroup = "APP_GROUP"
+)
+
+// policyTypes are the types of policies whose rules can reference
+// ApplicationSegments and SegmentGroups.
+var policyTypes = []string{
+	"ACCESS_POLICY",
+	"TIMEOUT_POLICY",
+	"CLIENT_FORWARDING_POLICY",
+	"INSPECTION_POLICY",
+}
+
+// ReferencingPolicyRules returns the policy rules with a condition on the
+// object with the supplied object type and ID.
+func ReferencingPolicyRules(ctx context.Context, c *zpa.ZscalerPrivateAccessAPIPortal, customerID, objectType, id string) ([]NamedObject, error) {
+	rules := make([]NamedObject, 0)
+	for _, policyType := range policyTypes {
+		for page := int32(1); ; page++ {
+			req := &policy_set_controller.GetPolicyRulesByPageUsingGET1Params{
+				Context:    ctx,
+				CustomerID: customerID,
+				PolicyType: policyType,
+				Page:       page,
+				Pagesize:   ListPageSize,
+			}
+			resp, err := c.PolicySetController.GetPolicyRulesByPageUsingGET1(req)
+			if err != nil {
+				return nil, errors.Wrapf(err, errListPolicyRules, policyType)
+			}
+
+			for _, rule := range resp.Payload.List {
+				if references(rule, objectType, id) {
+					rules = append(rules, NamedObject{ID: rule.ID, Name: StringValue(rule.Name)})
+				}
+			}
+
+			if page >= resp.Payload.TotalPages {
+				break
+			}
+		}
+	}
+	return rules, nil
+}
+
+func references(rule *models.PolicyRule, objectType, id string) bool {
+	for _, c := range rule.Conditions {
+		if c == nil {
+			continue
+		}
+		for _, o := range c.Operands {
+			if o != nil && o.ObjectType == objectType && o.RHS == id {
+				return true
+			}
+		}
+	}
+	return false
+}
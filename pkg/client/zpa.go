@@ -18,6 +18,8 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -27,6 +29,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/go-openapi/runtime"
 	httptransport "github.com/go-openapi/runtime/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -44,17 +47,33 @@ import (
 const (
 	errNoProviderConfigRef            = "no providerConfigRef is given"
 	errCannotGetProvider              = "cannot get referenced Provider"
+	errNoCustomerID                   = "no customerID is given in the referenced ProviderConfig"
 	errCannotTrackProviderConfigUsage = "cannot track ProviderConfig usage"
 	errOnlySecretSourceAllowed        = "only Secret supported as Source"
 	errExtractSecret                  = "cannot extract credentials from secret"
 	errExtractSecretKey               = "cannot extract secret key"
 	errGetCredentialsSecret           = "cannot get credentials secret"
 	errInvalidSecretData              = "'%s' is required in secret data"
+	errInvalidCABundle                = "caBundle contains no PEM encoded certificate"
 )
 
-// GetConfig constructs an *httptransport.Runtime that can be used to connect to Zscaler ZPA
+// Config is the connection to the ZPA tenant of a ProviderConfig.
+type Config struct {
+	// Transport sends the operations of the ZPA client to the tenant.
+	// Failed operations return an *APIError.
+	Transport runtime.ClientTransport
+
+	// CustomerID is the ID of the tenant, which is needed for all
+	// operations.
+	CustomerID string
+
+	// Host is the ZPA API host of the tenant.
+	Host string
+}
+
+// GetConfig constructs a Config that can be used to connect to Zscaler ZPA
 // API by the ZPA client.
-func GetConfig(ctx context.Context, c client.Client, mg resource.Managed) (*httptransport.Runtime, error) {
+func GetConfig(ctx context.Context, c client.Client, mg resource.Managed) (*Config, error) {
 	switch {
 	case mg.GetProviderConfigReference() != nil:
 		return UseProviderConfig(ctx, c, mg)
@@ -63,38 +82,64 @@ func GetConfig(ctx context.Context, c client.Client, mg resource.Managed) (*http
 	}
 }
 
-// UseProviderConfig to produce a *httptransport.Runtime that can be used to connect to Zscaler ZPA.
-func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed) (*httptransport.Runtime, error) { // nolint:gocyclo
+// UseProviderConfig to produce a Config that can be used to connect to Zscaler ZPA.
+func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed) (*Config, error) {
 	pc := &v1alpha1.ProviderConfig{}
 	if err := c.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
 		return nil, errors.Wrap(err, errCannotGetProvider)
 	}
 
+	if err := validate(pc); err != nil {
+		return nil, err
+	}
+
 	t := resource.NewProviderConfigUsageTracker(c, &v1alpha1.ProviderConfigUsage{})
 	if err := t.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, errCannotTrackProviderConfigUsage)
 	}
 
-	if pc.Spec.ClientID.Source != xpv1.CredentialsSourceSecret {
-		return nil, errors.New(errOnlySecretSourceAllowed)
+	return signIn(ctx, c, pc)
+}
+
+// NewConfig signs in to the ZPA tenant of the supplied ProviderConfig with
+// the credentials it references and returns a Config that connects to it.
+func NewConfig(ctx context.Context, c client.Client, pc *v1alpha1.ProviderConfig) (*Config, error) {
+	if err := validate(pc); err != nil {
+		return nil, err
+	}
+	return signIn(ctx, c, pc)
+}
+
+// validate returns an error if the supplied ProviderConfig lacks the
+// customer ID or references its credentials other than by Secrets.
+func validate(pc *v1alpha1.ProviderConfig) error {
+	if pc.Spec.CustomerID == "" {
+		return errors.New(errNoCustomerID)
+	}
+	if pc.Spec.ClientID.Source != xpv1.CredentialsSourceSecret || pc.Spec.ClientSecret.Source != xpv1.CredentialsSourceSecret {
+		return errors.New(errOnlySecretSourceAllowed)
 	}
+	return nil
+}
 
+// signIn signs in to the ZPA tenant of the supplied, valid ProviderConfig.
+func signIn(ctx context.Context, c client.Client, pc *v1alpha1.ProviderConfig) (*Config, error) {
 	clientID, credsErr := extractCredentialsFromSecret(ctx, c, pc.Spec.ClientID.CommonCredentialSelectors)
 	if credsErr != nil {
 		return nil, errors.Wrap(credsErr, errExtractSecret)
 	}
 
-	if pc.Spec.ClientSecret.Source != xpv1.CredentialsSourceSecret {
-		return nil, errors.New(errOnlySecretSourceAllowed)
-	}
-
 	clientSecret, credsErr := extractCredentialsFromSecret(ctx, c, pc.Spec.ClientSecret.CommonCredentialSelectors)
 	if credsErr != nil {
 		return nil, errors.Wrap(credsErr, errExtractSecret)
 	}
 
+	client, err := newHTTPClient(pc.Spec.CABundle)
+	if err != nil {
+		return nil, err
+	}
+
 	/* Authenticate */
-	client := &http.Client{}
 	data := url.Values{}
 	data.Set("client_id", clientID.token)
 	data.Set("client_secret", clientSecret.token)
@@ -125,13 +170,33 @@ func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed
 		return nil, err
 	}
 
-	transport := httptransport.New(pc.Spec.Host, "/", zpa.DefaultSchemes)
+	transport := httptransport.NewWithClient(pc.Spec.Host, "/", zpa.DefaultSchemes, client)
 	transport.DefaultAuthentication = httptransport.BearerToken(creds.AccessToken)
 
 	// Enable this line to see request and response in console output
 	// transport.SetDebug(true)
 
-	return transport, nil
+	return &Config{
+		Transport:  NewErrorDecodingTransport(transport),
+		CustomerID: pc.Spec.CustomerID,
+		Host:       pc.Spec.Host,
+	}, nil
+}
+
+// newHTTPClient returns an HTTP client that verifies the certificate of the
+// ZPA API host with the supplied PEM encoded certificate authorities, or with
+// those of the system if there are none.
+func newHTTPClient(caBundle []byte) (*http.Client, error) {
+	if len(caBundle) == 0 {
+		return &http.Client{}, nil
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caBundle) {
+		return nil, errors.New(errInvalidCABundle)
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	return &http.Client{Transport: t}, nil
 }
 
 type providerCredentials struct {
@@ -163,13 +228,3 @@ func extractCredentialsFromSecret(ctx context.Context, client client.Client, s x
 func closeBody(c io.Closer) {
 	_ = c.Close()
 }
-
-// CustomerID needed for all resources in call
-func CustomerID(ctx context.Context, c client.Client, mg resource.Managed) (string, error) {
-	pc := &v1alpha1.ProviderConfig{}
-	if err := c.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
-		return "", errors.Wrap(err, errCannotGetProvider)
-	}
-
-	return pc.Spec.CustomerID, nil
-}
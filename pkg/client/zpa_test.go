@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	"github.com/crossplane-contrib/provider-zpa/pkg/client/fake"
+)
+
+func TestNewConfigCABundle(t *testing.T) {
+	zpa := fake.NewServer()
+	defer zpa.Close()
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*corev1.Secret).Data = map[string][]byte{
+				"clientID":     []byte(fake.DefaultClientID),
+				"clientSecret": []byte(fake.DefaultClientSecret),
+			}
+			return nil
+		},
+	}
+	credentials := func(key string) v1alpha1.ProviderCredentials {
+		return v1alpha1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+				SecretRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "zpa"}, Key: key},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		reason   string
+		caBundle []byte
+		wantErr  bool
+	}{
+		"Trusted": {
+			reason:   "The certificate of the host should be verified with the CA bundle of the ProviderConfig.",
+			caBundle: zpa.CABundle(),
+		},
+		"SystemRoots": {
+			reason:  "Without a CA bundle the certificate of the host should be verified with the system roots, which do not trust the fake.",
+			wantErr: true,
+		},
+		"Invalid": {
+			reason:   "A CA bundle without a PEM encoded certificate should be an error.",
+			caBundle: []byte("not a certificate"),
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &v1alpha1.ProviderConfig{Spec: v1alpha1.ProviderConfigSpec{
+				ClientID:     credentials("clientID"),
+				ClientSecret: credentials("clientSecret"),
+				CustomerID:   zpa.CustomerID(),
+				Host:         zpa.Host(),
+				CABundle:     tc.caBundle,
+			}}
+
+			cfg, err := zpaclient.NewConfig(context.Background(), kube, pc)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("\n%s\nNewConfig(...): want error %t, got %v", tc.reason, tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.CustomerID != zpa.CustomerID() || cfg.Host != zpa.Host() {
+				t.Errorf("\n%s\nNewConfig(...): want the tenant of the ProviderConfig, got %s at %s", tc.reason, cfg.CustomerID, cfg.Host)
+			}
+		})
+	}
+}
+
+func TestUseProviderConfigNoCustomerID(t *testing.T) {
+	tracked := false
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			if pc, ok := obj.(*v1alpha1.ProviderConfig); ok {
+				pc.Spec.Host = "config.private.zscaler.com"
+			}
+			return nil
+		},
+		MockCreate: func(context.Context, client.Object, ...client.CreateOption) error {
+			tracked = true
+			return nil
+		},
+		MockUpdate: func(context.Context, client.Object, ...client.UpdateOption) error {
+			tracked = true
+			return nil
+		},
+	}
+	mg := &xpfake.Managed{}
+	mg.SetProviderConfigReference(&xpv1.Reference{Name: "default"})
+
+	_, err := zpaclient.UseProviderConfig(context.Background(), kube, mg)
+	if diff := cmp.Diff(errors.New("no customerID is given in the referenced ProviderConfig"), err, test.EquateErrors()); diff != "" {
+		t.Errorf("UseProviderConfig(...): -want error, +got error:\n%s\n", diff)
+	}
+	if tracked {
+		t.Errorf("UseProviderConfig(...): the usage of a ProviderConfig without customerID should not be tracked")
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adapter reconciles managed resources with ZPA objects. It does all
+// that is the same for every kind, e.g. connecting to ZPA, importing by name,
+// resolving name conflicts, reporting drift and merging updates, so that a
+// kind only supplies the conversion of its resources to and from the ZPA API,
+// their comparison and the calls of the ZPA client.
+package adapter
+
+import (
+	"context"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/connection"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	zpa "github.com/haarchri/zpa-go-client/pkg/client"
+
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	"github.com/crossplane-contrib/provider-zpa/pkg/features"
+)
+
+// A Client is the ZPA API client of a managed resource, together with the ZPA
+// customer and the cluster the resource belongs to.
+type Client struct {
+	*zpa.ZscalerPrivateAccessAPIPortal
+
+	// CustomerID is the ID of the ZPA tenant, which is needed for all
+	// operations.
+	CustomerID string
+
+	// Kube is a client of the cluster of the managed resource.
+	Kube client.Client
+}
+
+// NewClient returns a client of the ZPA tenant of the supplied Config.
+func NewClient(cfg *zpaclient.Config, kube client.Client) *Client {
+	return &Client{
+		ZscalerPrivateAccessAPIPortal: zpa.New(cfg.Transport, strfmt.Default),
+		CustomerID:                    cfg.CustomerID,
+		Kube:                          kube,
+	}
+}
+
+// Parameters are the parameters of a managed resource that are the same for
+// every kind.
+type Parameters struct {
+	// Name is the name of the object in ZPA.
+	Name string
+
+	// ImportByName imports an existing object with the same name when no
+	// external name is set.
+	ImportByName bool
+
+	// NameConflictPolicy decides what happens when creating the object
+	// fails because ZPA already has an object with the same name.
+	NameConflictPolicy string
+
+	// IgnoreDrift lists the paths of parameters that are owned outside of
+	// Crossplane.
+	IgnoreDrift []string
+
+	// ObserveOnly only observes the object, and never creates, updates,
+	// deletes or late initializes it.
+	ObserveOnly bool
+}
+
+// A Comparison of the desired state of a managed resource with its object in
+// ZPA.
+type Comparison struct {
+	// Drifted are the JSON names of the modifiable parameters whose desired
+	// value differs from the value in ZPA.
+	Drifted []string
+
+	// Desired and Observed are the parameters of the managed resource and
+	// those of the object in ZPA. They are diffed to describe the drift.
+	Desired  interface{}
+	Observed interface{}
+
+	// Options make the diff treat values as equal that the comparison
+	// does, e.g. port ranges covering the same ports.
+	Options []cmp.Option
+
+	// ModifiedBy and ModifiedTime tell who last modified the object in ZPA
+	// and when.
+	ModifiedBy   string
+	ModifiedTime string
+}
+
+// Fields are the fields of a ZPA object that the provider models.
+type Fields struct {
+	// Modelled are the JSON names in the ZPA API of the fields an update
+	// sets from the parameters. Other fields keep their value in ZPA.
+	Modelled []string
+
+	// Renamed maps the JSON names of the parameters that are named
+	// differently in the ZPA API to their name there.
+	Renamed map[string]string
+}
+
+// Parameters returns the JSON names of the parameters whose fields are
+// modelled, i.e. those ignoreDrift may select.
+func (f Fields) Parameters() []string {
+	apiNames := make(map[string]string, len(f.Renamed))
+	for p, n := range f.Renamed {
+		apiNames[n] = p
+	}
+	params := make([]string, 0, len(f.Modelled))
+	for _, n := range f.Modelled {
+		if p, ok := apiNames[n]; ok {
+			n = p
+		}
+		params = append(params, n)
+	}
+	return params
+}
+
+// A Kind of managed resource R whose objects are of type O in the ZPA API.
+type Kind[R resource.Managed, O any] interface {
+	// Kind is the name of the kind, e.g. SegmentGroup.
+	Kind() string
+
+	// Parameters returns the common parameters of the supplied resource.
+	Parameters(cr R) Parameters
+
+	// Fields returns the fields of the ZPA object the provider models.
+	Fields() Fields
+
+	// ToAPI returns the object desired by the supplied resource.
+	ToAPI(ctx context.Context, c *Client, cr R) (O, error)
+
+	// FromAPI sets the observation of the supplied resource from the
+	// supplied object. The last drift is kept by the adapter.
+	FromAPI(cr R, obj O)
+
+	// LateInitialize sets the unset parameters of the supplied resource
+	// from the supplied object.
+	LateInitialize(cr R, obj O)
+
+	// Compare compares the supplied resource with the supplied object.
+	Compare(cr R, obj O) Comparison
+
+	// LastDrift and SetLastDrift get and set the last drift in the status
+	// of the supplied resource.
+	LastDrift(cr R) *v1alpha1.Drift
+	SetLastDrift(cr R, d *v1alpha1.Drift)
+
+	// Get returns the object with the supplied ID.
+	Get(ctx context.Context, c *Client, id string) (O, error)
+
+	// List returns the objects whose name contains the supplied name.
+	List(ctx context.Context, c *Client, name string) ([]zpaclient.NamedObject, error)
+
+	// Create creates the supplied object and returns its ID.
+	Create(ctx context.Context, c *Client, obj O) (string, error)
+
+	// Update replaces the object with the supplied ID by the supplied
+	// object.
+	Update(ctx context.Context, c *Client, id string, obj O) error
+
+	// Delete deletes the object with the supplied ID of the supplied
+	// resource. It may refuse to, e.g. while other objects use it.
+	Delete(ctx context.Context, c *Client, cr R, id string) error
+
+	// Import returns a resource that manages the supplied object, i.e.
+	// whose external name is its ID and whose parameters are its fields.
+	Import(obj O) R
+}
+
+// An Observer is a Kind that observes more than the object of a resource,
+// e.g. its health. Observe is called after the object was observed.
+type Observer[R resource.Managed, O any] interface {
+	Observe(ctx context.Context, c *Client, cr R, obj O)
+}
+
+// Setup adds a controller that reconciles the managed resources of the
+// supplied kind, whose type is the supplied GroupVersionKind. The supplied
+// object is an empty resource of the kind.
+func Setup[R resource.Managed, O any](mgr ctrl.Manager, o controller.Options, kind Kind[R, O], gvk schema.GroupVersionKind, obj R) error {
+	name := managed.ControllerName(gvk.GroupKind().String())
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
+		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), v1alpha1.StoreConfigGroupVersionKind))
+	}
+
+	var eo []ExternalOption
+	if o.Features.Enabled(features.EnableAlphaObserveOnly) {
+		eo = append(eo, WithObserveOnly())
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(obj).
+		Complete(&reconciler{Reconciler: managed.NewReconciler(manager{Manager: mgr},
+			resource.ManagedKind(gvk),
+			managed.WithExternalConnecter(NewConnector(mgr.GetClient(), kind, recorder, eo...)),
+			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient())),
+			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithRecorder(recorder),
+			managed.WithConnectionPublishers(cps...))})
+}
+
+// NewConnector returns a connector that connects the managed resources of the
+// supplied kind to the ZPA tenant of their ProviderConfig.
+func NewConnector[R resource.Managed, O any](kube client.Client, kind Kind[R, O], recorder event.Recorder, o ...ExternalOption) managed.ExternalConnecter {
+	return &connector[R, O]{kube: kube, kind: kind, newClientFn: zpa.New, recorder: recorder, opts: o}
+}
+
+type connector[R resource.Managed, O any] struct {
+	kube        client.Client
+	kind        Kind[R, O]
+	newClientFn func(transport runtime.ClientTransport, formats strfmt.Registry) *zpa.ZscalerPrivateAccessAPIPortal
+	recorder    event.Recorder
+	opts        []ExternalOption
+}
+
+func (c *connector[R, O]) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(R); !ok {
+		return nil, errNotKindf(c.kind.Kind())
+	}
+
+	cfg, err := zpaclient.GetConfig(ctx, c.kube, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewExternal(&Client{
+		ZscalerPrivateAccessAPIPortal: c.newClientFn(cfg.Transport, strfmt.Default),
+		CustomerID:                    cfg.CustomerID,
+		Kube:                          c.kube,
+	}, c.kind, c.recorder, c.opts...), nil
+}
@@ -0,0 +1,350 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+)
+
+const (
+	errNotKind        = "managed resource is not a %s custom resource"
+	errCreateFailed   = "cannot create %s"
+	errUpdateFailed   = "cannot update %s"
+	errDescribeFailed = "cannot describe %s"
+	errListFailed     = "cannot list %ss"
+	errDeleteFailed   = "cannot delete %s"
+
+	errObserveOnlyDisabled = "%s is observe only, which requires the provider to run with --enable-observe-only"
+	errObserveOnlyMissing  = "%s does not exist in ZPA and is observe only, so it is not created"
+	errUnknownIgnoreDrift  = "ignoreDrift paths %q select none of the parameters of %s: %q"
+)
+
+// messages are the messages errors of a kind are wrapped with.
+type messages struct {
+	create   string
+	update   string
+	describe string
+	list     string
+	delete   string
+}
+
+func newMessages(kind string) messages {
+	return messages{
+		create:   fmt.Sprintf(errCreateFailed, kind),
+		update:   fmt.Sprintf(errUpdateFailed, kind),
+		describe: fmt.Sprintf(errDescribeFailed, kind),
+		list:     fmt.Sprintf(errListFailed, kind),
+		delete:   fmt.Sprintf(errDeleteFailed, kind),
+	}
+}
+
+func errNotKindf(kind string) error {
+	return errors.Errorf(errNotKind, kind)
+}
+
+// An ExternalOption configures an External.
+type ExternalOption func(*externalOptions)
+
+type externalOptions struct {
+	observeOnly bool
+}
+
+// WithObserveOnly allows resources to be observe only, i.e. to only observe
+// their object and report its drift. Observe fails for observe only
+// resources without this option, so that their object is never changed.
+func WithObserveOnly() ExternalOption {
+	return func(o *externalOptions) {
+		o.observeOnly = true
+	}
+}
+
+// NewExternal returns an external client that reconciles the managed
+// resources of the supplied kind using the supplied client.
+func NewExternal[R resource.Managed, O any](c *Client, kind Kind[R, O], recorder event.Recorder, o ...ExternalOption) *External[R, O] {
+	e := &External[R, O]{client: c, kind: kind, recorder: recorder, errs: newMessages(kind.Kind())}
+	for _, fn := range o {
+		fn(&e.opts)
+	}
+	return e
+}
+
+// An External reconciles the managed resources of a kind with their objects
+// in ZPA.
+type External[R resource.Managed, O any] struct {
+	client   *Client
+	kind     Kind[R, O]
+	recorder event.Recorder
+	errs     messages
+	opts     externalOptions
+}
+
+// Observe reads the object of the supplied managed resource, which is looked
+// up by name if it has no external name and imports by name, and reports
+// its state and drift. An observe only resource is reported to be up to date
+// and is not late initialized, and is reported not to exist once it is
+// deleted so that its object is kept. Resources with ignoreDrift paths that
+// select no parameter fail unless they are deleted.
+func (e *External[R, O]) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) { // nolint:gocyclo
+	cr, ok := mg.(R)
+	if !ok {
+		return managed.ExternalObservation{}, errNotKindf(e.kind.Kind())
+	}
+
+	p := e.kind.Parameters(cr)
+	if p.ObserveOnly && meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if p.ObserveOnly && !e.opts.observeOnly {
+		return managed.ExternalObservation{}, errors.Errorf(errObserveOnlyDisabled, e.kind.Kind())
+	}
+	if err := CheckIgnoreDrift(e.kind, cr); err != nil && !meta.WasDeleted(cr) {
+		return managed.ExternalObservation{}, err
+	}
+
+	id := meta.GetExternalName(cr)
+	imported := false
+	if id == "" {
+		if !p.ImportByName {
+			return e.missing(p)
+		}
+
+		found, err := e.findByName(ctx, p.Name)
+		if err != nil {
+			return managed.ExternalObservation{}, e.failed(ctx, cr, err)
+		}
+		meta.SetExternalName(cr, found)
+		id, imported = found, true
+	}
+
+	obj, err := e.kind.Get(ctx, e.client, id)
+	if zpaclient.IsNotFound(err) {
+		return e.missing(p)
+	}
+	if err != nil {
+		return managed.ExternalObservation{ResourceExists: false}, e.failed(ctx, cr, errors.Wrap(err, e.errs.describe))
+	}
+
+	lastDrift := e.kind.LastDrift(cr)
+	e.kind.FromAPI(cr, obj)
+	e.kind.SetLastDrift(cr, lastDrift)
+
+	lateInitialized := false
+	if !p.ObserveOnly {
+		current := cr.DeepCopyObject()
+		e.kind.LateInitialize(cr, obj)
+		lateInitialized = !equality.Semantic.DeepEqual(current, cr)
+	}
+
+	cr.SetConditions(xpv1.Available())
+	if o, ok := e.kind.(Observer[R, O]); ok {
+		o.Observe(ctx, e.client, cr, obj)
+	}
+
+	diffs, c := Drift(e.kind, cr, obj)
+	if len(diffs) > 0 {
+		d := zpaclient.NewDrift(diffs, c.ModifiedBy, c.ModifiedTime)
+		e.kind.SetLastDrift(cr, zpaclient.ReportDrift(e.recorder, cr, lastDrift, d))
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        len(diffs) == 0 || p.ObserveOnly,
+		ResourceLateInitialized: imported || lateInitialized,
+	}, nil
+}
+
+// missing returns the observation of a resource whose object does not exist.
+// An observe only resource fails instead, as its object may not be created.
+func (e *External[R, O]) missing(p Parameters) (managed.ExternalObservation, error) {
+	if p.ObserveOnly {
+		return managed.ExternalObservation{}, errors.Errorf(errObserveOnlyMissing, e.kind.Kind())
+	}
+	return managed.ExternalObservation{ResourceExists: false}, nil
+}
+
+// CheckIgnoreDrift returns an error if any ignoreDrift path of the supplied
+// resource selects no parameter whose field is modelled, so that a typo does
+// not leave the field it was meant to protect to be overwritten.
+func CheckIgnoreDrift[R resource.Managed, O any](kind Kind[R, O], cr R) error {
+	params := kind.Fields().Parameters()
+	if unknown := zpaclient.UnknownIgnored(kind.Parameters(cr).IgnoreDrift, params); len(unknown) > 0 {
+		return errors.Errorf(errUnknownIgnoreDrift, unknown, kind.Kind(), params)
+	}
+	return nil
+}
+
+// Drift returns the differences of the parameters of the supplied resource
+// from the supplied object, except those of ignored parameters, together with
+// the comparison they were found by. The resource is up to date if there are
+// none.
+func Drift[R resource.Managed, O any](kind Kind[R, O], cr R, obj O) ([]v1alpha1.FieldDrift, Comparison) {
+	c := kind.Compare(cr, obj)
+	drift := zpaclient.WithoutIgnored(c.Drifted, kind.Parameters(cr).IgnoreDrift)
+	if len(drift) == 0 {
+		return nil, c
+	}
+	return zpaclient.Diff(c.Desired, c.Observed, drift, c.Options...), c
+}
+
+// Create creates the object of the supplied managed resource and sets its ID
+// as external name. It refuses to for an observe only resource.
+func (e *External[R, O]) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(R)
+	if !ok {
+		return managed.ExternalCreation{}, errNotKindf(e.kind.Kind())
+	}
+	if e.kind.Parameters(cr).ObserveOnly {
+		return managed.ExternalCreation{}, errors.Errorf(errObserveOnlyMissing, e.kind.Kind())
+	}
+
+	obj, err := e.kind.ToAPI(ctx, e.client, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, e.errs.create)
+	}
+
+	id, err := e.kind.Create(ctx, e.client, obj)
+	if err != nil {
+		creation, err := e.createFailed(ctx, cr, err)
+		return creation, e.failed(ctx, cr, err)
+	}
+
+	meta.SetExternalName(cr, id)
+	return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+}
+
+// Update replaces the object of the supplied managed resource by the object
+// it desires, merged with the fields the provider does not model. Objects of
+// observe only resources are not updated.
+func (e *External[R, O]) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(R)
+	if !ok {
+		return managed.ExternalUpdate{}, errNotKindf(e.kind.Kind())
+	}
+	if e.kind.Parameters(cr).ObserveOnly {
+		return managed.ExternalUpdate{}, nil
+	}
+
+	obj, err := e.kind.ToAPI(ctx, e.client, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, e.errs.update)
+	}
+
+	id := meta.GetExternalName(cr)
+	if err := Merge(ctx, e.kind, e.client, cr, id, obj); err != nil {
+		return managed.ExternalUpdate{}, e.failed(ctx, cr, errors.Wrap(err, e.errs.update))
+	}
+
+	if err := e.kind.Update(ctx, e.client, id, obj); err != nil {
+		return managed.ExternalUpdate{}, e.failed(ctx, cr, errors.Wrap(err, e.errs.update))
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete deletes the object of the supplied managed resource. An object that
+// does not exist, or was never created, is deleted. Objects of observe only
+// resources are kept.
+func (e *External[R, O]) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(R)
+	if !ok {
+		return errNotKindf(e.kind.Kind())
+	}
+
+	id := meta.GetExternalName(cr)
+	if id == "" || e.kind.Parameters(cr).ObserveOnly {
+		return nil
+	}
+
+	err := resource.Ignore(zpaclient.IsNotFound, e.kind.Delete(ctx, e.client, cr, id))
+	return e.failed(ctx, cr, errors.Wrap(err, e.errs.delete))
+}
+
+// failed records the reason of the supplied error the ZPA API failed with as
+// the reason the supplied managed resource is not synced, together with the
+// delay after which it is reconciled again if ZPA throttled the call, and
+// emits it as a warning event. Other errors are returned as they are.
+func (e *External[R, O]) failed(ctx context.Context, cr R, err error) error {
+	reason := zpaclient.Reason(err)
+	if reason == "" {
+		return err
+	}
+	setOutcome(ctx, reason, zpaclient.RetryAfter(err))
+	e.recorder.Event(cr, event.Warning(event.Reason(reason), err))
+	return err
+}
+
+// Merge overlays the modelled fields of the supplied update payload of the
+// supplied resource onto the object with the supplied ID as it is in ZPA.
+// Fields the provider does not model, and parameters listed in ignoreDrift,
+// keep their value in ZPA. Every update of an object must be merged.
+func Merge[R resource.Managed, O any](ctx context.Context, kind Kind[R, O], c *Client, cr R, id string, payload O) error {
+	live, err := kind.Get(ctx, c, id)
+	if err != nil {
+		return errors.Wrapf(err, errDescribeFailed, kind.Kind())
+	}
+
+	f := kind.Fields()
+	fields := zpaclient.WithoutIgnored(f.Modelled, zpaclient.APIFields(kind.Parameters(cr).IgnoreDrift, f.Renamed))
+	return zpaclient.Overlay(payload, live, fields)
+}
+
+// findByName returns the ID of the only object with the supplied name.
+func (e *External[R, O]) findByName(ctx context.Context, name string) (string, error) {
+	objs, err := e.kind.List(ctx, e.client, name)
+	if err != nil {
+		return "", errors.Wrap(err, e.errs.list)
+	}
+
+	return zpaclient.FindIDByName(e.kind.Kind(), name, objs)
+}
+
+// createFailed handles a failed creation of the supplied managed resource. If
+// the creation failed because another object with the same name exists, it is
+// either adopted or returned as a NameConflictError according to the
+// NameConflictPolicy.
+func (e *External[R, O]) createFailed(ctx context.Context, cr R, createErr error) (managed.ExternalCreation, error) {
+	if !zpaclient.IsDuplicate(createErr) {
+		return managed.ExternalCreation{}, errors.Wrap(createErr, e.errs.create)
+	}
+
+	p := e.kind.Parameters(cr)
+	objs, err := e.kind.List(ctx, e.client, p.Name)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(createErr, e.errs.create)
+	}
+
+	id, err := zpaclient.ResolveNameConflict(e.kind.Kind(), p.Name, p.NameConflictPolicy, objs, createErr)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrap(err, e.errs.create)
+	}
+
+	meta.SetExternalName(cr, id)
+	return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+}
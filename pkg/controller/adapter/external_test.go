@@ -0,0 +1,409 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+)
+
+const id = "72058000000000001"
+
+var (
+	errBoom     = errors.New("boom")
+	errNotFound = &zpaclient.APIError{StatusCode: http.StatusNotFound, Class: zpaclient.ErrorClassNotFound}
+	errConflict = &zpaclient.APIError{StatusCode: http.StatusBadRequest, ID: "duplicate.item", Class: zpaclient.ErrorClassConflict}
+	errInvalid  = &zpaclient.APIError{StatusCode: http.StatusBadRequest, ID: "invalid.port.range", Class: zpaclient.ErrorClassValidation}
+	errThrottle = &zpaclient.APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: 5 * time.Second, Class: zpaclient.ErrorClassThrottled}
+)
+
+type object struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// testKind is a Kind whose operations return the supplied errors.
+type testKind struct {
+	params    Parameters
+	getErr    error
+	list      []zpaclient.NamedObject
+	drifted   []string
+	lateInit  bool
+	createErr error
+	deleteErr error
+	deleted   []string
+}
+
+func (k *testKind) Kind() string                                           { return "Test" }
+func (k *testKind) Parameters(*fake.Managed) Parameters                    { return k.params }
+func (k *testKind) Fields() Fields                                         { return Fields{Modelled: []string{"name", "description"}} }
+func (k *testKind) FromAPI(*fake.Managed, *object)                         {}
+func (k *testKind) LastDrift(*fake.Managed) *v1alpha1.Drift                { return nil }
+func (k *testKind) SetLastDrift(*fake.Managed, *v1alpha1.Drift)            {}
+func (k *testKind) Update(context.Context, *Client, string, *object) error { return nil }
+func (k *testKind) Import(*object) *fake.Managed                           { return &fake.Managed{} }
+
+func (k *testKind) ToAPI(context.Context, *Client, *fake.Managed) (*object, error) {
+	return &object{Name: k.params.Name}, nil
+}
+
+func (k *testKind) Get(_ context.Context, _ *Client, id string) (*object, error) {
+	if k.getErr != nil {
+		return nil, k.getErr
+	}
+	return &object{ID: id, Name: k.params.Name}, nil
+}
+
+func (k *testKind) LateInitialize(cr *fake.Managed, _ *object) {
+	if k.lateInit {
+		meta.AddLabels(cr, map[string]string{"late": "initialized"})
+	}
+}
+
+func (k *testKind) Compare(*fake.Managed, *object) Comparison {
+	return Comparison{Drifted: k.drifted, Desired: &object{Description: "desired"}, Observed: &object{}}
+}
+
+func (k *testKind) List(context.Context, *Client, string) ([]zpaclient.NamedObject, error) {
+	return k.list, nil
+}
+
+func (k *testKind) Create(context.Context, *Client, *object) (string, error) {
+	if k.createErr != nil {
+		return "", k.createErr
+	}
+	return id, nil
+}
+
+func (k *testKind) Delete(_ context.Context, _ *Client, _ *fake.Managed, id string) error {
+	k.deleted = append(k.deleted, id)
+	return k.deleteErr
+}
+
+func managedWithExternalName(name string) *fake.Managed {
+	mg := &fake.Managed{}
+	meta.SetExternalName(mg, name)
+	return mg
+}
+
+func deletedWithExternalName(name string) *fake.Managed {
+	mg := managedWithExternalName(name)
+	now := metav1.Now()
+	mg.SetDeletionTimestamp(&now)
+	return mg
+}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		result       managed.ExternalObservation
+		externalName string
+		outcome      outcome
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kind   *testKind
+		opts   []ExternalOption
+		mg     *fake.Managed
+		want   want
+	}{
+		"NoExternalName": {
+			reason: "A resource without external name that does not import by name does not exist.",
+			kind:   &testKind{},
+			mg:     managedWithExternalName(""),
+			want:   want{result: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"ImportByName": {
+			reason: "A resource that imports by name should adopt the object with its name.",
+			kind: &testKind{
+				params: Parameters{Name: "example", ImportByName: true},
+				list:   []zpaclient.NamedObject{{ID: id, Name: "example"}, {ID: "72058000000000002", Name: "example-2"}},
+			},
+			mg: managedWithExternalName(""),
+			want: want{
+				result:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				externalName: id,
+			},
+		},
+		"NotFoundByName": {
+			reason: "A resource that imports by a name no object has should fail with reason NotFoundByName.",
+			kind: &testKind{
+				params: Parameters{Name: "example", ImportByName: true},
+				list:   []zpaclient.NamedObject{{ID: "72058000000000002", Name: "example-2"}},
+			},
+			mg: managedWithExternalName(""),
+			want: want{
+				outcome: outcome{reason: zpaclient.ReasonNotFoundByName},
+				err:     &zpaclient.NotFoundByNameError{Kind: "Test", Name: "example"},
+			},
+		},
+		"AmbiguousName": {
+			reason: "A resource that imports by a name several objects have should fail with reason AmbiguousName.",
+			kind: &testKind{
+				params: Parameters{Name: "example", ImportByName: true},
+				list:   []zpaclient.NamedObject{{ID: id, Name: "example"}, {ID: "72058000000000002", Name: "example"}},
+			},
+			mg: managedWithExternalName(""),
+			want: want{
+				outcome: outcome{reason: zpaclient.ReasonAmbiguousName},
+				err:     &zpaclient.AmbiguousNameError{Kind: "Test", Name: "example", IDs: []string{id, "72058000000000002"}},
+			},
+		},
+		"NotFound": {
+			reason: "A resource whose object is missing in ZPA does not exist.",
+			kind:   &testKind{getErr: errNotFound},
+			mg:     managedWithExternalName(id),
+			want:   want{result: managed.ExternalObservation{ResourceExists: false}, externalName: id},
+		},
+		"DescribeFailed": {
+			reason: "Errors reading the object should be wrapped.",
+			kind:   &testKind{getErr: errBoom},
+			mg:     managedWithExternalName(id),
+			want:   want{err: errors.Wrap(errBoom, "cannot describe Test"), externalName: id},
+		},
+		"Drift": {
+			reason: "A resource whose object drifted is not up to date.",
+			kind:   &testKind{drifted: []string{"description"}},
+			mg:     managedWithExternalName(id),
+			want:   want{result: managed.ExternalObservation{ResourceExists: true}, externalName: id},
+		},
+		"ObserveOnlyDisabled": {
+			reason: "An observe only resource should fail unless observe only is enabled.",
+			kind:   &testKind{params: Parameters{ObserveOnly: true}},
+			mg:     managedWithExternalName(id),
+			want:   want{err: errors.Errorf(errObserveOnlyDisabled, "Test"), externalName: id},
+		},
+		"UnknownIgnoreDrift": {
+			reason: "A resource with an ignoreDrift path that selects no parameter should fail rather than overwrite the field.",
+			kind:   &testKind{params: Parameters{IgnoreDrift: []string{"description", "spec.forProvider.descripton"}}},
+			mg:     managedWithExternalName(id),
+			want: want{
+				err:          errors.Errorf(errUnknownIgnoreDrift, []string{"spec.forProvider.descripton"}, "Test", []string{"name", "description"}),
+				externalName: id,
+			},
+		},
+		"UnknownIgnoreDriftDeleted": {
+			reason: "A deleted resource with an ignoreDrift path that selects no parameter should still be observed, so that it can be deleted.",
+			kind:   &testKind{params: Parameters{IgnoreDrift: []string{"descripton"}}, getErr: errNotFound},
+			mg:     deletedWithExternalName(id),
+			want:   want{result: managed.ExternalObservation{ResourceExists: false}, externalName: id},
+		},
+		"ObserveOnlyDrift": {
+			reason: "An observe only resource whose object drifted should be up to date and not be late initialized.",
+			kind:   &testKind{params: Parameters{ObserveOnly: true}, drifted: []string{"description"}, lateInit: true},
+			opts:   []ExternalOption{WithObserveOnly()},
+			mg:     managedWithExternalName(id),
+			want:   want{result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, externalName: id},
+		},
+		"ObserveOnlyNotFound": {
+			reason: "An observe only resource whose object is missing should fail rather than create it.",
+			kind:   &testKind{params: Parameters{ObserveOnly: true}, getErr: errNotFound},
+			opts:   []ExternalOption{WithObserveOnly()},
+			mg:     managedWithExternalName(id),
+			want:   want{err: errors.Errorf(errObserveOnlyMissing, "Test"), externalName: id},
+		},
+		"ObserveOnlyDeleted": {
+			reason: "A deleted observe only resource should not exist, so that its object is kept.",
+			kind:   &testKind{params: Parameters{ObserveOnly: true}},
+			opts:   []ExternalOption{WithObserveOnly()},
+			mg:     deletedWithExternalName(id),
+			want:   want{result: managed.ExternalObservation{ResourceExists: false}, externalName: id},
+		},
+		"ObserveOnlyDeletedDisabled": {
+			reason: "A deleted observe only resource should not exist even if observe only is disabled, so that its deletion does not hang.",
+			kind:   &testKind{params: Parameters{ObserveOnly: true}},
+			mg:     deletedWithExternalName(id),
+			want:   want{result: managed.ExternalObservation{ResourceExists: false}, externalName: id},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewExternal[*fake.Managed, *object](&Client{}, tc.kind, event.NewNopRecorder(), tc.opts...)
+			o := &outcome{}
+			ctx := context.WithValue(context.Background(), outcomeKey{}, o)
+			got, err := e.Observe(ctx, tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.outcome, *o, cmp.AllowUnexported(outcome{})); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want outcome, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.mg)); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want external name, +got:\n%s\n", tc.reason, diff)
+			}
+			if tc.kind.params.ObserveOnly && len(tc.mg.GetLabels()) > 0 {
+				t.Errorf("\n%s\ne.Observe(...): observe only resource should not be late initialized", tc.reason)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		result       managed.ExternalCreation
+		externalName string
+		reason       xpv1.ConditionReason
+		err          error
+	}
+
+	conflict := &zpaclient.NameConflictError{Kind: "Test", Name: "example", IDs: []string{id}}
+	taken := []zpaclient.NamedObject{{ID: id, Name: "example"}}
+
+	cases := map[string]struct {
+		reason string
+		kind   *testKind
+		want   want
+	}{
+		"Success": {
+			reason: "The ID of the created object should be the external name.",
+			kind:   &testKind{params: Parameters{Name: "example"}},
+			want:   want{result: managed.ExternalCreation{ExternalNameAssigned: true}, externalName: id},
+		},
+		"NameConflict": {
+			reason: "A name conflict should be reported with reason NameConflict.",
+			kind:   &testKind{params: Parameters{Name: "example"}, createErr: errConflict, list: taken},
+			want:   want{reason: zpaclient.ReasonNameConflict, err: errors.Wrap(conflict, "cannot create Test")},
+		},
+		"NameConflictAdopt": {
+			reason: "A name conflict should adopt the existing object if the policy says so.",
+			kind:   &testKind{params: Parameters{Name: "example", NameConflictPolicy: zpaclient.NameConflictPolicyAdopt}, createErr: errConflict, list: taken},
+			want:   want{result: managed.ExternalCreation{ExternalNameAssigned: true}, externalName: id},
+		},
+		"InvalidNotAdopted": {
+			reason: "An invalid request that does not report a duplicate should not adopt an object with the same name, and should be reported with reason InvalidRequest.",
+			kind:   &testKind{params: Parameters{Name: "example", NameConflictPolicy: zpaclient.NameConflictPolicyAdopt}, createErr: errInvalid, list: taken},
+			want:   want{reason: zpaclient.ReasonInvalidRequest, err: errors.Wrap(errInvalid, "cannot create Test")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &outcome{}
+			ctx := context.WithValue(context.Background(), outcomeKey{}, o)
+			mg := managedWithExternalName("")
+
+			e := NewExternal[*fake.Managed, *object](&Client{}, tc.kind, event.NewNopRecorder())
+			got, err := e.Create(ctx, mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(mg)); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want external name, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reason, o.reason); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want reason, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type want struct {
+		deleted []string
+		err     error
+		outcome outcome
+	}
+
+	cases := map[string]struct {
+		reason string
+		kind   *testKind
+		mg     *fake.Managed
+		want   want
+	}{
+		"Success": {
+			reason: "The object of the resource should be deleted.",
+			kind:   &testKind{},
+			mg:     managedWithExternalName(id),
+			want:   want{deleted: []string{id}},
+		},
+		"NoExternalName": {
+			reason: "A resource whose object was never created has nothing to delete.",
+			kind:   &testKind{},
+			mg:     managedWithExternalName(""),
+		},
+		"NotFound": {
+			reason: "An object that is already gone should be deleted successfully.",
+			kind:   &testKind{deleteErr: errors.Wrap(errNotFound, "cannot do something first")},
+			mg:     managedWithExternalName(id),
+			want:   want{deleted: []string{id}},
+		},
+		"DeleteFailed": {
+			reason: "Errors deleting the object should be wrapped.",
+			kind:   &testKind{deleteErr: errBoom},
+			mg:     managedWithExternalName(id),
+			want:   want{deleted: []string{id}, err: errors.Wrap(errBoom, "cannot delete Test")},
+		},
+		"Throttled": {
+			reason: "A throttled deletion should record the Throttled reason and the delay ZPA asked for.",
+			kind:   &testKind{deleteErr: errThrottle},
+			mg:     managedWithExternalName(id),
+			want: want{
+				deleted: []string{id},
+				err:     errors.Wrap(errThrottle, "cannot delete Test"),
+				outcome: outcome{reason: zpaclient.ReasonThrottled, retryAfter: 5 * time.Second},
+			},
+		},
+		"ObserveOnly": {
+			reason: "The object of an observe only resource should be kept.",
+			kind:   &testKind{params: Parameters{ObserveOnly: true}},
+			mg:     managedWithExternalName(id),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &outcome{}
+			ctx := context.WithValue(context.Background(), outcomeKey{}, o)
+			e := NewExternal[*fake.Managed, *object](&Client{}, tc.kind, event.NewNopRecorder())
+			err := e.Delete(ctx, tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.outcome, *o, cmp.AllowUnexported(outcome{})); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want outcome, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, tc.kind.deleted); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want deleted, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// An outcome of a reconcile that the managed reconciler cannot express. The
+// managed reconciler sets the reason of the Synced condition of every failed
+// reconcile to ReconcileError.
+type outcome struct {
+	// reason the resource is not synced.
+	reason xpv1.ConditionReason
+
+	// retryAfter is the delay after which the resource is reconciled again,
+	// if ZPA throttled the reconcile.
+	retryAfter time.Duration
+}
+
+type outcomeKey struct{}
+
+// setOutcome records the reason the resource of the reconcile of the supplied
+// context is not synced and the delay after which it is reconciled again, if
+// the reconcile records its outcome.
+func setOutcome(ctx context.Context, reason xpv1.ConditionReason, retryAfter time.Duration) {
+	if o, ok := ctx.Value(outcomeKey{}).(*outcome); ok {
+		o.reason = reason
+		o.retryAfter = retryAfter
+	}
+}
+
+// A reconciler records the outcome of every reconcile of the managed
+// reconciler it wraps. It requeues a throttled reconcile after the delay ZPA
+// asked for rather than after the backoff of the managed reconciler.
+type reconciler struct {
+	reconcile.Reconciler
+}
+
+func (r *reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	o := &outcome{}
+	result, err := r.Reconciler.Reconcile(context.WithValue(ctx, outcomeKey{}, o), req)
+	if err == nil && o.retryAfter > 0 {
+		return reconcile.Result{RequeueAfter: o.retryAfter}, nil
+	}
+	return result, err
+}
+
+// A manager is the manager of the managed reconciler. Its client sets the
+// recorded reason on the Synced condition of the resources whose status the
+// managed reconciler updates after a reconcile failed.
+type manager struct {
+	ctrl.Manager
+}
+
+func (m manager) GetClient() client.Client {
+	return statusClient{Client: m.Manager.GetClient()}
+}
+
+type statusClient struct {
+	client.Client
+}
+
+func (c statusClient) Status() client.StatusWriter {
+	return statusWriter{StatusWriter: c.Client.Status()}
+}
+
+type statusWriter struct {
+	client.StatusWriter
+}
+
+func (w statusWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	setSyncedReason(ctx, obj)
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+// setSyncedReason replaces the reason of a ReconcileError Synced condition of
+// the supplied object by the reason recorded during the reconcile of the
+// supplied context.
+func setSyncedReason(ctx context.Context, obj client.Object) {
+	o, ok := ctx.Value(outcomeKey{}).(*outcome)
+	if !ok || o.reason == "" {
+		return
+	}
+	cr, ok := obj.(resource.Conditioned)
+	if !ok {
+		return
+	}
+	if c := cr.GetCondition(xpv1.TypeSynced); c.Reason == xpv1.ReasonReconcileError {
+		c.Reason = o.reason
+		cr.SetConditions(c)
+	}
+}
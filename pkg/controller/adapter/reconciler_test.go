@@ -0,0 +1,146 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+)
+
+// reconcileFn is a function that implements reconcile.Reconciler.
+type reconcileFn func(ctx context.Context, req reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return fn(ctx, req)
+}
+
+func TestReconcilerSyncedReason(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		recorded  xpv1.ConditionReason
+		condition xpv1.Condition
+		want      xpv1.ConditionReason
+	}{
+		"ReconcileError": {
+			reason:    "A recorded reason should replace the reason of a ReconcileError Synced condition.",
+			recorded:  zpaclient.ReasonNameConflict,
+			condition: xpv1.ReconcileError(errBoom),
+			want:      zpaclient.ReasonNameConflict,
+		},
+		"NothingRecorded": {
+			reason:    "A ReconcileError Synced condition should be kept if no reason was recorded.",
+			condition: xpv1.ReconcileError(errBoom),
+			want:      xpv1.ReasonReconcileError,
+		},
+		"ReconcileSuccess": {
+			reason:    "A recorded reason should not change a successful Synced condition.",
+			recorded:  zpaclient.ReasonNameConflict,
+			condition: xpv1.ReconcileSuccess(),
+			want:      xpv1.ReasonReconcileSuccess,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updated xpv1.Condition
+			kube := statusClient{Client: &test.MockClient{
+				MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					updated = obj.(*fake.Managed).GetCondition(xpv1.TypeSynced)
+					return nil
+				},
+			}}
+
+			r := &reconciler{Reconciler: reconcileFn(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				if tc.recorded != "" {
+					setOutcome(ctx, tc.recorded, 0)
+				}
+				mg := &fake.Managed{}
+				mg.SetConditions(tc.condition)
+				return reconcile.Result{Requeue: true}, errors.Wrap(kube.Status().Update(ctx, mg), "cannot update status")
+			})}
+
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+				t.Fatalf("r.Reconcile(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, updated.Reason); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want reason, +got reason:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.condition.Message, updated.Message); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want message, +got message:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReconcilerRetryAfter(t *testing.T) {
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason     string
+		retryAfter time.Duration
+		err        error
+		want       want
+	}{
+		"Throttled": {
+			reason:     "A throttled reconcile should be requeued after the delay ZPA asked for.",
+			retryAfter: 5 * time.Second,
+			want:       want{result: reconcile.Result{RequeueAfter: 5 * time.Second}},
+		},
+		"NotThrottled": {
+			reason: "A reconcile that was not throttled should keep the result of the managed reconciler.",
+			want:   want{result: reconcile.Result{Requeue: true}},
+		},
+		"Error": {
+			reason:     "A reconcile that returns an error should keep its result.",
+			retryAfter: 5 * time.Second,
+			err:        errBoom,
+			want:       want{result: reconcile.Result{Requeue: true}, err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &reconciler{Reconciler: reconcileFn(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				setOutcome(ctx, zpaclient.ReasonThrottled, tc.retryAfter)
+				return reconcile.Result{Requeue: true}, tc.err
+			})}
+
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
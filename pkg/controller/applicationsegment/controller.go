@@ -15,330 +15,338 @@ package application
 
 import (
 	"context"
+	"strconv"
 
-	"github.com/go-openapi/runtime"
-	"github.com/go-openapi/strfmt"
 	"github.com/google/go-cmp/cmp"
-
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
-	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
-	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
-	zpa "github.com/haarchri/zpa-go-client/pkg/client"
 	"github.com/haarchri/zpa-go-client/pkg/client/application_controller"
 	"github.com/haarchri/zpa-go-client/pkg/client/server_group_controller"
 	"github.com/haarchri/zpa-go-client/pkg/models"
 
 	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
-	store "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpav1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
 	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
-	"github.com/crossplane-contrib/provider-zpa/pkg/features"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
 )
 
 const (
-	errNotApplicationSegment = "managed resource is not an ApplicationSegment custom resource"
-	errCreateFailed          = "cannot create ApplicationSegment"
-	errDescribeFailed        = "cannot describe ApplicationSegment"
-	errUpdateFailed          = "cannot update ApplicationSegment"
-	errDeleteFailed          = "cannot delete ApplicationSegment"
-	errServerGroupNotFound   = "cannot get ServerGroup"
+	errServerGroupNotFound = "cannot get ServerGroup"
+	errInvalidTCPPortRange = "invalid tcpPortRange"
+	errInvalidUDPPortRange = "invalid udpPortRange"
 )
 
 // SetupApplicationSegment adds a controller that reconciles ApplicationSegments.
 func SetupApplicationSegment(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha1.ApplicationSegmentGroupKind)
-
-	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
-	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
-		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), store.StoreConfigGroupVersionKind))
-	}
-
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		WithOptions(o.ForControllerRuntime()).
-		For(&v1alpha1.ApplicationSegment{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha1.ApplicationSegmentGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), newClientFn: zpa.New}),
-			managed.WithConnectionPublishers(),
-			managed.WithInitializers(),
-			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
-			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithConnectionPublishers(cps...)))
+	return adapter.Setup[*v1alpha1.ApplicationSegment, *models.ApplicationResource](mgr, o, kind{}, v1alpha1.ApplicationSegmentGroupVersionKind, &v1alpha1.ApplicationSegment{})
 }
 
-type connector struct {
-	kube        client.Client
-	newClientFn func(transport runtime.ClientTransport, formats strfmt.Registry) *zpa.ZscalerPrivateAccessAPIPortal
+// NewKind returns the kind that reconciles ApplicationSegments.
+func NewKind() adapter.Kind[*v1alpha1.ApplicationSegment, *models.ApplicationResource] {
+	return kind{}
 }
 
-type external struct {
-	client *zpa.ZscalerPrivateAccessAPIPortal
-	kube   client.Client
+// kind reconciles ApplicationSegments with ZPA applications.
+type kind struct{}
+
+func (kind) Kind() string { return v1alpha1.ApplicationSegmentKind }
+
+func (kind) Parameters(cr *v1alpha1.ApplicationSegment) adapter.Parameters {
+	p := cr.Spec.ForProvider
+	return adapter.Parameters{
+		Name:               p.Name,
+		ImportByName:       zpaclient.BoolValue(p.ImportByName),
+		NameConflictPolicy: p.NameConflictPolicy,
+		IgnoreDrift:        p.IgnoreDrift,
+		ObserveOnly:        zpaclient.BoolValue(p.ObserveOnly),
+	}
 }
 
-func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	_, ok := mg.(*v1alpha1.ApplicationSegment)
-	if !ok {
-		return nil, errors.New(errNotApplicationSegment)
+// Fields are the JSON names in the ZPA API of the fields Update sets from the
+// parameters of an ApplicationSegment.
+func (kind) Fields() adapter.Fields {
+	return adapter.Fields{
+		Modelled: []string{
+			"bypassType",
+			"configSpace",
+			"defaultIdleTimeout",
+			"defaultMaxAge",
+			"description",
+			"domainNames",
+			"doubleEncrypt",
+			"enabled",
+			"healthCheckType",
+			"healthReporting",
+			"icmpAccessType",
+			"ipAnchored",
+			"isCnameEnabled",
+			"name",
+			"passiveHealthEnabled",
+			"segmentGroupId",
+			"serverGroups",
+			"tcpPortRanges",
+			"udpPortRanges",
+		},
+		Renamed: map[string]string{
+			"segmentGroupID": "segmentGroupId",
+			"tcpPortRange":   "tcpPortRanges",
+			"udpPortRange":   "udpPortRanges",
+		},
 	}
+}
 
-	cfg, err := zpaclient.GetConfig(ctx, c.kube, mg)
+func (kind) ToAPI(ctx context.Context, c *adapter.Client, cr *v1alpha1.ApplicationSegment) (*models.ApplicationResource, error) {
+	p, err := ConvertDeprecatedPortRanges(cr.Spec.ForProvider)
 	if err != nil {
 		return nil, err
 	}
 
-	client := c.newClientFn(cfg, strfmt.Default)
-	return &external{client, c.kube}, nil
-}
-
-func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.ApplicationSegment)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotApplicationSegment)
+	if err := ValidatePortRanges(p.TCPPortRange); err != nil {
+		return nil, errors.Wrap(err, errInvalidTCPPortRange)
 	}
 
-	id := meta.GetExternalName(cr)
-	if id == "" {
-		return managed.ExternalObservation{
-			ResourceExists:   false,
-			ResourceUpToDate: false,
-		}, nil
+	if err := ValidatePortRanges(p.UDPPortRange); err != nil {
+		return nil, errors.Wrap(err, errInvalidUDPPortRange)
 	}
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
-	req := &application_controller.GetApplicationUsingGET1Params{
-		Context:       ctx,
-		ApplicationID: id,
-		CustomerID:    customerID,
-	}
-	resp, reqErr := e.client.ApplicationController.GetApplicationUsingGET1(req)
-	if reqErr != nil {
-		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(resource.Ignore(IsNotFound, reqErr), errDescribeFailed)
+	obj := &models.ApplicationResource{
+		BypassType:           cr.Spec.ForProvider.BypassType,
+		ConfigSpace:          cr.Spec.ForProvider.ConfigSpace,
+		DefaultIdleTimeout:   cr.Spec.ForProvider.DefaultIdleTimeout,
+		DefaultMaxAge:        cr.Spec.ForProvider.DefaultMaxAge,
+		Description:          cr.Spec.ForProvider.Description,
+		DomainNames:          cr.Spec.ForProvider.DomainNames,
+		DoubleEncrypt:        zpaclient.BoolValue(cr.Spec.ForProvider.DoubleEncrypt),
+		Enabled:              zpaclient.BoolValue(cr.Spec.ForProvider.Enabled),
+		HealthCheckType:      cr.Spec.ForProvider.HealthCheckType,
+		HealthReporting:      cr.Spec.ForProvider.HealthReporting,
+		IcmpAccessType:       cr.Spec.ForProvider.IcmpAccessType,
+		IPAnchored:           zpaclient.BoolValue(cr.Spec.ForProvider.IPAnchored),
+		IsCnameEnabled:       zpaclient.BoolValue(cr.Spec.ForProvider.IsCnameEnabled),
+		Name:                 cr.Spec.ForProvider.Name,
+		PassiveHealthEnabled: zpaclient.BoolValue(cr.Spec.ForProvider.PassiveHealthEnabled),
+		SegmentGroupID:       zpaclient.StringValue(cr.Spec.ForProvider.SegmentGroupID),
+		TCPPortRanges:        PortRangesToAPI(p.TCPPortRange),
+		UDPPortRanges:        PortRangesToAPI(p.UDPPortRange),
+		ServerGroups:         make([]*models.AppServerGroup, 0),
 	}
 
-	cr.Status.AtProvider = generateObservation(resp)
+	for _, id := range cr.Spec.ForProvider.ServerGroups {
+		req := &server_group_controller.GetServerGroupUsingGET1Params{
+			Context:    ctx,
+			CustomerID: c.CustomerID,
+			GroupID:    id,
+		}
+		resp, err := c.ServerGroupController.GetServerGroupUsingGET1(req)
+		if err != nil {
+			return nil, errors.Wrap(err, errServerGroupNotFound)
+		}
 
-	currentSpec := cr.Spec.ForProvider.DeepCopy()
-	e.LateInitialize(cr, resp)
+		obj.ServerGroups = append(obj.ServerGroups, &models.AppServerGroup{
+			ID:   id,
+			Name: zpaclient.String(resp.Payload.Name),
+		})
+	}
 
-	cr.Status.SetConditions(v1.Available())
+	return obj, nil
+}
 
-	return managed.ExternalObservation{
-		ResourceExists:          true,
-		ResourceUpToDate:        isUpToDate(&cr.Spec.ForProvider, resp),
-		ResourceLateInitialized: !cmp.Equal(&cr.Spec.ForProvider, currentSpec),
-	}, nil
+func (kind) FromAPI(cr *v1alpha1.ApplicationSegment, obj *models.ApplicationResource) {
+	cr.Status.AtProvider = generateObservation(obj)
 }
 
-func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.ApplicationSegment)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotApplicationSegment)
+func (kind) LateInitialize(cr *v1alpha1.ApplicationSegment, obj *models.ApplicationResource) { // nolint:gocyclo
+	if cr.Spec.ForProvider.Enabled == nil {
+		cr.Spec.ForProvider.Enabled = zpaclient.Bool(obj.Enabled)
 	}
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
-	req := &application_controller.AddApplicationUsingPOST1Params{
-		Context:    ctx,
-		CustomerID: customerID,
-		Application: &models.ApplicationResource{
-			BypassType:           cr.Spec.ForProvider.BypassType,
-			ConfigSpace:          cr.Spec.ForProvider.ConfigSpace,
-			DefaultIdleTimeout:   cr.Spec.ForProvider.DefaultIdleTimeout,
-			DefaultMaxAge:        cr.Spec.ForProvider.DefaultMaxAge,
-			Description:          cr.Spec.ForProvider.Description,
-			DomainNames:          cr.Spec.ForProvider.DomainNames,
-			DoubleEncrypt:        zpaclient.BoolValue(cr.Spec.ForProvider.DoubleEncrypt),
-			Enabled:              zpaclient.BoolValue(cr.Spec.ForProvider.Enabled),
-			HealthCheckType:      cr.Spec.ForProvider.HealthCheckType,
-			HealthReporting:      cr.Spec.ForProvider.HealthReporting,
-			IcmpAccessType:       cr.Spec.ForProvider.IcmpAccessType,
-			IPAnchored:           zpaclient.BoolValue(cr.Spec.ForProvider.IPAnchored),
-			IsCnameEnabled:       zpaclient.BoolValue(cr.Spec.ForProvider.IsCnameEnabled),
-			Name:                 cr.Spec.ForProvider.Name,
-			PassiveHealthEnabled: zpaclient.BoolValue(cr.Spec.ForProvider.PassiveHealthEnabled),
-			SegmentGroupID:       zpaclient.StringValue(cr.Spec.ForProvider.SegmentGroupID),
-			TCPPortRanges:        cr.Spec.ForProvider.TCPPortRanges,
-			UDPPortRanges:        cr.Spec.ForProvider.UDPPortRanges,
-			ServerGroups:         make([]*models.AppServerGroup, 0),
-		},
+	if cr.Spec.ForProvider.PassiveHealthEnabled == nil {
+		cr.Spec.ForProvider.PassiveHealthEnabled = zpaclient.Bool(obj.PassiveHealthEnabled)
 	}
 
-	for i := range cr.Spec.ForProvider.ServerGroups {
-		servergroupreq := &server_group_controller.GetServerGroupUsingGET1Params{
-			Context:    ctx,
-			CustomerID: customerID,
-			GroupID:    cr.Spec.ForProvider.ServerGroups[i],
-		}
-		servergroupresp, err := e.client.ServerGroupController.GetServerGroupUsingGET1(servergroupreq)
-		if err != nil {
-			return managed.ExternalCreation{}, errors.Wrap(err, errServerGroupNotFound)
-		}
-
-		req.Application.ServerGroups = append(
-			req.Application.ServerGroups,
-			&models.AppServerGroup{
-				ID:   cr.Spec.ForProvider.ServerGroups[i],
-				Name: zpaclient.String(servergroupresp.Payload.Name),
-			})
+	if cr.Spec.ForProvider.DoubleEncrypt == nil {
+		cr.Spec.ForProvider.DoubleEncrypt = zpaclient.Bool(obj.DoubleEncrypt)
 	}
 
-	resp, err := e.client.ApplicationController.AddApplicationUsingPOST1(req)
-	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+	if cr.Spec.ForProvider.ConfigSpace == "" {
+		cr.Spec.ForProvider.ConfigSpace = obj.ConfigSpace
 	}
 
-	meta.SetExternalName(cr, resp.Payload.ID)
-	return managed.ExternalCreation{
-		ExternalNameAssigned: true,
-	}, nil
-
-}
-
-func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*v1alpha1.ApplicationSegment)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotApplicationSegment)
+	if cr.Spec.ForProvider.BypassType == "" {
+		cr.Spec.ForProvider.BypassType = obj.BypassType
 	}
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
-	req := &application_controller.UpdateApplicationV2UsingPUT1Params{
-		Context:       ctx,
-		CustomerID:    customerID,
-		ApplicationID: meta.GetExternalName(cr),
-		Application: &models.ApplicationResource{
-			BypassType:           cr.Spec.ForProvider.BypassType,
-			ConfigSpace:          cr.Spec.ForProvider.ConfigSpace,
-			DefaultIdleTimeout:   cr.Spec.ForProvider.DefaultIdleTimeout,
-			DefaultMaxAge:        cr.Spec.ForProvider.DefaultMaxAge,
-			Description:          cr.Spec.ForProvider.Description,
-			DomainNames:          cr.Spec.ForProvider.DomainNames,
-			DoubleEncrypt:        zpaclient.BoolValue(cr.Spec.ForProvider.DoubleEncrypt),
-			Enabled:              zpaclient.BoolValue(cr.Spec.ForProvider.Enabled),
-			HealthCheckType:      cr.Spec.ForProvider.HealthCheckType,
-			HealthReporting:      cr.Spec.ForProvider.HealthReporting,
-			IcmpAccessType:       cr.Spec.ForProvider.IcmpAccessType,
-			IPAnchored:           zpaclient.BoolValue(cr.Spec.ForProvider.IPAnchored),
-			IsCnameEnabled:       zpaclient.BoolValue(cr.Spec.ForProvider.IsCnameEnabled),
-			Name:                 cr.Spec.ForProvider.Name,
-			PassiveHealthEnabled: zpaclient.BoolValue(cr.Spec.ForProvider.PassiveHealthEnabled),
-			SegmentGroupID:       zpaclient.StringValue(cr.Spec.ForProvider.SegmentGroupID),
-			TCPPortRanges:        cr.Spec.ForProvider.TCPPortRanges,
-			UDPPortRanges:        cr.Spec.ForProvider.UDPPortRanges,
-			ServerGroups:         make([]*models.AppServerGroup, 0),
-		},
+	if cr.Spec.ForProvider.HealthCheckType == "" {
+		cr.Spec.ForProvider.HealthCheckType = obj.HealthCheckType
 	}
 
-	for i := range cr.Spec.ForProvider.ServerGroups {
-		servergroupreq := &server_group_controller.GetServerGroupUsingGET1Params{
-			Context:    ctx,
-			CustomerID: customerID,
-			GroupID:    cr.Spec.ForProvider.ServerGroups[i],
-		}
-		servergroupresp, err := e.client.ServerGroupController.GetServerGroupUsingGET1(servergroupreq)
-		if err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, errServerGroupNotFound)
-		}
+	if cr.Spec.ForProvider.IcmpAccessType == "" {
+		cr.Spec.ForProvider.IcmpAccessType = obj.IcmpAccessType
+	}
 
-		req.Application.ServerGroups = append(
-			req.Application.ServerGroups,
-			&models.AppServerGroup{
-				ID:   cr.Spec.ForProvider.ServerGroups[i],
-				Name: zpaclient.String(servergroupresp.Payload.Name),
-			})
+	if cr.Spec.ForProvider.IsCnameEnabled == nil {
+		cr.Spec.ForProvider.IsCnameEnabled = zpaclient.Bool(obj.IsCnameEnabled)
 	}
 
-	if _, _, err := e.client.ApplicationController.UpdateApplicationV2UsingPUT1(req); err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+	if cr.Spec.ForProvider.IPAnchored == nil {
+		cr.Spec.ForProvider.IPAnchored = zpaclient.Bool(obj.IPAnchored)
 	}
 
-	return managed.ExternalUpdate{}, nil
+	if cr.Spec.ForProvider.HealthReporting == "" {
+		cr.Spec.ForProvider.HealthReporting = obj.HealthReporting
+	}
 }
 
-func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.ApplicationSegment)
-	if !ok {
-		return errors.New(errNotApplicationSegment)
+func (kind) Compare(cr *v1alpha1.ApplicationSegment, obj *models.ApplicationResource) adapter.Comparison {
+	// Invalid deprecated port ranges are reported by ToAPI when updating.
+	p, _ := ConvertDeprecatedPortRanges(cr.Spec.ForProvider)
+	return adapter.Comparison{
+		Drifted:      driftedFields(&p, obj),
+		Desired:      &p,
+		Observed:     &cr.Status.AtProvider.ApplicationSegment,
+		Options:      []cmp.Option{normalizePortRanges},
+		ModifiedBy:   obj.ModifiedBy,
+		ModifiedTime: obj.ModifiedTime,
 	}
+}
 
-	id := meta.GetExternalName(cr)
-	if id == "" {
-		return errors.New(errNotApplicationSegment)
+// Observe reports the health of the server groups of the supplied
+// ApplicationSegment if it asks for it, and removes the reported health
+// otherwise.
+func (kind) Observe(ctx context.Context, c *adapter.Client, cr *v1alpha1.ApplicationSegment, obj *models.ApplicationResource) {
+	if !zpaclient.BoolValue(cr.Spec.ForProvider.ReportHealth) {
+		clearHealth(cr)
+		return
 	}
+	observeHealth(ctx, c, cr, obj.ServerGroups)
+}
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
-	req := &application_controller.DeleteApplicationUsingDELETE1Params{
+func (kind) LastDrift(cr *v1alpha1.ApplicationSegment) *zpav1alpha1.Drift {
+	return cr.Status.AtProvider.LastDrift
+}
+
+func (kind) SetLastDrift(cr *v1alpha1.ApplicationSegment, d *zpav1alpha1.Drift) {
+	cr.Status.AtProvider.LastDrift = d
+}
+
+func (kind) Get(ctx context.Context, c *adapter.Client, id string) (*models.ApplicationResource, error) {
+	req := &application_controller.GetApplicationUsingGET1Params{
 		Context:       ctx,
 		ApplicationID: id,
-		CustomerID:    customerID,
-		ForceDelete:   zpaclient.Bool(true),
+		CustomerID:    c.CustomerID,
 	}
-
-	_, err := e.client.ApplicationController.DeleteApplicationUsingDELETE1(req)
+	resp, err := c.ApplicationController.GetApplicationUsingGET1(req)
 	if err != nil {
-		return errors.Wrap(err, errDeleteFailed)
+		return nil, err
 	}
-
-	return nil
+	return resp.Payload, nil
 }
 
-func (e *external) LateInitialize(cr *v1alpha1.ApplicationSegment, obj *application_controller.GetApplicationUsingGET1OK) { // nolint:gocyclo
+func (kind) List(ctx context.Context, c *adapter.Client, name string) ([]zpaclient.NamedObject, error) {
+	objs := make([]zpaclient.NamedObject, 0)
+	for page := int32(1); ; page++ {
+		req := &application_controller.GetAllApplicationsUsingGET3Params{
+			Context:    ctx,
+			CustomerID: c.CustomerID,
+			Page:       page,
+			Pagesize:   zpaclient.ListPageSize,
+			Search:     name,
+		}
+		resp, err := c.ApplicationController.GetAllApplicationsUsingGET3(req)
+		if err != nil {
+			return nil, err
+		}
 
-	if cr.Spec.ForProvider.Enabled == nil {
-		cr.Spec.ForProvider.Enabled = zpaclient.Bool(obj.Payload.Enabled)
-	}
+		for _, obj := range resp.Payload.List {
+			objs = append(objs, zpaclient.NamedObject{ID: obj.ID, Name: obj.Name})
+		}
 
-	if cr.Spec.ForProvider.PassiveHealthEnabled == nil {
-		cr.Spec.ForProvider.PassiveHealthEnabled = zpaclient.Bool(obj.Payload.PassiveHealthEnabled)
+		if page >= resp.Payload.TotalPages {
+			break
+		}
 	}
 
-	if cr.Spec.ForProvider.DoubleEncrypt == nil {
-		cr.Spec.ForProvider.DoubleEncrypt = zpaclient.Bool(obj.Payload.DoubleEncrypt)
-	}
+	return objs, nil
+}
 
-	if cr.Spec.ForProvider.ConfigSpace == "" {
-		cr.Spec.ForProvider.ConfigSpace = obj.Payload.ConfigSpace
+func (kind) Create(ctx context.Context, c *adapter.Client, obj *models.ApplicationResource) (string, error) {
+	req := &application_controller.AddApplicationUsingPOST1Params{
+		Context:     ctx,
+		CustomerID:  c.CustomerID,
+		Application: obj,
 	}
-
-	if cr.Spec.ForProvider.BypassType == "" {
-		cr.Spec.ForProvider.BypassType = obj.Payload.BypassType
+	resp, err := c.ApplicationController.AddApplicationUsingPOST1(req)
+	if err != nil {
+		return "", err
 	}
+	return resp.Payload.ID, nil
+}
 
-	if cr.Spec.ForProvider.HealthCheckType == "" {
-		cr.Spec.ForProvider.HealthCheckType = obj.Payload.HealthCheckType
+func (kind) Update(ctx context.Context, c *adapter.Client, id string, obj *models.ApplicationResource) error {
+	req := &application_controller.UpdateApplicationV2UsingPUT1Params{
+		Context:       ctx,
+		CustomerID:    c.CustomerID,
+		ApplicationID: id,
+		Application:   obj,
 	}
+	_, _, err := c.ApplicationController.UpdateApplicationV2UsingPUT1(req)
+	return err
+}
 
-	if cr.Spec.ForProvider.IcmpAccessType == "" {
-		cr.Spec.ForProvider.IcmpAccessType = obj.Payload.IcmpAccessType
+// Delete deletes the supplied ApplicationSegment. Unless its deletion is
+// forced, ZPA refuses to delete it while policy rules reference it.
+func (kind) Delete(ctx context.Context, c *adapter.Client, cr *v1alpha1.ApplicationSegment, id string) error {
+	force := false
+	if o := cr.Spec.ForProvider.DeletionOptions; o != nil {
+		force = zpaclient.BoolValue(o.Force)
 	}
 
-	if cr.Spec.ForProvider.IsCnameEnabled == nil {
-		cr.Spec.ForProvider.IsCnameEnabled = zpaclient.Bool(obj.Payload.IsCnameEnabled)
+	req := &application_controller.DeleteApplicationUsingDELETE1Params{
+		Context:       ctx,
+		ApplicationID: id,
+		CustomerID:    c.CustomerID,
+		ForceDelete:   zpaclient.Bool(force),
 	}
-
-	if cr.Spec.ForProvider.IPAnchored == nil {
-		cr.Spec.ForProvider.IPAnchored = zpaclient.Bool(obj.Payload.IPAnchored)
+	_, err := c.ApplicationController.DeleteApplicationUsingDELETE1(req)
+	if err != nil && !zpaclient.IsNotFound(err) && !force {
+		return deleteFailed(ctx, c, cr, id, err)
 	}
+	zpaclient.UnblockDeletion(cr)
+	return err
+}
 
-	if cr.Spec.ForProvider.HealthReporting == "" {
-		cr.Spec.ForProvider.HealthReporting = obj.Payload.HealthReporting
+func (kind) Import(obj *models.ApplicationResource) *v1alpha1.ApplicationSegment {
+	cr := &v1alpha1.ApplicationSegment{Spec: v1alpha1.ApplicationSegmentSpec{ForProvider: generateObservation(obj).ApplicationSegment}}
+	meta.SetExternalName(cr, obj.ID)
+	return cr
+}
+
+// deleteFailed reports the policy rules that still reference the supplied
+// ApplicationSegment if they are the reason its deletion failed, or that no
+// rules block its deletion anymore.
+func deleteFailed(ctx context.Context, c *adapter.Client, cr *v1alpha1.ApplicationSegment, id string, deleteErr error) error {
+	rules, err := zpaclient.ReferencingPolicyRules(ctx, c.ZscalerPrivateAccessAPIPortal, c.CustomerID, zpaclient.ObjectTypeApplication, id)
+	if err != nil {
+		return deleteErr
+	}
+	if len(rules) == 0 {
+		zpaclient.UnblockDeletion(cr)
+		return deleteErr
 	}
 
+	cond := zpaclient.ReferencedByPolicyRules(rules)
+	cr.SetConditions(cond)
+	return errors.New(cond.Message)
 }
 
-// generateObservation generates observation for the input object application_controller.GetApplicationUsingGET1OK
-func generateObservation(in *application_controller.GetApplicationUsingGET1OK) v1alpha1.Observation {
+// generateObservation generates observation for the input object models.ApplicationResource
+func generateObservation(obj *models.ApplicationResource) v1alpha1.Observation {
 	cr := v1alpha1.Observation{}
-	obj := in.Payload
 
 	cr.ID = obj.ID
 	cr.CreationTime = obj.CreationTime
@@ -354,84 +362,157 @@ func generateObservation(in *application_controller.GetApplicationUsingGET1OK) v
 	cr.ApplicationSegment.HealthCheckType = obj.HealthCheckType
 	cr.ApplicationSegment.HealthReporting = obj.HealthReporting
 	cr.ApplicationSegment.IPAnchored = zpaclient.Bool(obj.IPAnchored)
+	cr.ApplicationSegment.Name = obj.Name
+	cr.ApplicationSegment.Description = obj.Description
+	cr.ApplicationSegment.IcmpAccessType = obj.IcmpAccessType
+	cr.ApplicationSegment.IsCnameEnabled = zpaclient.Bool(obj.IsCnameEnabled)
+	cr.ApplicationSegment.PassiveHealthEnabled = zpaclient.Bool(obj.PassiveHealthEnabled)
+	cr.ApplicationSegment.SegmentGroupID = zpaclient.StringToPtr(obj.SegmentGroupID)
+	// Port ranges ZPA cannot have stored are not worth failing the observation.
+	cr.ApplicationSegment.TCPPortRange, _ = PortRangesFromAPI(obj.TCPPortRanges)
+	cr.ApplicationSegment.UDPPortRange, _ = PortRangesFromAPI(obj.UDPPortRanges)
+	cr.SegmentGroupName = obj.SegmentGroupName
 	for i := range obj.ServerGroups {
+		if obj.ServerGroups[i] == nil {
+			continue
+		}
 		cr.ServerGroup = append(cr.ServerGroup, v1alpha1.AppServerGroup(*obj.ServerGroups[i]))
+		cr.ApplicationSegment.ServerGroups = append(cr.ApplicationSegment.ServerGroups, obj.ServerGroups[i].ID)
+	}
+	for _, app := range obj.ClientlessApps {
+		if app == nil {
+			continue
+		}
+		cr.ClientlessApps = append(cr.ClientlessApps, v1alpha1.AppObservation{
+			ID:                  app.ID,
+			AppID:               app.AppID,
+			Name:                app.Name,
+			Domain:              app.Domain,
+			ApplicationPort:     app.ApplicationPort,
+			ApplicationProtocol: app.ApplicationProtocol,
+			Enabled:             app.Enabled,
+		})
+	}
+	for _, app := range obj.InspectionApps {
+		if app == nil {
+			continue
+		}
+		cr.InspectionApps = append(cr.InspectionApps, v1alpha1.AppObservation{
+			ID:                  app.ID,
+			AppID:               app.AppID,
+			Name:                app.Name,
+			Domain:              app.Domain,
+			ApplicationPort:     strconv.Itoa(int(app.ApplicationPort)),
+			ApplicationProtocol: app.ApplicationProtocol,
+			Enabled:             app.Enabled,
+		})
 	}
 
 	return cr
 }
 
-// isUpToDate checks whether there is a change in any of the modifiable fields.
-func isUpToDate(cr *v1alpha1.ApplicationSegmentParameters, gobj *application_controller.GetApplicationUsingGET1OK) bool { // nolint:gocyclo
-	obj := gobj.Payload
+// driftedFields returns the modifiable fields whose desired value differs from
+// the value in ZPA.
+func driftedFields(cr *v1alpha1.ApplicationSegmentParameters, obj *models.ApplicationResource) []string { // nolint:gocyclo
+	fields := make([]string, 0)
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.BypassType), zpaclient.StringToPtr(obj.BypassType)) {
-		return false
+		fields = append(fields, "bypassType")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.ConfigSpace), zpaclient.StringToPtr(obj.ConfigSpace)) {
-		return false
+		fields = append(fields, "configSpace")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.DefaultIdleTimeout), zpaclient.StringToPtr(obj.DefaultIdleTimeout)) {
-		return false
+		fields = append(fields, "defaultIdleTimeout")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.DefaultMaxAge), zpaclient.StringToPtr(obj.DefaultMaxAge)) {
-		return false
+		fields = append(fields, "defaultMaxAge")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.Description), zpaclient.StringToPtr(obj.Description)) {
-		return false
+		fields = append(fields, "description")
 	}
 
 	if !zpaclient.IsEqualBool(cr.DoubleEncrypt, zpaclient.Bool(obj.DoubleEncrypt)) {
-		return false
+		fields = append(fields, "doubleEncrypt")
 	}
 
 	if !zpaclient.IsEqualBool(cr.Enabled, zpaclient.Bool(obj.Enabled)) {
-		return false
+		fields = append(fields, "enabled")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.HealthCheckType), zpaclient.StringToPtr(obj.HealthCheckType)) {
-		return false
+		fields = append(fields, "healthCheckType")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.HealthReporting), zpaclient.StringToPtr(obj.HealthReporting)) {
-		return false
+		fields = append(fields, "healthReporting")
 	}
 
 	if !zpaclient.IsEqualBool(cr.IPAnchored, zpaclient.Bool(obj.IPAnchored)) {
-		return false
+		fields = append(fields, "ipAnchored")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.IcmpAccessType), zpaclient.StringToPtr(obj.IcmpAccessType)) {
-		return false
+		fields = append(fields, "icmpAccessType")
 	}
 
 	if !zpaclient.IsEqualBool(cr.IsCnameEnabled, zpaclient.Bool(obj.IsCnameEnabled)) {
-		return false
+		fields = append(fields, "isCnameEnabled")
 	}
 
 	if !zpaclient.IsEqualBool(cr.PassiveHealthEnabled, zpaclient.Bool(obj.PassiveHealthEnabled)) {
-		return false
+		fields = append(fields, "passiveHealthEnabled")
 	}
 
 	if !zpaclient.IsEqualStringArrayContent(cr.DomainNames, obj.DomainNames) {
-		return false
+		fields = append(fields, "domainNames")
 	}
 
-	if !zpaclient.IsEqualStringArrayContent(cr.TCPPortRanges, obj.TCPPortRanges) {
-		return false
+	if !isEqualPortRanges(cr.TCPPortRange, obj.TCPPortRanges) {
+		fields = append(fields, "tcpPortRange")
 	}
 
-	if !zpaclient.IsEqualStringArrayContent(cr.UDPPortRanges, obj.UDPPortRanges) {
-		return false
+	if !isEqualPortRanges(cr.UDPPortRange, obj.UDPPortRanges) {
+		fields = append(fields, "udpPortRange")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.Name), zpaclient.StringToPtr(obj.Name)) {
-		return false
+		fields = append(fields, "name")
+	}
+
+	if !zpaclient.IsEqualString(cr.SegmentGroupID, zpaclient.StringToPtr(obj.SegmentGroupID)) {
+		fields = append(fields, "segmentGroupID")
+	}
+
+	serverGroups := make([]string, 0, len(obj.ServerGroups))
+	for _, g := range obj.ServerGroups {
+		if g != nil {
+			serverGroups = append(serverGroups, g.ID)
+		}
+	}
+	if !zpaclient.IsEqualStringArrayContent(cr.ServerGroups, serverGroups) {
+		fields = append(fields, "serverGroups")
 	}
 
-	return true
+	return fields
+}
+
+// normalizePortRanges makes zpaclient.Diff compare port ranges the same way as
+// isEqualPortRanges.
+var normalizePortRanges = cmp.FilterValues(func(x, y []v1alpha1.PortRange) bool {
+	return len(x) > 0 || len(y) > 0
+}, cmp.Transformer("NormalizePortRanges", NormalizePortRanges))
+
+// isEqualPortRanges checks whether the desired port ranges cover the same ports
+// as the ranges in ZPA wire format.
+func isEqualPortRanges(desired []v1alpha1.PortRange, observed []string) bool {
+	obs, err := PortRangesFromAPI(observed)
+	if err != nil {
+		return false
+	}
+	return cmp.Equal(NormalizePortRanges(desired), obs, cmpopts.EquateEmpty())
 }
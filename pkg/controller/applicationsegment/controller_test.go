@@ -0,0 +1,916 @@
+/*
+Copyright 2022 The Crossplane Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	zpa "github.com/haarchri/zpa-go-client/pkg/client"
+	"github.com/haarchri/zpa-go-client/pkg/client/application_controller"
+	"github.com/haarchri/zpa-go-client/pkg/client/connector_group_controller"
+	"github.com/haarchri/zpa-go-client/pkg/client/policy_set_controller"
+	"github.com/haarchri/zpa-go-client/pkg/client/server_group_controller"
+	"github.com/haarchri/zpa-go-client/pkg/models"
+
+	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	zpav1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	mockapp "github.com/crossplane-contrib/provider-zpa/pkg/client/mock/application_controller"
+	mockcg "github.com/crossplane-contrib/provider-zpa/pkg/client/mock/connector_group_controller"
+	mockpolicy "github.com/crossplane-contrib/provider-zpa/pkg/client/mock/policy_set_controller"
+	mocksg "github.com/crossplane-contrib/provider-zpa/pkg/client/mock/server_group_controller"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+)
+
+const (
+	customerID     = "216196257331281920"
+	id             = "72058000000000001"
+	segmentGroup   = "72058000000000020"
+	serverGroup    = "72058000000000030"
+	connectorGroup = "72058000000000040"
+
+	errCreateFailed   = "cannot create ApplicationSegment"
+	errUpdateFailed   = "cannot update ApplicationSegment"
+	errDescribeFailed = "cannot describe ApplicationSegment"
+	errDeleteFailed   = "cannot delete ApplicationSegment"
+)
+
+var (
+	errBoom     = errors.New("boom")
+	errNotFound = &zpaclient.APIError{StatusCode: http.StatusNotFound, Class: zpaclient.ErrorClassNotFound}
+	errConflict = &zpaclient.APIError{StatusCode: http.StatusBadRequest, ID: "duplicate.item", Class: zpaclient.ErrorClassConflict}
+	errInUse    = &zpaclient.APIError{StatusCode: http.StatusBadRequest, ID: "resource.in.use", Class: zpaclient.ErrorClassValidation}
+	errInvalid  = &zpaclient.APIError{StatusCode: http.StatusBadRequest, ID: "invalid.port.range", Class: zpaclient.ErrorClassValidation}
+)
+
+type asModifier func(*v1alpha1.ApplicationSegment)
+
+func withExternalName(n string) asModifier {
+	return func(cr *v1alpha1.ApplicationSegment) { meta.SetExternalName(cr, n) }
+}
+
+func withSpec(fn func(*v1alpha1.ApplicationSegmentParameters)) asModifier {
+	return func(cr *v1alpha1.ApplicationSegment) { fn(&cr.Spec.ForProvider) }
+}
+
+func withObservation(o v1alpha1.Observation) asModifier {
+	return func(cr *v1alpha1.ApplicationSegment) { cr.Status.AtProvider = o }
+}
+
+func withConditions(c ...xpv1.Condition) asModifier {
+	return func(cr *v1alpha1.ApplicationSegment) { cr.Status.SetConditions(c...) }
+}
+
+func withName(n string) asModifier {
+	return func(cr *v1alpha1.ApplicationSegment) { cr.SetName(n) }
+}
+
+func withDomains(d ...string) asModifier {
+	return func(cr *v1alpha1.ApplicationSegment) { cr.Spec.ForProvider.DomainNames = d }
+}
+
+func withTCP(r ...v1alpha1.PortRange) asModifier {
+	return func(cr *v1alpha1.ApplicationSegment) { cr.Spec.ForProvider.TCPPortRange = r }
+}
+
+func withUDP(r ...v1alpha1.PortRange) asModifier {
+	return func(cr *v1alpha1.ApplicationSegment) { cr.Spec.ForProvider.UDPPortRange = r }
+}
+
+func withProviderConfig(name string) asModifier {
+	return func(cr *v1alpha1.ApplicationSegment) { cr.SetProviderConfigReference(&xpv1.Reference{Name: name}) }
+}
+
+func parameters() v1alpha1.ApplicationSegmentParameters {
+	return v1alpha1.ApplicationSegmentParameters{
+		Name:                 "example",
+		Description:          "managed by crossplane",
+		BypassType:           "NEVER",
+		ConfigSpace:          "DEFAULT",
+		HealthCheckType:      "DEFAULT",
+		HealthReporting:      "ON_ACCESS",
+		IcmpAccessType:       "NONE",
+		DomainNames:          []string{"app.example.com"},
+		Enabled:              zpaclient.Bool(true),
+		DoubleEncrypt:        zpaclient.Bool(false),
+		IPAnchored:           zpaclient.Bool(false),
+		IsCnameEnabled:       zpaclient.Bool(true),
+		PassiveHealthEnabled: zpaclient.Bool(true),
+		SegmentGroupID:       zpaclient.String(segmentGroup),
+		ServerGroups:         []string{serverGroup},
+		TCPPortRange:         []v1alpha1.PortRange{{From: 443, To: 443}},
+	}
+}
+
+func applicationSegment(m ...asModifier) *v1alpha1.ApplicationSegment {
+	cr := &v1alpha1.ApplicationSegment{
+		Spec: v1alpha1.ApplicationSegmentSpec{ForProvider: parameters()},
+	}
+	meta.SetExternalName(cr, id)
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+func payload(m ...func(*models.ApplicationResource)) *models.ApplicationResource {
+	p := &models.ApplicationResource{
+		ID:                   id,
+		Name:                 "example",
+		Description:          "managed by crossplane",
+		BypassType:           "NEVER",
+		ConfigSpace:          "DEFAULT",
+		HealthCheckType:      "DEFAULT",
+		HealthReporting:      "ON_ACCESS",
+		IcmpAccessType:       "NONE",
+		DomainNames:          []string{"app.example.com"},
+		Enabled:              true,
+		IsCnameEnabled:       true,
+		PassiveHealthEnabled: true,
+		SegmentGroupID:       segmentGroup,
+		SegmentGroupName:     "segments",
+		ServerGroups:         []*models.AppServerGroup{{ID: serverGroup, Name: zpaclient.String("servers")}},
+		TCPPortRanges:        []string{"443", "443"},
+		ModifiedBy:           "72057594037928115",
+		ModifiedTime:         "1650000000",
+	}
+	for _, f := range m {
+		f(p)
+	}
+	return p
+}
+
+func observation(m ...func(*v1alpha1.Observation)) v1alpha1.Observation {
+	o := v1alpha1.Observation{
+		ID:                 id,
+		ModifiedBy:         "72057594037928115",
+		ModifiedTime:       "1650000000",
+		SegmentGroupName:   "segments",
+		ServerGroup:        []v1alpha1.AppServerGroup{{ID: serverGroup, Name: zpaclient.String("servers")}},
+		ApplicationSegment: parameters(),
+	}
+	for _, f := range m {
+		f(&o)
+	}
+	return o
+}
+
+func getServerGroup(m mocks) {
+	m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{
+		Payload: &models.ServerGroupDTO{ID: serverGroup, Name: "servers"},
+	}, nil)
+}
+
+type mocks struct {
+	app    *mockapp.MockClientService
+	sg     *mocksg.MockClientService
+	cg     *mockcg.MockClientService
+	policy *mockpolicy.MockClientService
+}
+
+func newExternal(t *testing.T, mock func(mocks)) *adapter.External[*v1alpha1.ApplicationSegment, *models.ApplicationResource] {
+	ctrl := gomock.NewController(t)
+	m := mocks{
+		app:    mockapp.NewMockClientService(ctrl),
+		sg:     mocksg.NewMockClientService(ctrl),
+		cg:     mockcg.NewMockClientService(ctrl),
+		policy: mockpolicy.NewMockClientService(ctrl),
+	}
+	if mock != nil {
+		mock(m)
+	}
+	c := &adapter.Client{
+		ZscalerPrivateAccessAPIPortal: &zpa.ZscalerPrivateAccessAPIPortal{
+			ApplicationController:    m.app,
+			ServerGroupController:    m.sg,
+			ConnectorGroupController: m.cg,
+			PolicySetController:      m.policy,
+		},
+		CustomerID: customerID,
+	}
+	return adapter.NewExternal[*v1alpha1.ApplicationSegment, *models.ApplicationResource](c, kind{}, event.NewNopRecorder())
+}
+
+var equateStatus = []cmp.Option{
+	test.EquateConditions(),
+	cmpopts.IgnoreFields(zpav1alpha1.Drift{}, "DetectedTime"),
+}
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		mock func(mocks)
+		cr   *v1alpha1.ApplicationSegment
+	}
+	type want struct {
+		cr     *v1alpha1.ApplicationSegment
+		result managed.ExternalObservation
+		err    error
+	}
+
+	reportHealth := withSpec(func(p *v1alpha1.ApplicationSegmentParameters) { p.ReportHealth = zpaclient.Bool(true) })
+	getApplication := func(m mocks) {
+		m.app.EXPECT().GetApplicationUsingGET1(gomock.Any()).Return(&application_controller.GetApplicationUsingGET1OK{Payload: payload()}, nil)
+	}
+	getServerGroupWithServers := func(m mocks) {
+		m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{
+			Payload: &models.ServerGroupDTO{
+				ID:                 serverGroup,
+				Name:               "servers",
+				Enabled:            true,
+				AppConnectorGroups: []*models.AppConnectorGroup{{ID: connectorGroup}},
+				Servers:            []*models.ApplicationServer{{ID: "72058000000000050", Enabled: true}},
+			},
+		}, nil)
+	}
+	getConnectorGroup := func(status string) func(m mocks) {
+		return func(m mocks) {
+			m.cg.EXPECT().GetAppConnectorGroupUsingGET1(gomock.Any()).Return(&connector_group_controller.GetAppConnectorGroupUsingGET1OK{
+				Payload: &models.AppConnectorGroup{ID: connectorGroup, Connectors: []*models.Connector{{Enabled: true, ControlChannelStatus: status}}},
+			}, nil)
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoExternalName": {
+			reason: "An ApplicationSegment without external name does not exist yet.",
+			args: args{
+				cr: applicationSegment(withExternalName("")),
+			},
+			want: want{
+				cr:     applicationSegment(withExternalName("")),
+				result: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotFound": {
+			reason: "An ApplicationSegment that is missing in ZPA does not exist.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().GetApplicationUsingGET1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: applicationSegment(),
+			},
+			want: want{
+				cr:     applicationSegment(),
+				result: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"DescribeFailed": {
+			reason: "Errors reading the ApplicationSegment should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().GetApplicationUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: applicationSegment(),
+			},
+			want: want{
+				cr:  applicationSegment(),
+				err: errors.Wrap(errBoom, errDescribeFailed),
+			},
+		},
+		"UpToDate": {
+			reason: "An ApplicationSegment that matches ZPA is up to date.",
+			args: args{
+				mock: getApplication,
+				cr:   applicationSegment(),
+			},
+			want: want{
+				cr:     applicationSegment(withObservation(observation()), withConditions(xpv1.Available())),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"LateInitialize": {
+			reason: "Parameters that are only set in ZPA should be late initialized.",
+			args: args{
+				mock: getApplication,
+				cr: applicationSegment(withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.BypassType = ""
+					p.HealthReporting = ""
+					p.Enabled = nil
+					p.DoubleEncrypt = nil
+				})),
+			},
+			want: want{
+				cr:     applicationSegment(withObservation(observation()), withConditions(xpv1.Available())),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
+		"EquivalentPortRanges": {
+			reason: "Port ranges that cover the same ports as in ZPA should not make an ApplicationSegment outdated.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().GetApplicationUsingGET1(gomock.Any()).Return(&application_controller.GetApplicationUsingGET1OK{Payload: payload(func(p *models.ApplicationResource) {
+						p.TCPPortRanges = []string{"80", "443"}
+					})}, nil)
+				},
+				cr: applicationSegment(withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.TCPPortRange = []v1alpha1.PortRange{{From: 200, To: 443}, {From: 80, To: 250}}
+				})),
+			},
+			want: want{
+				cr: applicationSegment(
+					withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+						p.TCPPortRange = []v1alpha1.PortRange{{From: 200, To: 443}, {From: 80, To: 250}}
+					}),
+					withObservation(observation(func(o *v1alpha1.Observation) {
+						o.ApplicationSegment.TCPPortRange = []v1alpha1.PortRange{{From: 80, To: 443}}
+					})),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"Drifted": {
+			reason: "An ApplicationSegment whose domain names were changed in ZPA is not up to date.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().GetApplicationUsingGET1(gomock.Any()).Return(&application_controller.GetApplicationUsingGET1OK{Payload: payload(func(p *models.ApplicationResource) {
+						p.DomainNames = []string{"other.example.com"}
+					})}, nil)
+				},
+				cr: applicationSegment(),
+			},
+			want: want{
+				cr: applicationSegment(
+					withObservation(observation(func(o *v1alpha1.Observation) {
+						o.ApplicationSegment.DomainNames = []string{"other.example.com"}
+						o.LastDrift = &zpav1alpha1.Drift{
+							ModifiedBy:   "72057594037928115",
+							ModifiedTime: "1650000000",
+							Fields:       []zpav1alpha1.FieldDrift{{Path: "domainNames[0]", Desired: "app.example.com", Observed: "other.example.com"}},
+						}
+					})),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"IgnoredDrift": {
+			reason: "Drift of parameters listed in ignoreDrift should not make an ApplicationSegment outdated.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().GetApplicationUsingGET1(gomock.Any()).Return(&application_controller.GetApplicationUsingGET1OK{Payload: payload(func(p *models.ApplicationResource) {
+						p.DomainNames = []string{"other.example.com"}
+					})}, nil)
+				},
+				cr: applicationSegment(withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.IgnoreDrift = []string{"domainNames"}
+				})),
+			},
+			want: want{
+				cr: applicationSegment(
+					withSpec(func(p *v1alpha1.ApplicationSegmentParameters) { p.IgnoreDrift = []string{"domainNames"} }),
+					withObservation(observation(func(o *v1alpha1.Observation) {
+						o.ApplicationSegment.DomainNames = []string{"other.example.com"}
+					})),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"Healthy": {
+			reason: "An ApplicationSegment whose server groups have connected connectors and enabled servers is healthy.",
+			args: args{
+				mock: func(m mocks) {
+					getApplication(m)
+					getServerGroupWithServers(m)
+					getConnectorGroup(models.ConnectorControlChannelStatusZPNSTATUSAUTHENTICATED)(m)
+				},
+				cr: applicationSegment(reportHealth),
+			},
+			want: want{
+				cr: applicationSegment(
+					reportHealth,
+					withObservation(observation(func(o *v1alpha1.Observation) {
+						o.Health = &v1alpha1.ApplicationHealth{
+							HealthyServerGroups: 1,
+							ServerGroups: []v1alpha1.ServerGroupHealth{{
+								ID: serverGroup, Name: "servers", Healthy: true,
+								Connectors: 1, ConnectedConnectors: 1, Servers: 1, EnabledServers: 1,
+							}},
+						}
+					})),
+					withConditions(xpv1.Available(), zpaclient.Healthy()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"Unhealthy": {
+			reason: "An ApplicationSegment whose server groups have no connected connectors is unhealthy.",
+			args: args{
+				mock: func(m mocks) {
+					getApplication(m)
+					getServerGroupWithServers(m)
+					getConnectorGroup(models.ConnectorControlChannelStatusZPNSTATUSDISCONNECTED)(m)
+				},
+				cr: applicationSegment(reportHealth),
+			},
+			want: want{
+				cr: applicationSegment(
+					reportHealth,
+					withObservation(observation(func(o *v1alpha1.Observation) {
+						o.Health = &v1alpha1.ApplicationHealth{
+							ServerGroups: []v1alpha1.ServerGroupHealth{{
+								ID: serverGroup, Name: "servers",
+								Connectors: 1, Servers: 1, EnabledServers: 1,
+							}},
+						}
+					})),
+					withConditions(xpv1.Available(), zpaclient.Unhealthy([]string{
+						`server group "servers" (` + serverGroup + `) has 0 of 1 connectors connected and 1 of 1 servers enabled`,
+					})),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"HealthNotReported": {
+			reason: "An ApplicationSegment that no longer asks for its health should not keep the health it reported.",
+			args: args{
+				mock: getApplication,
+				cr: applicationSegment(
+					withObservation(observation(func(o *v1alpha1.Observation) {
+						o.Health = &v1alpha1.ApplicationHealth{HealthyServerGroups: 1}
+					})),
+					withConditions(zpaclient.Healthy()),
+				),
+			},
+			want: want{
+				cr: applicationSegment(
+					withObservation(observation()),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"HealthUnknown": {
+			reason: "Errors reading the server groups of an ApplicationSegment should make its health unknown.",
+			args: args{
+				mock: func(m mocks) {
+					getApplication(m)
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: applicationSegment(reportHealth),
+			},
+			want: want{
+				cr: applicationSegment(
+					reportHealth,
+					withObservation(observation()),
+					withConditions(xpv1.Available(), zpaclient.HealthUnknown(errors.Wrapf(errBoom, errGetServerGroupHealth, serverGroup))),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, tc.args.mock)
+			got, err := e.Observe(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		mock func(mocks)
+		cr   *v1alpha1.ApplicationSegment
+	}
+	type want struct {
+		cr     *v1alpha1.ApplicationSegment
+		result managed.ExternalCreation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "The ID of the created ApplicationSegment should become its external name.",
+			args: args{
+				mock: func(m mocks) {
+					getServerGroup(m)
+					m.app.EXPECT().AddApplicationUsingPOST1(gomock.Any()).DoAndReturn(
+						func(params *application_controller.AddApplicationUsingPOST1Params, _ ...application_controller.ClientOption) (*application_controller.AddApplicationUsingPOST1Created, error) {
+							want := payload(func(p *models.ApplicationResource) {
+								p.ID, p.ModifiedBy, p.ModifiedTime, p.SegmentGroupName = "", "", "", ""
+							})
+							if diff := cmp.Diff(want, params.Application); diff != "" {
+								t.Errorf("AddApplicationUsingPOST1(...): -want payload, +got:\n%s", diff)
+							}
+							return &application_controller.AddApplicationUsingPOST1Created{Payload: payload()}, nil
+						})
+				},
+				cr: applicationSegment(withExternalName("")),
+			},
+			want: want{
+				cr:     applicationSegment(),
+				result: managed.ExternalCreation{ExternalNameAssigned: true},
+			},
+		},
+		"InvalidPortRange": {
+			reason: "An ApplicationSegment with an invalid port range should not be created.",
+			args: args{
+				cr: applicationSegment(withExternalName(""), withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.TCPPortRange = []v1alpha1.PortRange{{From: 443, To: 80}}
+				})),
+			},
+			want: want{
+				cr: applicationSegment(withExternalName(""), withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.TCPPortRange = []v1alpha1.PortRange{{From: 443, To: 80}}
+				})),
+				err: errors.Wrap(errors.Wrap(ValidatePortRanges([]v1alpha1.PortRange{{From: 443, To: 80}}), errInvalidTCPPortRange), errCreateFailed),
+			},
+		},
+		"ServerGroupNotFound": {
+			reason: "Errors reading a referenced ServerGroup should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: applicationSegment(withExternalName("")),
+			},
+			want: want{
+				cr:  applicationSegment(withExternalName("")),
+				err: errors.Wrap(errors.Wrap(errNotFound, errServerGroupNotFound), errCreateFailed),
+			},
+		},
+		"CreateFailed": {
+			reason: "Errors creating the ApplicationSegment should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					getServerGroup(m)
+					m.app.EXPECT().AddApplicationUsingPOST1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: applicationSegment(withExternalName("")),
+			},
+			want: want{
+				cr:  applicationSegment(withExternalName("")),
+				err: errors.Wrap(errBoom, errCreateFailed),
+			},
+		},
+		"InvalidNotAdopted": {
+			reason: "An ApplicationSegment that is invalid should not adopt an object with the same name, even if its policy says so.",
+			args: args{
+				mock: func(m mocks) {
+					getServerGroup(m)
+					m.app.EXPECT().AddApplicationUsingPOST1(gomock.Any()).Return(nil, errInvalid)
+				},
+				cr: applicationSegment(withExternalName(""), withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.NameConflictPolicy = zpaclient.NameConflictPolicyAdopt
+				})),
+			},
+			want: want{
+				cr: applicationSegment(withExternalName(""), withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.NameConflictPolicy = zpaclient.NameConflictPolicyAdopt
+				})),
+				err: errors.Wrap(errInvalid, errCreateFailed),
+			},
+		},
+		"NameConflictAdopt": {
+			reason: "An ApplicationSegment whose name is taken should adopt the existing object if its policy says so.",
+			args: args{
+				mock: func(m mocks) {
+					getServerGroup(m)
+					m.app.EXPECT().AddApplicationUsingPOST1(gomock.Any()).Return(nil, errConflict)
+					m.app.EXPECT().GetAllApplicationsUsingGET3(gomock.Any()).Return(&application_controller.GetAllApplicationsUsingGET3OK{
+						Payload: &models.PageListOfApplicationResource{List: []*models.ApplicationResource{payload()}, TotalPages: 1},
+					}, nil)
+				},
+				cr: applicationSegment(withExternalName(""), withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.NameConflictPolicy = zpaclient.NameConflictPolicyAdopt
+				})),
+			},
+			want: want{
+				cr: applicationSegment(withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.NameConflictPolicy = zpaclient.NameConflictPolicyAdopt
+				})),
+				result: managed.ExternalCreation{ExternalNameAssigned: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, tc.args.mock)
+			got, err := e.Create(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		mock func(mocks)
+		cr   *v1alpha1.ApplicationSegment
+	}
+	type want struct {
+		err error
+	}
+
+	clientlessApps := []*models.BAAppDto{{ID: "72058000000000060", Name: "portal", Domain: "app.example.com"}}
+	live := payload(func(p *models.ApplicationResource) {
+		p.DomainNames = []string{"other.example.com"}
+		p.ClientlessApps = clientlessApps
+	})
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "Update should send the desired parameters merged with the fields it does not model.",
+			args: args{
+				mock: func(m mocks) {
+					getServerGroup(m)
+					m.app.EXPECT().GetApplicationUsingGET1(gomock.Any()).Return(&application_controller.GetApplicationUsingGET1OK{Payload: live}, nil)
+					m.app.EXPECT().UpdateApplicationV2UsingPUT1(gomock.Any()).DoAndReturn(
+						func(params *application_controller.UpdateApplicationV2UsingPUT1Params, _ ...application_controller.ClientOption) (*application_controller.UpdateApplicationV2UsingPUT1Created, *application_controller.UpdateApplicationV2UsingPUT1NoContent, error) {
+							want := payload(func(p *models.ApplicationResource) {
+								p.ClientlessApps = clientlessApps
+							})
+							if diff := cmp.Diff(want, params.Application); diff != "" {
+								t.Errorf("UpdateApplicationV2UsingPUT1(...): -want payload, +got:\n%s", diff)
+							}
+							return nil, &application_controller.UpdateApplicationV2UsingPUT1NoContent{}, nil
+						})
+				},
+				cr: applicationSegment(),
+			},
+		},
+		"IgnoredDrift": {
+			reason: "Update should keep the value in ZPA of parameters listed in ignoreDrift.",
+			args: args{
+				mock: func(m mocks) {
+					getServerGroup(m)
+					m.app.EXPECT().GetApplicationUsingGET1(gomock.Any()).Return(&application_controller.GetApplicationUsingGET1OK{Payload: live}, nil)
+					m.app.EXPECT().UpdateApplicationV2UsingPUT1(gomock.Any()).DoAndReturn(
+						func(params *application_controller.UpdateApplicationV2UsingPUT1Params, _ ...application_controller.ClientOption) (*application_controller.UpdateApplicationV2UsingPUT1Created, *application_controller.UpdateApplicationV2UsingPUT1NoContent, error) {
+							if diff := cmp.Diff(live.DomainNames, params.Application.DomainNames); diff != "" {
+								t.Errorf("UpdateApplicationV2UsingPUT1(...): -want domainNames, +got:\n%s", diff)
+							}
+							return nil, &application_controller.UpdateApplicationV2UsingPUT1NoContent{}, nil
+						})
+				},
+				cr: applicationSegment(withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.IgnoreDrift = []string{"domainNames"}
+				})),
+			},
+		},
+		"InvalidPortRange": {
+			reason: "An ApplicationSegment with an invalid port range should not be updated.",
+			args: args{
+				cr: applicationSegment(withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.UDPPortRange = []v1alpha1.PortRange{{From: 443, To: 80}}
+				})),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(ValidatePortRanges([]v1alpha1.PortRange{{From: 443, To: 80}}), errInvalidUDPPortRange), errUpdateFailed),
+			},
+		},
+		"DescribeFailed": {
+			reason: "Errors reading the ApplicationSegment before updating it should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					getServerGroup(m)
+					m.app.EXPECT().GetApplicationUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: applicationSegment(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errDescribeFailed), errUpdateFailed),
+			},
+		},
+		"UpdateFailed": {
+			reason: "Errors updating the ApplicationSegment should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					getServerGroup(m)
+					m.app.EXPECT().GetApplicationUsingGET1(gomock.Any()).Return(&application_controller.GetApplicationUsingGET1OK{Payload: live}, nil)
+					m.app.EXPECT().UpdateApplicationV2UsingPUT1(gomock.Any()).Return(nil, nil, errBoom)
+				},
+				cr: applicationSegment(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, tc.args.mock)
+			_, err := e.Update(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		mock func(mocks)
+		cr   *v1alpha1.ApplicationSegment
+	}
+	type want struct {
+		cr  *v1alpha1.ApplicationSegment
+		err error
+	}
+
+	force := withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+		p.DeletionOptions = &v1alpha1.DeletionOptions{Force: zpaclient.Bool(true)}
+	})
+	rule := &models.PolicyRule{
+		ID:   "72058000000000070",
+		Name: zpaclient.String("allow"),
+		Conditions: []*models.ConditionSet{{Operands: []*models.Operand{
+			{ObjectType: zpaclient.ObjectTypeApplication, RHS: id},
+		}}},
+	}
+	listRules := func(rules ...*models.PolicyRule) func(m mocks) {
+		return func(m mocks) {
+			m.policy.EXPECT().GetPolicyRulesByPageUsingGET1(gomock.Any()).DoAndReturn(
+				func(params *policy_set_controller.GetPolicyRulesByPageUsingGET1Params, _ ...policy_set_controller.ClientOption) (*policy_set_controller.GetPolicyRulesByPageUsingGET1OK, error) {
+					list := []*models.PolicyRule{}
+					if params.PolicyType == "ACCESS_POLICY" {
+						list = rules
+					}
+					return &policy_set_controller.GetPolicyRulesByPageUsingGET1OK{
+						Payload: &models.PageListOfPolicyRule{List: list, TotalPages: 1},
+					}, nil
+				}).AnyTimes()
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "An ApplicationSegment should be deleted without force by default.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().DeleteApplicationUsingDELETE1(gomock.Any()).DoAndReturn(
+						func(params *application_controller.DeleteApplicationUsingDELETE1Params, _ ...application_controller.ClientOption) (*application_controller.DeleteApplicationUsingDELETE1NoContent, error) {
+							if diff := cmp.Diff(zpaclient.Bool(false), params.ForceDelete); diff != "" {
+								t.Errorf("DeleteApplicationUsingDELETE1(...): -want forceDelete, +got:\n%s", diff)
+							}
+							return &application_controller.DeleteApplicationUsingDELETE1NoContent{}, nil
+						})
+				},
+				cr: applicationSegment(),
+			},
+			want: want{
+				cr: applicationSegment(),
+			},
+		},
+		"NotFound": {
+			reason: "An ApplicationSegment that is already gone should be deleted successfully.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().DeleteApplicationUsingDELETE1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: applicationSegment(),
+			},
+			want: want{
+				cr: applicationSegment(),
+			},
+		},
+		"ReferencedByPolicyRules": {
+			reason: "An ApplicationSegment that policy rules reference should report them.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().DeleteApplicationUsingDELETE1(gomock.Any()).Return(nil, errInUse)
+					listRules(rule)(m)
+				},
+				cr: applicationSegment(),
+			},
+			want: want{
+				cr:  applicationSegment(withConditions(zpaclient.ReferencedByPolicyRules([]zpaclient.NamedObject{{ID: rule.ID, Name: "allow"}}))),
+				err: errors.Wrap(errors.New(`referenced by policy rules "allow" (72058000000000070)`), errDeleteFailed),
+			},
+		},
+		"NotReferenced": {
+			reason: "Errors deleting an ApplicationSegment that no policy rule references should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().DeleteApplicationUsingDELETE1(gomock.Any()).Return(nil, errBoom)
+					listRules()(m)
+				},
+				cr: applicationSegment(),
+			},
+			want: want{
+				cr:  applicationSegment(),
+				err: errors.Wrap(errBoom, errDeleteFailed),
+			},
+		},
+		"NoLongerReferenced": {
+			reason: "An ApplicationSegment whose deletion failed after no policy rule references it anymore should not report the stale rules.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().DeleteApplicationUsingDELETE1(gomock.Any()).Return(nil, errBoom)
+					listRules()(m)
+				},
+				cr: applicationSegment(withConditions(zpaclient.ReferencedByPolicyRules([]zpaclient.NamedObject{{ID: rule.ID, Name: "allow"}}))),
+			},
+			want: want{
+				cr:  applicationSegment(withConditions(zpaclient.DeletionNotBlocked())),
+				err: errors.Wrap(errBoom, errDeleteFailed),
+			},
+		},
+		"ListPolicyRulesFailed": {
+			reason: "Errors listing policy rules should not hide why the deletion failed.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().DeleteApplicationUsingDELETE1(gomock.Any()).Return(nil, errInUse)
+					m.policy.EXPECT().GetPolicyRulesByPageUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: applicationSegment(),
+			},
+			want: want{
+				cr:  applicationSegment(),
+				err: errors.Wrap(errInUse, errDeleteFailed),
+			},
+		},
+		"ForceFailed": {
+			reason: "Errors force deleting an ApplicationSegment should be wrapped without looking up policy rules.",
+			args: args{
+				mock: func(m mocks) {
+					m.app.EXPECT().DeleteApplicationUsingDELETE1(gomock.Any()).DoAndReturn(
+						func(params *application_controller.DeleteApplicationUsingDELETE1Params, _ ...application_controller.ClientOption) (*application_controller.DeleteApplicationUsingDELETE1NoContent, error) {
+							if diff := cmp.Diff(zpaclient.Bool(true), params.ForceDelete); diff != "" {
+								t.Errorf("DeleteApplicationUsingDELETE1(...): -want forceDelete, +got:\n%s", diff)
+							}
+							return nil, errBoom
+						})
+				},
+				cr: applicationSegment(force),
+			},
+			want: want{
+				cr:  applicationSegment(force),
+				err: errors.Wrap(errBoom, errDeleteFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, tc.args.mock)
+			err := e.Delete(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
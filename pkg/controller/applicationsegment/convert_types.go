@@ -1,14 +1,120 @@
 package application
 
 import (
-	"errors"
+	"fmt"
+	"sort"
+	"strconv"
 
-	"github.com/haarchri/zpa-go-client/pkg/client/application_controller"
+	"github.com/pkg/errors"
+
+	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+)
+
+const (
+	minPort = 1
+	maxPort = 65535
+
+	errPortOutOfRange = "port range %d-%d is outside of %d-%d"
+	errPortRangeOrder = "port range %d-%d starts after it ends"
+
+	errInvalidTCPPortRanges = "invalid tcpPortRanges"
+	errInvalidUDPPortRanges = "invalid udpPortRanges"
 )
 
-// IsNotFound returns whether the given error is of type NotFound or not.
-func IsNotFound(err error) bool {
-	// 404 NotFound is in API Response 400 BadRequest ApplicationSegment NotFound
-	var badRequest *application_controller.GetApplicationUsingGET1BadRequest
-	return errors.As(err, &badRequest)
+// ValidatePortRanges returns an error if any of the given port ranges is
+// outside of the valid port numbers or starts after it ends.
+func ValidatePortRanges(in []v1alpha1.PortRange) error {
+	for _, r := range in {
+		if r.From < minPort || r.From > maxPort || r.To < minPort || r.To > maxPort {
+			return fmt.Errorf(errPortOutOfRange, r.From, r.To, minPort, maxPort)
+		}
+		if r.From > r.To {
+			return fmt.Errorf(errPortRangeOrder, r.From, r.To)
+		}
+	}
+	return nil
+}
+
+// NormalizePortRanges returns the given port ranges sorted by their first
+// port with duplicate and overlapping ranges merged into one.
+func NormalizePortRanges(in []v1alpha1.PortRange) []v1alpha1.PortRange {
+	if len(in) == 0 {
+		return nil
+	}
+
+	sorted := make([]v1alpha1.PortRange, len(in))
+	copy(sorted, in)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].From == sorted[j].From {
+			return sorted[i].To < sorted[j].To
+		}
+		return sorted[i].From < sorted[j].From
+	})
+
+	out := []v1alpha1.PortRange{sorted[0]}
+	for _, r := range sorted[1:] {
+		last := &out[len(out)-1]
+		if r.From <= last.To {
+			if r.To > last.To {
+				last.To = r.To
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// PortRangesToAPI converts port ranges into the ZPA wire format, which is a
+// flat list of alternating from and to ports.
+func PortRangesToAPI(in []v1alpha1.PortRange) []string {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]string, 0, 2*len(in))
+	for _, r := range NormalizePortRanges(in) {
+		out = append(out, strconv.Itoa(int(r.From)), strconv.Itoa(int(r.To)))
+	}
+	return out
+}
+
+// PortRangesFromAPI converts the ZPA wire format of alternating from and to
+// ports into normalized port ranges. A trailing port without a matching end
+// is treated as a single port range.
+func PortRangesFromAPI(in []string) ([]v1alpha1.PortRange, error) {
+	out := make([]v1alpha1.PortRange, 0, (len(in)+1)/2)
+	for i := 0; i < len(in); i += 2 {
+		from, err := strconv.ParseInt(in[i], 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if i+1 < len(in) {
+			if to, err = strconv.ParseInt(in[i+1], 10, 32); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, v1alpha1.PortRange{From: int32(from), To: int32(to)})
+	}
+	return NormalizePortRanges(out), nil
+}
+
+// ConvertDeprecatedPortRanges returns the supplied parameters with the
+// deprecated tcpPortRanges and udpPortRanges converted to tcpPortRange and
+// udpPortRange, unless those are set. The deprecated fields are cleared, so
+// that the result can be compared with the parameters observed in ZPA.
+func ConvertDeprecatedPortRanges(p v1alpha1.ApplicationSegmentParameters) (v1alpha1.ApplicationSegmentParameters, error) {
+	var err error
+	if len(p.TCPPortRange) == 0 && len(p.TCPPortRanges) > 0 {
+		if p.TCPPortRange, err = PortRangesFromAPI(p.TCPPortRanges); err != nil {
+			return p, errors.Wrap(err, errInvalidTCPPortRanges)
+		}
+	}
+	if len(p.UDPPortRange) == 0 && len(p.UDPPortRanges) > 0 {
+		if p.UDPPortRange, err = PortRangesFromAPI(p.UDPPortRanges); err != nil {
+			return p, errors.Wrap(err, errInvalidUDPPortRanges)
+		}
+	}
+	p.TCPPortRanges, p.UDPPortRanges = nil, nil
+	return p, nil
 }
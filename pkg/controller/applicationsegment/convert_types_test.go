@@ -0,0 +1,215 @@
+/*
+Copyright 2022 The Crossplane Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+)
+
+func TestValidatePortRanges(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     []v1alpha1.PortRange
+		want   error
+	}{
+		"Empty": {
+			reason: "No port ranges should be valid.",
+		},
+		"Valid": {
+			reason: "Port ranges within 1-65535 that do not start after they end should be valid.",
+			in:     []v1alpha1.PortRange{{From: 1, To: 1}, {From: 80, To: 443}, {From: 65535, To: 65535}},
+		},
+		"Zero": {
+			reason: "Port 0 should be invalid.",
+			in:     []v1alpha1.PortRange{{From: 0, To: 80}},
+			want:   fmt.Errorf(errPortOutOfRange, 0, 80, minPort, maxPort),
+		},
+		"TooHigh": {
+			reason: "Ports above 65535 should be invalid.",
+			in:     []v1alpha1.PortRange{{From: 80, To: 443}, {From: 8080, To: 65536}},
+			want:   fmt.Errorf(errPortOutOfRange, 8080, 65536, minPort, maxPort),
+		},
+		"Reversed": {
+			reason: "A port range that starts after it ends should be invalid.",
+			in:     []v1alpha1.PortRange{{From: 443, To: 80}},
+			want:   fmt.Errorf(errPortRangeOrder, 443, 80),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidatePortRanges(tc.in)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidatePortRanges(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNormalizePortRanges(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     []v1alpha1.PortRange
+		want   []v1alpha1.PortRange
+	}{
+		"Empty": {
+			reason: "No port ranges should be normalized to nil.",
+			in:     []v1alpha1.PortRange{},
+		},
+		"Sorted": {
+			reason: "Port ranges should be sorted by their first port.",
+			in:     []v1alpha1.PortRange{{From: 443, To: 443}, {From: 80, To: 80}},
+			want:   []v1alpha1.PortRange{{From: 80, To: 80}, {From: 443, To: 443}},
+		},
+		"Duplicates": {
+			reason: "Duplicate port ranges should be merged.",
+			in:     []v1alpha1.PortRange{{From: 443, To: 443}, {From: 443, To: 443}},
+			want:   []v1alpha1.PortRange{{From: 443, To: 443}},
+		},
+		"Overlapping": {
+			reason: "Overlapping port ranges should be merged.",
+			in:     []v1alpha1.PortRange{{From: 8000, To: 8100}, {From: 8050, To: 8200}, {From: 8080, To: 8080}},
+			want:   []v1alpha1.PortRange{{From: 8000, To: 8200}},
+		},
+		"Adjacent": {
+			reason: "Adjacent port ranges should be kept apart.",
+			in:     []v1alpha1.PortRange{{From: 81, To: 90}, {From: 80, To: 80}},
+			want:   []v1alpha1.PortRange{{From: 80, To: 80}, {From: 81, To: 90}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NormalizePortRanges(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nNormalizePortRanges(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPortRangesFromAPI(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     []string
+		want   []v1alpha1.PortRange
+		err    error
+	}{
+		"Empty": {
+			reason: "No ports should be converted to no port ranges.",
+		},
+		"Pairs": {
+			reason: "Alternating from and to ports should be converted to normalized port ranges.",
+			in:     []string{"443", "443", "80", "90", "85", "85"},
+			want:   []v1alpha1.PortRange{{From: 80, To: 90}, {From: 443, To: 443}},
+		},
+		"OddLength": {
+			reason: "A trailing port without a matching end should be a single port range.",
+			in:     []string{"80", "90", "443"},
+			want:   []v1alpha1.PortRange{{From: 80, To: 90}, {From: 443, To: 443}},
+		},
+		"InvalidFrom": {
+			reason: "A from port that is no number should be an error.",
+			in:     []string{"http", "80"},
+			err:    &strconv.NumError{Func: "ParseInt", Num: "http", Err: strconv.ErrSyntax},
+		},
+		"InvalidTo": {
+			reason: "A to port that is no number should be an error.",
+			in:     []string{"80", "http"},
+			err:    &strconv.NumError{Func: "ParseInt", Num: "http", Err: strconv.ErrSyntax},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := PortRangesFromAPI(tc.in)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPortRangesFromAPI(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nPortRangesFromAPI(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPortRangesToAPI(t *testing.T) {
+	got := PortRangesToAPI([]v1alpha1.PortRange{{From: 443, To: 443}, {From: 80, To: 90}, {From: 85, To: 85}})
+	want := []string{"80", "90", "443", "443"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PortRangesToAPI(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestConvertDeprecatedPortRanges(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.ApplicationSegmentParameters
+		want   v1alpha1.ApplicationSegmentParameters
+		err    error
+	}{
+		"Deprecated": {
+			reason: "Deprecated port ranges should be converted and cleared.",
+			in: v1alpha1.ApplicationSegmentParameters{
+				TCPPortRanges: []string{"443", "443"},
+				UDPPortRanges: []string{"53", "53"},
+			},
+			want: v1alpha1.ApplicationSegmentParameters{
+				TCPPortRange: []v1alpha1.PortRange{{From: 443, To: 443}},
+				UDPPortRange: []v1alpha1.PortRange{{From: 53, To: 53}},
+			},
+		},
+		"Typed": {
+			reason: "Typed port ranges should take precedence over deprecated ones.",
+			in: v1alpha1.ApplicationSegmentParameters{
+				TCPPortRange:  []v1alpha1.PortRange{{From: 80, To: 80}},
+				TCPPortRanges: []string{"443", "443"},
+			},
+			want: v1alpha1.ApplicationSegmentParameters{
+				TCPPortRange: []v1alpha1.PortRange{{From: 80, To: 80}},
+			},
+		},
+		"Invalid": {
+			reason: "Deprecated port ranges that are no numbers should be an error.",
+			in: v1alpha1.ApplicationSegmentParameters{
+				UDPPortRanges: []string{"dns"},
+			},
+			want: v1alpha1.ApplicationSegmentParameters{
+				UDPPortRanges: []string{"dns"},
+			},
+			err: errors.Wrap(&strconv.NumError{Func: "ParseInt", Num: "dns", Err: strconv.ErrSyntax}, errInvalidUDPPortRanges),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ConvertDeprecatedPortRanges(tc.in)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConvertDeprecatedPortRanges(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nConvertDeprecatedPortRanges(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
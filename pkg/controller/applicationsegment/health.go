@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Crossplane Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/haarchri/zpa-go-client/pkg/client/connector_group_controller"
+	"github.com/haarchri/zpa-go-client/pkg/client/server_group_controller"
+	"github.com/haarchri/zpa-go-client/pkg/models"
+
+	"github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+)
+
+const (
+	errGetServerGroupHealth    = "cannot get ServerGroup %s"
+	errGetConnectorGroupHealth = "cannot get AppConnectorGroup %s"
+)
+
+// observeHealth reports the health of the supplied server groups of the
+// ApplicationSegment in its Healthy condition and status. ZPA does not expose
+// the reachability of single applications, i.e. the results of the health
+// checks configured by healthReporting, so the health is derived from the
+// connectors and servers of each server group.
+func observeHealth(ctx context.Context, c *adapter.Client, cr *v1alpha1.ApplicationSegment, groups []*models.AppServerGroup) {
+	health, err := health(ctx, c, groups)
+	if err != nil {
+		cr.Status.AtProvider.Health = nil
+		cr.SetConditions(zpaclient.HealthUnknown(err))
+		return
+	}
+	cr.Status.AtProvider.Health = health
+
+	var reasons []string
+	if len(health.ServerGroups) == 0 {
+		reasons = append(reasons, "no server groups")
+	}
+	for _, g := range health.ServerGroups {
+		if !g.Healthy {
+			reasons = append(reasons, fmt.Sprintf("server group %q (%s) has %d of %d connectors connected and %d of %d servers enabled",
+				g.Name, g.ID, g.ConnectedConnectors, g.Connectors, g.EnabledServers, g.Servers))
+		}
+	}
+	if len(reasons) > 0 {
+		cr.SetConditions(zpaclient.Unhealthy(reasons))
+		return
+	}
+	cr.SetConditions(zpaclient.Healthy())
+}
+
+// clearHealth removes the health reported for the ApplicationSegment, so that
+// no stale Healthy condition remains once it no longer asks for its health.
+func clearHealth(cr *v1alpha1.ApplicationSegment) {
+	cr.Status.AtProvider.Health = nil
+
+	conditions := cr.Status.Conditions[:0]
+	for _, c := range cr.Status.Conditions {
+		if c.Type != zpaclient.TypeHealthy {
+			conditions = append(conditions, c)
+		}
+	}
+	cr.Status.Conditions = conditions
+}
+
+// health returns the health of the supplied server groups.
+func health(ctx context.Context, c *adapter.Client, groups []*models.AppServerGroup) (*v1alpha1.ApplicationHealth, error) {
+	// Server groups often share connector groups, so each is read once.
+	counts := map[string]connectorCount{}
+
+	h := &v1alpha1.ApplicationHealth{ServerGroups: make([]v1alpha1.ServerGroupHealth, 0, len(groups))}
+	for _, sg := range groups {
+		if sg == nil {
+			continue
+		}
+		req := &server_group_controller.GetServerGroupUsingGET1Params{
+			Context:    ctx,
+			CustomerID: c.CustomerID,
+			GroupID:    sg.ID,
+		}
+		resp, err := c.ServerGroupController.GetServerGroupUsingGET1(req)
+		if err != nil {
+			return nil, errors.Wrapf(err, errGetServerGroupHealth, sg.ID)
+		}
+
+		g := v1alpha1.ServerGroupHealth{ID: sg.ID, Name: resp.Payload.Name}
+		for _, acg := range resp.Payload.AppConnectorGroups {
+			if acg == nil {
+				continue
+			}
+			n, ok := counts[acg.ID]
+			if !ok {
+				n, err = connectors(ctx, c, acg.ID)
+				if err != nil {
+					return nil, err
+				}
+				counts[acg.ID] = n
+			}
+			g.ConnectedConnectors += n.connected
+			g.Connectors += n.total
+		}
+		for _, s := range resp.Payload.Servers {
+			if s == nil {
+				continue
+			}
+			g.Servers++
+			if s.Enabled {
+				g.EnabledServers++
+			}
+		}
+		g.Healthy = resp.Payload.Enabled && g.ConnectedConnectors > 0 && (g.EnabledServers > 0 || resp.Payload.DynamicDiscovery)
+		if g.Healthy {
+			h.HealthyServerGroups++
+		}
+		h.ServerGroups = append(h.ServerGroups, g)
+	}
+	return h, nil
+}
+
+// connectorCount counts the connectors of a connector group.
+type connectorCount struct {
+	connected int
+	total     int
+}
+
+// connectors counts the connectors of the supplied connector group.
+func connectors(ctx context.Context, c *adapter.Client, id string) (connectorCount, error) {
+	req := &connector_group_controller.GetAppConnectorGroupUsingGET1Params{
+		Context:             ctx,
+		CustomerID:          c.CustomerID,
+		AppConnectorGroupID: id,
+	}
+	resp, err := c.ConnectorGroupController.GetAppConnectorGroupUsingGET1(req)
+	if err != nil {
+		return connectorCount{}, errors.Wrapf(err, errGetConnectorGroupHealth, id)
+	}
+
+	n := connectorCount{}
+	for _, conn := range resp.Payload.Connectors {
+		if conn == nil {
+			continue
+		}
+		n.total++
+		if conn.Enabled && conn.ControlChannelStatus == models.ConnectorControlChannelStatusZPNSTATUSAUTHENTICATED {
+			n.connected++
+		}
+	}
+	return n, nil
+}
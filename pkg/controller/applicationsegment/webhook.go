@@ -0,0 +1,222 @@
+/*
+Copyright 2022 The Crossplane Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+)
+
+const (
+	webhookPath = "/validate-zpa-crossplane-io-v1alpha1-applicationsegment"
+
+	errNewDecoder              = "cannot create admission decoder"
+	errDecodeApplicationSeg    = "cannot decode ApplicationSegment"
+	errListApplicationSegments = "cannot list ApplicationSegments"
+	errOverlap                 = "ApplicationSegment %q overlaps with ApplicationSegment %q: domain %q and domain %q share %s ports %d-%d"
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-zpa-crossplane-io-v1alpha1-applicationsegment,mutating=false,failurePolicy=fail,groups=zpa.crossplane.io,resources=applicationsegments,versions=v1alpha1,name=applicationsegments.zpa.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+// SetupApplicationSegmentWebhook registers a validating webhook that rejects
+// ApplicationSegments with invalid port ranges or overlapping with other
+// ApplicationSegments of the same ProviderConfig.
+func SetupApplicationSegmentWebhook(mgr ctrl.Manager) error {
+	d, err := admission.NewDecoder(mgr.GetScheme())
+	if err != nil {
+		return errors.Wrap(err, errNewDecoder)
+	}
+
+	mgr.GetWebhookServer().Register(webhookPath, &webhook.Admission{Handler: &validator{kube: mgr.GetClient(), decoder: d}})
+	return nil
+}
+
+type validator struct {
+	kube    client.Client
+	decoder *admission.Decoder
+}
+
+// Handle rejects an ApplicationSegment with invalid port ranges, or whose
+// domain names and ports overlap with another ApplicationSegment using the
+// same ProviderConfig, because ZPA refuses to create or update such segments.
+func (v *validator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	cr := &v1alpha1.ApplicationSegment{}
+	if err := v.decoder.Decode(req, cr); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeApplicationSeg))
+	}
+
+	// The provider itself updates ApplicationSegments, e.g. to late initialize
+	// them. Only changes to the fields that matter for overlaps are validated,
+	// so that segments that are invalid or overlap already do not get stuck.
+	if req.Operation == admissionv1.Update {
+		old := &v1alpha1.ApplicationSegment{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeApplicationSeg))
+		}
+		if !overlapRelevantChange(old, cr) {
+			return admission.Allowed("")
+		}
+	}
+
+	if err := validatePortRanges(cr); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	l := &v1alpha1.ApplicationSegmentList{}
+	if err := v.kube.List(ctx, l); err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errListApplicationSegments))
+	}
+
+	for i := range l.Items {
+		other := &l.Items[i]
+		if other.GetName() == cr.GetName() || meta.WasDeleted(other) || !zpaclient.SameProviderConfig(other, cr) {
+			continue
+		}
+		if msg := overlap(cr, other); msg != "" {
+			return admission.Denied(msg)
+		}
+	}
+
+	return admission.Allowed("")
+}
+
+func overlapRelevantChange(old, cr *v1alpha1.ApplicationSegment) bool {
+	return !zpaclient.SameProviderConfig(old, cr) ||
+		!cmp.Equal(old.Spec.ForProvider.DomainNames, cr.Spec.ForProvider.DomainNames) ||
+		!cmp.Equal(old.Spec.ForProvider.TCPPortRange, cr.Spec.ForProvider.TCPPortRange) ||
+		!cmp.Equal(old.Spec.ForProvider.UDPPortRange, cr.Spec.ForProvider.UDPPortRange) ||
+		!cmp.Equal(old.Spec.ForProvider.TCPPortRanges, cr.Spec.ForProvider.TCPPortRanges) ||
+		!cmp.Equal(old.Spec.ForProvider.UDPPortRanges, cr.Spec.ForProvider.UDPPortRanges)
+}
+
+// validatePortRanges returns an error if a port range of the supplied
+// ApplicationSegment is outside of the valid ports or starts after it ends.
+func validatePortRanges(cr *v1alpha1.ApplicationSegment) error {
+	p, err := ConvertDeprecatedPortRanges(cr.Spec.ForProvider)
+	if err != nil {
+		return err
+	}
+	if err := ValidatePortRanges(p.TCPPortRange); err != nil {
+		return errors.Wrap(err, errInvalidTCPPortRange)
+	}
+	return errors.Wrap(ValidatePortRanges(p.UDPPortRange), errInvalidUDPPortRange)
+}
+
+// overlap returns a message describing the first domain and port combination
+// that the supplied ApplicationSegments have in common, or an empty string if
+// they do not overlap.
+func overlap(cr, other *v1alpha1.ApplicationSegment) string {
+	// Segments with invalid deprecated port ranges are rejected before, or
+	// cover no ports.
+	a, _ := ConvertDeprecatedPortRanges(cr.Spec.ForProvider)
+	b, _ := ConvertDeprecatedPortRanges(other.Spec.ForProvider)
+	for _, d1 := range a.DomainNames {
+		for _, d2 := range b.DomainNames {
+			if !domainsOverlap(d1, d2) {
+				continue
+			}
+			if r, ok := portRangesOverlap(a.TCPPortRange, b.TCPPortRange); ok {
+				return fmt.Sprintf(errOverlap, cr.GetName(), other.GetName(), d1, d2, "TCP", r.From, r.To)
+			}
+			if r, ok := portRangesOverlap(a.UDPPortRange, b.UDPPortRange); ok {
+				return fmt.Sprintf(errOverlap, cr.GetName(), other.GetName(), d1, d2, "UDP", r.From, r.To)
+			}
+		}
+	}
+	return ""
+}
+
+// portRangesOverlap returns the first range of ports that is part of both
+// supplied lists of port ranges.
+func portRangesOverlap(a, b []v1alpha1.PortRange) (v1alpha1.PortRange, bool) {
+	for _, r1 := range NormalizePortRanges(a) {
+		for _, r2 := range NormalizePortRanges(b) {
+			if r1.From <= r2.To && r2.From <= r1.To {
+				return v1alpha1.PortRange{From: max32(r1.From, r2.From), To: min32(r1.To, r2.To)}, true
+			}
+		}
+	}
+	return v1alpha1.PortRange{}, false
+}
+
+// domainsOverlap returns whether the supplied domain names match at least one
+// common host. Wildcard domains such as *.example.com match all subdomains,
+// and IP addresses and CIDR ranges overlap if one contains the other.
+func domainsOverlap(a, b string) bool {
+	a = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(a)), ".")
+	b = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(b)), ".")
+	if a == b {
+		return true
+	}
+
+	if n1, n2 := parseNetwork(a), parseNetwork(b); n1 != nil && n2 != nil {
+		return n1.Contains(n2.IP) || n2.Contains(n1.IP)
+	}
+
+	return wildcardCovers(a, b) || wildcardCovers(b, a)
+}
+
+// wildcardCovers returns whether the wildcard domain w matches the domain d,
+// which may itself be a wildcard domain.
+func wildcardCovers(w, d string) bool {
+	if !strings.HasPrefix(w, "*.") {
+		return false
+	}
+	return strings.HasSuffix(strings.TrimPrefix(d, "*"), w[1:])
+}
+
+func parseNetwork(s string) *net.IPNet {
+	if _, n, err := net.ParseCIDR(s); err == nil {
+		return n
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil
+	}
+	bits := 8 * net.IPv6len
+	if ip.To4() != nil {
+		ip, bits = ip.To4(), 8*net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+}
+
+func min32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max32(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2022 The Crossplane Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane-contrib/provider-zpa/apis"
+	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+)
+
+func TestDomainsOverlap(t *testing.T) {
+	cases := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"SameDomain":             {a: "app.example.com", b: "app.example.com", want: true},
+		"CaseAndTrailingDot":     {a: "App.Example.com.", b: "app.example.com", want: true},
+		"DifferentDomains":       {a: "app.example.com", b: "web.example.com", want: false},
+		"WildcardCoversExact":    {a: "*.example.com", b: "app.example.com", want: true},
+		"ExactCoveredByWildcard": {a: "app.example.com", b: "*.example.com", want: true},
+		"WildcardCoversNested":   {a: "*.example.com", b: "a.b.example.com", want: true},
+		"WildcardNotApex":        {a: "*.example.com", b: "example.com", want: false},
+		"WildcardNotSuffix":      {a: "*.example.com", b: "badexample.com", want: false},
+		"NestedWildcards":        {a: "*.example.com", b: "*.app.example.com", want: true},
+		"DisjointWildcards":      {a: "*.example.com", b: "*.example.org", want: false},
+		"SameIP":                 {a: "10.0.0.1", b: "10.0.0.1", want: true},
+		"DifferentIPs":           {a: "10.0.0.1", b: "10.0.0.2", want: false},
+		"CIDRContainsIP":         {a: "10.0.0.0/24", b: "10.0.0.42", want: true},
+		"IPInCIDR":               {a: "10.0.0.42", b: "10.0.0.0/24", want: true},
+		"IPOutsideCIDR":          {a: "10.0.1.1", b: "10.0.0.0/24", want: false},
+		"NestedCIDRs":            {a: "10.0.0.0/16", b: "10.0.3.0/24", want: true},
+		"DisjointCIDRs":          {a: "10.0.0.0/24", b: "10.0.1.0/24", want: false},
+		"IPv6CIDRContainsIP":     {a: "fd00::/64", b: "fd00::1", want: true},
+		"IPAndDomain":            {a: "10.0.0.1", b: "app.example.com", want: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := domainsOverlap(tc.a, tc.b); got != tc.want {
+				t.Errorf("domainsOverlap(%q, %q): want %t, got %t", tc.a, tc.b, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPortRangesOverlap(t *testing.T) {
+	cases := map[string]struct {
+		a, b   []v1alpha1.PortRange
+		want   v1alpha1.PortRange
+		wantOK bool
+	}{
+		"Same": {
+			a:      []v1alpha1.PortRange{{From: 443, To: 443}},
+			b:      []v1alpha1.PortRange{{From: 443, To: 443}},
+			want:   v1alpha1.PortRange{From: 443, To: 443},
+			wantOK: true,
+		},
+		"Overlapping": {
+			a:      []v1alpha1.PortRange{{From: 8000, To: 8100}},
+			b:      []v1alpha1.PortRange{{From: 8050, To: 8200}},
+			want:   v1alpha1.PortRange{From: 8050, To: 8100},
+			wantOK: true,
+		},
+		"Contained": {
+			a:      []v1alpha1.PortRange{{From: 1, To: 65535}},
+			b:      []v1alpha1.PortRange{{From: 22, To: 22}},
+			want:   v1alpha1.PortRange{From: 22, To: 22},
+			wantOK: true,
+		},
+		"SharedEnd": {
+			a:      []v1alpha1.PortRange{{From: 80, To: 90}},
+			b:      []v1alpha1.PortRange{{From: 90, To: 100}},
+			want:   v1alpha1.PortRange{From: 90, To: 90},
+			wantOK: true,
+		},
+		"Adjacent": {
+			a: []v1alpha1.PortRange{{From: 80, To: 89}},
+			b: []v1alpha1.PortRange{{From: 90, To: 100}},
+		},
+		"Disjoint": {
+			a: []v1alpha1.PortRange{{From: 80, To: 80}, {From: 443, To: 443}},
+			b: []v1alpha1.PortRange{{From: 8080, To: 8080}},
+		},
+		"Empty": {
+			a: []v1alpha1.PortRange{{From: 80, To: 80}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := portRangesOverlap(tc.a, tc.b)
+			if ok != tc.wantOK {
+				t.Errorf("portRangesOverlap(...): want %t, got %t", tc.wantOK, ok)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("portRangesOverlap(...): -want, +got:\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestOverlap(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		cr     *v1alpha1.ApplicationSegment
+		other  *v1alpha1.ApplicationSegment
+		want   string
+	}{
+		"TCP": {
+			reason: "Segments sharing a domain and TCP ports should overlap.",
+			cr:     applicationSegment(withName("a"), withDomains("app.example.com"), withTCP(v1alpha1.PortRange{From: 443, To: 443})),
+			other:  applicationSegment(withName("b"), withDomains("*.example.com"), withTCP(v1alpha1.PortRange{From: 1, To: 1024})),
+			want:   fmt.Sprintf(errOverlap, "a", "b", "app.example.com", "*.example.com", "TCP", 443, 443),
+		},
+		"UDP": {
+			reason: "Segments sharing a domain and UDP ports should overlap.",
+			cr:     applicationSegment(withName("a"), withDomains("10.0.0.53"), withTCP(), withUDP(v1alpha1.PortRange{From: 53, To: 53})),
+			other:  applicationSegment(withName("b"), withDomains("10.0.0.0/24"), withTCP(), withUDP(v1alpha1.PortRange{From: 53, To: 53})),
+			want:   fmt.Sprintf(errOverlap, "a", "b", "10.0.0.53", "10.0.0.0/24", "UDP", 53, 53),
+		},
+		"Deprecated": {
+			reason: "Deprecated port ranges should be checked for overlaps.",
+			cr: applicationSegment(withName("a"), withDomains("app.example.com"), withTCP(), withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+				p.TCPPortRanges = []string{"80", "90"}
+			})),
+			other: applicationSegment(withName("b"), withDomains("app.example.com"), withTCP(v1alpha1.PortRange{From: 85, To: 85})),
+			want:  fmt.Sprintf(errOverlap, "a", "b", "app.example.com", "app.example.com", "TCP", 85, 85),
+		},
+		"DifferentProtocols": {
+			reason: "Segments using the same ports of different protocols should not overlap.",
+			cr:     applicationSegment(withName("a"), withDomains("app.example.com"), withTCP(v1alpha1.PortRange{From: 53, To: 53})),
+			other:  applicationSegment(withName("b"), withDomains("app.example.com"), withTCP(), withUDP(v1alpha1.PortRange{From: 53, To: 53})),
+		},
+		"DifferentDomains": {
+			reason: "Segments of different domains should not overlap.",
+			cr:     applicationSegment(withName("a"), withDomains("app.example.com"), withTCP(v1alpha1.PortRange{From: 443, To: 443})),
+			other:  applicationSegment(withName("b"), withDomains("web.example.com"), withTCP(v1alpha1.PortRange{From: 443, To: 443})),
+		},
+		"AdjacentPorts": {
+			reason: "Segments of the same domain with adjacent ports should not overlap.",
+			cr:     applicationSegment(withName("a"), withDomains("app.example.com"), withTCP(v1alpha1.PortRange{From: 80, To: 89})),
+			other:  applicationSegment(withName("b"), withDomains("app.example.com"), withTCP(v1alpha1.PortRange{From: 90, To: 99})),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, overlap(tc.cr, tc.other)); diff != "" {
+				t.Errorf("\n%s\noverlap(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	d, err := admission.NewDecoder(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := applicationSegment(withName("existing"), withDomains("*.example.com"), withTCP(v1alpha1.PortRange{From: 443, To: 443}))
+
+	type args struct {
+		op  admissionv1.Operation
+		cr  *v1alpha1.ApplicationSegment
+		old *v1alpha1.ApplicationSegment
+	}
+	cases := map[string]struct {
+		reason  string
+		args    args
+		allowed bool
+		message string
+	}{
+		"NoOverlap": {
+			reason:  "A segment that overlaps with no other segment should be allowed.",
+			args:    args{op: admissionv1.Create, cr: applicationSegment(withName("new"), withDomains("app.example.org"), withTCP(v1alpha1.PortRange{From: 443, To: 443}))},
+			allowed: true,
+		},
+		"Overlap": {
+			reason:  "A segment that overlaps with another segment should be denied, naming the other segment.",
+			args:    args{op: admissionv1.Create, cr: applicationSegment(withName("new"), withDomains("app.example.com"), withTCP(v1alpha1.PortRange{From: 443, To: 443}))},
+			message: fmt.Sprintf(errOverlap, "new", "existing", "app.example.com", "*.example.com", "TCP", 443, 443),
+		},
+		"OtherProviderConfig": {
+			reason:  "A segment should not be checked against segments of other ProviderConfigs.",
+			args:    args{op: admissionv1.Create, cr: applicationSegment(withName("new"), withDomains("app.example.com"), withTCP(v1alpha1.PortRange{From: 443, To: 443}), withProviderConfig("other"))},
+			allowed: true,
+		},
+		"Itself": {
+			reason:  "A segment should not overlap with itself.",
+			args:    args{op: admissionv1.Update, cr: applicationSegment(withName("existing"), withDomains("*.example.com"), withTCP(v1alpha1.PortRange{From: 443, To: 444})), old: existing},
+			allowed: true,
+		},
+		"InvalidPortRange": {
+			reason:  "A segment with a port range that starts after it ends should be denied.",
+			args:    args{op: admissionv1.Create, cr: applicationSegment(withName("new"), withDomains("app.example.org"), withTCP(v1alpha1.PortRange{From: 443, To: 80}))},
+			message: errInvalidTCPPortRange + ": " + fmt.Sprintf(errPortRangeOrder, 443, 80),
+		},
+		"UnrelatedUpdate": {
+			reason: "An update that does not change domains, ports or ProviderConfig should be allowed, even if the segment overlaps.",
+			args: args{
+				op: admissionv1.Update,
+				cr: applicationSegment(withName("new"), withDomains("app.example.com"), withTCP(v1alpha1.PortRange{From: 443, To: 443}), withSpec(func(p *v1alpha1.ApplicationSegmentParameters) {
+					p.Description = "changed"
+				})),
+				old: applicationSegment(withName("new"), withDomains("app.example.com"), withTCP(v1alpha1.PortRange{From: 443, To: 443})),
+			},
+			allowed: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{kube: fake.NewClientBuilder().WithScheme(s).WithObjects(existing.DeepCopy()).Build(), decoder: d}
+
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: tc.args.op, Object: raw(t, tc.args.cr)}}
+			if tc.args.old != nil {
+				req.OldObject = raw(t, tc.args.old)
+			}
+
+			got := v.Handle(context.Background(), req)
+			if got.Allowed != tc.allowed {
+				t.Errorf("\n%s\nHandle(...): want allowed %t, got %t: %v", tc.reason, tc.allowed, got.Allowed, got.Result)
+			}
+			if tc.message == "" {
+				return
+			}
+			if diff := cmp.Diff(tc.message, string(got.Result.Reason)); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want reason, +got reason:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func raw(t *testing.T, cr *v1alpha1.ApplicationSegment) runtime.RawExtension {
+	t.Helper()
+	cr = cr.DeepCopy()
+	cr.SetGroupVersionKind(v1alpha1.ApplicationSegmentGroupVersionKind)
+	b, err := json.Marshal(cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runtime.RawExtension{Raw: b}
+}
@@ -16,260 +16,295 @@ package segment
 import (
 	"context"
 
-	"github.com/go-openapi/runtime"
-	"github.com/go-openapi/strfmt"
-	"github.com/google/go-cmp/cmp"
-
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
-	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
-	zpa "github.com/haarchri/zpa-go-client/pkg/client"
 	"github.com/haarchri/zpa-go-client/pkg/client/segment_group_controller"
 	"github.com/haarchri/zpa-go-client/pkg/models"
 
+	appv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
 	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/segmentgroup/v1alpha1"
-	store "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpav1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
 	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
-	"github.com/crossplane-contrib/provider-zpa/pkg/features"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
 )
 
 const (
-	errNotSegmentGroup = "managed resource is not an SegmentGroup custom resource"
-	errCreateFailed    = "cannot create SegmentGroup"
-	errUpdateFailed    = "cannot update SegmentGroup"
-	errDescribeFailed  = "cannot describe SegmentGroup"
-	errDeleteFailed    = "cannot delete SegmentGroup"
+	errDescribeFailed          = "cannot describe SegmentGroup"
+	errListApplicationSegments = "cannot list ApplicationSegments"
 )
 
 // SetupSegmentGroup adds a controller that reconciles SegmentGroups.
 func SetupSegmentGroup(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha1.SegmentGroupGroupKind)
+	return adapter.Setup[*v1alpha1.SegmentGroup, *models.SegmentGroup](mgr, o, kind{}, v1alpha1.SegmentGroupGroupVersionKind, &v1alpha1.SegmentGroup{})
+}
+
+// NewKind returns the kind that reconciles SegmentGroups.
+func NewKind() adapter.Kind[*v1alpha1.SegmentGroup, *models.SegmentGroup] {
+	return kind{}
+}
 
-	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
-	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
-		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), store.StoreConfigGroupVersionKind))
+// kind reconciles SegmentGroups with ZPA segment groups.
+type kind struct{}
+
+func (kind) Kind() string { return v1alpha1.SegmentGroupKind }
+
+func (kind) Parameters(cr *v1alpha1.SegmentGroup) adapter.Parameters {
+	p := cr.Spec.ForProvider
+	return adapter.Parameters{
+		Name:               zpaclient.StringValue(p.Name),
+		ImportByName:       zpaclient.BoolValue(p.ImportByName),
+		NameConflictPolicy: p.NameConflictPolicy,
+		IgnoreDrift:        p.IgnoreDrift,
+		ObserveOnly:        zpaclient.BoolValue(p.ObserveOnly),
 	}
+}
 
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		WithOptions(o.ForControllerRuntime()).
-		For(&v1alpha1.SegmentGroup{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha1.SegmentGroupGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), newClientFn: zpa.New}),
-			managed.WithConnectionPublishers(),
-			managed.WithInitializers(),
-			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
-			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithConnectionPublishers(cps...)))
+// Fields are the JSON names in the ZPA API of the fields Update sets from the
+// parameters of a SegmentGroup.
+func (kind) Fields() adapter.Fields {
+	return adapter.Fields{Modelled: []string{
+		"configSpace",
+		"description",
+		"enabled",
+		"name",
+		"tcpKeepAliveEnabled",
+	}}
 }
 
-type connector struct {
-	kube        client.Client
-	newClientFn func(transport runtime.ClientTransport, formats strfmt.Registry) *zpa.ZscalerPrivateAccessAPIPortal
+func (kind) ToAPI(_ context.Context, _ *adapter.Client, cr *v1alpha1.SegmentGroup) (*models.SegmentGroup, error) {
+	return &models.SegmentGroup{
+		Name:        cr.Spec.ForProvider.Name,
+		ConfigSpace: cr.Spec.ForProvider.ConfigSpace,
+		Description: cr.Spec.ForProvider.Description,
+		// update enable to false is not possible via update in the api
+		Enabled:             zpaclient.BoolValue(cr.Spec.ForProvider.Enabled),
+		TCPKeepAliveEnabled: cr.Spec.ForProvider.TCPKeepAliveEnabled,
+	}, nil
 }
 
-type external struct {
-	client *zpa.ZscalerPrivateAccessAPIPortal
-	kube   client.Client
+func (kind) FromAPI(cr *v1alpha1.SegmentGroup, obj *models.SegmentGroup) {
+	cr.Status.AtProvider = generateObservation(obj)
 }
 
-func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	_, ok := mg.(*v1alpha1.SegmentGroup)
-	if !ok {
-		return nil, errors.New(errNotSegmentGroup)
+func (kind) LateInitialize(cr *v1alpha1.SegmentGroup, obj *models.SegmentGroup) {
+	if cr.Spec.ForProvider.ConfigSpace == "" && obj.ConfigSpace != "" {
+		cr.Spec.ForProvider.ConfigSpace = obj.ConfigSpace
 	}
 
-	cfg, err := zpaclient.GetConfig(ctx, c.kube, mg)
-	if err != nil {
-		return nil, err
+	if cr.Spec.ForProvider.TCPKeepAliveEnabled == "" && obj.TCPKeepAliveEnabled != "" {
+		cr.Spec.ForProvider.TCPKeepAliveEnabled = obj.TCPKeepAliveEnabled
 	}
-
-	client := c.newClientFn(cfg, strfmt.Default)
-	return &external{client, c.kube}, nil
 }
 
-func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.SegmentGroup)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotSegmentGroup)
+func (kind) Compare(cr *v1alpha1.SegmentGroup, obj *models.SegmentGroup) adapter.Comparison {
+	return adapter.Comparison{
+		Drifted:      driftedFields(&cr.Spec.ForProvider, obj),
+		Desired:      &cr.Spec.ForProvider,
+		Observed:     observedParameters(obj),
+		ModifiedBy:   obj.ModifiedBy,
+		ModifiedTime: obj.ModifiedTime,
 	}
+}
 
-	id := meta.GetExternalName(cr)
-	if id == "" {
-		return managed.ExternalObservation{
-			ResourceExists:   false,
-			ResourceUpToDate: false,
-		}, nil
-	}
+func (kind) LastDrift(cr *v1alpha1.SegmentGroup) *zpav1alpha1.Drift {
+	return cr.Status.AtProvider.LastDrift
+}
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
+func (kind) SetLastDrift(cr *v1alpha1.SegmentGroup, d *zpav1alpha1.Drift) {
+	cr.Status.AtProvider.LastDrift = d
+}
+
+func (kind) Get(ctx context.Context, c *adapter.Client, id string) (*models.SegmentGroup, error) {
 	req := &segment_group_controller.GetSegmentGroupUsingGET1Params{
 		Context:        ctx,
 		SegmentGroupID: id,
-		CustomerID:     customerID,
+		CustomerID:     c.CustomerID,
 	}
-	resp, reqErr := e.client.SegmentGroupController.GetSegmentGroupUsingGET1(req)
-	if reqErr != nil {
-		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(resource.Ignore(IsNotFound, reqErr), errDescribeFailed)
+	resp, err := c.SegmentGroupController.GetSegmentGroupUsingGET1(req)
+	if err != nil {
+		return nil, err
 	}
-
-	cr.Status.AtProvider = generateObservation(resp)
-
-	currentSpec := cr.Spec.ForProvider.DeepCopy()
-	e.LateInitialize(cr, resp)
-
-	cr.Status.SetConditions(v1.Available())
-
-	return managed.ExternalObservation{
-		ResourceExists:          true,
-		ResourceUpToDate:        isUpToDate(&cr.Spec.ForProvider, resp),
-		ResourceLateInitialized: !cmp.Equal(&cr.Spec.ForProvider, currentSpec),
-	}, nil
+	return resp.Payload, nil
 }
 
-func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.SegmentGroup)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotSegmentGroup)
+func (kind) List(ctx context.Context, c *adapter.Client, name string) ([]zpaclient.NamedObject, error) {
+	objs := make([]zpaclient.NamedObject, 0)
+	for page := int32(1); ; page++ {
+		req := &segment_group_controller.GetAllSegmentGroupsUsingGET1Params{
+			Context:    ctx,
+			CustomerID: c.CustomerID,
+			Page:       page,
+			Pagesize:   zpaclient.ListPageSize,
+			Search:     name,
+		}
+		resp, err := c.SegmentGroupController.GetAllSegmentGroupsUsingGET1(req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range resp.Payload.List {
+			objs = append(objs, zpaclient.NamedObject{ID: obj.ID, Name: zpaclient.StringValue(obj.Name)})
+		}
+
+		if page >= resp.Payload.TotalPages {
+			break
+		}
 	}
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
+	return objs, nil
+}
+
+func (kind) Create(ctx context.Context, c *adapter.Client, obj *models.SegmentGroup) (string, error) {
 	req := &segment_group_controller.AddSegmentGroupUsingPOST1Params{
-		Context:    ctx,
-		CustomerID: customerID,
-		SegmentGroup: &models.SegmentGroup{
-			Name:                cr.Spec.ForProvider.Name,
-			ConfigSpace:         cr.Spec.ForProvider.ConfigSpace,
-			Description:         cr.Spec.ForProvider.Description,
-			Enabled:             zpaclient.BoolValue(cr.Spec.ForProvider.Enabled),
-			TCPKeepAliveEnabled: cr.Spec.ForProvider.TCPKeepAliveEnabled,
-		},
+		Context:      ctx,
+		CustomerID:   c.CustomerID,
+		SegmentGroup: obj,
 	}
-
-	resp, err := e.client.SegmentGroupController.AddSegmentGroupUsingPOST1(req)
+	resp, err := c.SegmentGroupController.AddSegmentGroupUsingPOST1(req)
 	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+		return "", err
 	}
-
-	meta.SetExternalName(cr, resp.Payload.ID)
-	return managed.ExternalCreation{
-		ExternalNameAssigned: true,
-	}, nil
-
+	return resp.Payload.ID, nil
 }
 
-func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*v1alpha1.SegmentGroup)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotSegmentGroup)
-	}
-
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
+func (kind) Update(ctx context.Context, c *adapter.Client, id string, obj *models.SegmentGroup) error {
 	req := &segment_group_controller.UpdateSegmentGroupUsingPUT1Params{
 		Context:        ctx,
-		CustomerID:     customerID,
-		SegmentGroupID: meta.GetExternalName(cr),
-		SegmentGroup: &models.SegmentGroup{
-			Name:        cr.Spec.ForProvider.Name,
-			ID:          meta.GetExternalName(cr),
-			ConfigSpace: cr.Spec.ForProvider.ConfigSpace,
-			Description: cr.Spec.ForProvider.Description,
-			// update enable to false is not possible via update in the api
-			Enabled:             zpaclient.BoolValue(cr.Spec.ForProvider.Enabled),
-			TCPKeepAliveEnabled: cr.Spec.ForProvider.TCPKeepAliveEnabled,
-		},
-	}
-
-	if _, _, err := e.client.SegmentGroupController.UpdateSegmentGroupUsingPUT1(req); err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+		CustomerID:     c.CustomerID,
+		SegmentGroupID: id,
+		SegmentGroup:   obj,
 	}
-
-	return managed.ExternalUpdate{}, nil
+	_, _, err := c.SegmentGroupController.UpdateSegmentGroupUsingPUT1(req)
+	return err
 }
 
-func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.SegmentGroup)
-	if !ok {
-		return errors.New(errNotSegmentGroup)
+// Delete deletes the supplied SegmentGroup unless ApplicationSegments or
+// policy rules use it.
+func (kind) Delete(ctx context.Context, c *adapter.Client, cr *v1alpha1.SegmentGroup, id string) error {
+	dependents, err := dependents(ctx, c, cr, id)
+	if err != nil {
+		return err
 	}
-
-	id := meta.GetExternalName(cr)
-	if id == "" {
-		return errors.New(errNotSegmentGroup)
+	if len(dependents) > 0 {
+		cond := zpaclient.HasDependents(dependents)
+		cr.SetConditions(cond)
+		return errors.New(cond.Message)
 	}
+	zpaclient.UnblockDeletion(cr)
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
 	req := &segment_group_controller.DeleteSegmentGroupUsingDELETE1Params{
 		Context:        ctx,
 		SegmentGroupID: id,
-		CustomerID:     customerID,
+		CustomerID:     c.CustomerID,
 	}
+	_, err = c.SegmentGroupController.DeleteSegmentGroupUsingDELETE1(req)
+	return err
+}
 
-	_, err := e.client.SegmentGroupController.DeleteSegmentGroupUsingDELETE1(req)
-	if err != nil {
-		return errors.Wrap(err, errDeleteFailed)
+func (kind) Import(obj *models.SegmentGroup) *v1alpha1.SegmentGroup {
+	cr := &v1alpha1.SegmentGroup{Spec: v1alpha1.SegmentGroupSpec{ForProvider: *observedParameters(obj)}}
+	meta.SetExternalName(cr, obj.ID)
+	return cr
+}
+
+// dependents describes the ApplicationSegments in the cluster whose
+// SegmentGroupID is the supplied SegmentGroup, the applications ZPA reports
+// for it and the policy rules that reference it.
+func dependents(ctx context.Context, c *adapter.Client, cr *v1alpha1.SegmentGroup, id string) ([]string, error) {
+	l := &appv1alpha1.ApplicationSegmentList{}
+	if err := c.Kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListApplicationSegments)
 	}
 
-	return nil
-}
+	mgs := make([]resource.Managed, 0)
+	for i := range l.Items {
+		as := &l.Items[i]
+		if zpaclient.StringValue(as.Spec.ForProvider.SegmentGroupID) == id && zpaclient.SameProviderConfig(as, cr) {
+			mgs = append(mgs, as)
+		}
+	}
 
-func (e *external) LateInitialize(cr *v1alpha1.SegmentGroup, obj *segment_group_controller.GetSegmentGroupUsingGET1OK) { // nolint:gocyclo
+	obj, err := kind{}.Get(ctx, c, id)
+	if zpaclient.IsNotFound(err) {
+		return zpaclient.DescribeDependents("ApplicationSegment", mgs, nil), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errDescribeFailed)
+	}
 
-	if cr.Spec.ForProvider.ConfigSpace == "" && obj.Payload.ConfigSpace != "" {
-		cr.Spec.ForProvider.ConfigSpace = obj.Payload.ConfigSpace
+	objs := make([]zpaclient.NamedObject, 0, len(obj.Applications))
+	for _, app := range obj.Applications {
+		objs = append(objs, zpaclient.NamedObject{ID: app.ID, Name: zpaclient.StringValue(app.Name)})
 	}
 
-	if cr.Spec.ForProvider.TCPKeepAliveEnabled == "" && obj.Payload.TCPKeepAliveEnabled != "" {
-		cr.Spec.ForProvider.TCPKeepAliveEnabled = obj.Payload.TCPKeepAliveEnabled
+	rules, err := zpaclient.ReferencingPolicyRules(ctx, c.ZscalerPrivateAccessAPIPortal, c.CustomerID, zpaclient.ObjectTypeSegmentGroup, id)
+	if err != nil {
+		return nil, err
 	}
 
+	return append(zpaclient.DescribeDependents("ApplicationSegment", mgs, objs), zpaclient.DescribePolicyRules(rules)...), nil
 }
 
-// generateObservation generates observation for the input object segment_group_controller.GetSegmentGroupUsingGET1OK
-func generateObservation(in *segment_group_controller.GetSegmentGroupUsingGET1OK) v1alpha1.Observation {
+// generateObservation generates observation for the input object models.SegmentGroup
+func generateObservation(obj *models.SegmentGroup) v1alpha1.Observation {
 	cr := v1alpha1.Observation{}
 
-	obj := in.Payload
-
 	cr.CreationTime = obj.CreationTime
 	cr.ID = obj.ID
 	cr.ModifiedBy = obj.ModifiedBy
 	cr.ModifiedTime = obj.ModifiedTime
 	cr.PolicyMigrated = obj.PolicyMigrated
+	cr.Name = zpaclient.StringValue(obj.Name)
+	cr.ConfigSpace = obj.ConfigSpace
+	cr.Description = obj.Description
+	cr.Enabled = obj.Enabled
+	cr.TCPKeepAliveEnabled = obj.TCPKeepAliveEnabled
+	for _, app := range obj.Applications {
+		if app != nil {
+			cr.Applications = append(cr.Applications, v1alpha1.NameID{ID: app.ID, Name: zpaclient.StringValue(app.Name)})
+		}
+	}
 
 	return cr
 }
 
-// isUpToDate checks whether there is a change in any of the modifiable fields.
-func isUpToDate(cr *v1alpha1.SegmentGroupParameters, gobj *segment_group_controller.GetSegmentGroupUsingGET1OK) bool { // nolint:gocyclo
-	obj := gobj.Payload
+// observedParameters returns the parameters of the supplied ZPA object.
+func observedParameters(obj *models.SegmentGroup) *v1alpha1.SegmentGroupParameters {
+	return &v1alpha1.SegmentGroupParameters{
+		ConfigSpace:         obj.ConfigSpace,
+		Description:         obj.Description,
+		Enabled:             zpaclient.Bool(obj.Enabled),
+		PolicyMigrated:      zpaclient.Bool(obj.PolicyMigrated),
+		TCPKeepAliveEnabled: obj.TCPKeepAliveEnabled,
+		Name:                obj.Name,
+	}
+}
+
+// driftedFields returns the modifiable fields whose desired value differs from
+// the value in ZPA.
+func driftedFields(cr *v1alpha1.SegmentGroupParameters, obj *models.SegmentGroup) []string { // nolint:gocyclo
+	fields := make([]string, 0)
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.Description), zpaclient.StringToPtr(obj.Description)) {
-		return false
+		fields = append(fields, "description")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.ConfigSpace), zpaclient.StringToPtr(obj.ConfigSpace)) {
-		return false
+		fields = append(fields, "configSpace")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.TCPKeepAliveEnabled), zpaclient.StringToPtr(obj.TCPKeepAliveEnabled)) {
-		return false
+		fields = append(fields, "tcpKeepAliveEnabled")
 	}
 
 	if !zpaclient.IsEqualString(cr.Name, obj.Name) {
-		return false
+		fields = append(fields, "name")
 	}
 
-	return true
+	return fields
 }
@@ -0,0 +1,688 @@
+/*
+Copyright 2022 The Crossplane Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package segment
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	zpa "github.com/haarchri/zpa-go-client/pkg/client"
+	"github.com/haarchri/zpa-go-client/pkg/client/policy_set_controller"
+	"github.com/haarchri/zpa-go-client/pkg/client/segment_group_controller"
+	"github.com/haarchri/zpa-go-client/pkg/models"
+
+	appv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/segmentgroup/v1alpha1"
+	zpav1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	mockpolicy "github.com/crossplane-contrib/provider-zpa/pkg/client/mock/policy_set_controller"
+	mocksg "github.com/crossplane-contrib/provider-zpa/pkg/client/mock/segment_group_controller"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+)
+
+const (
+	customerID = "216196257331281920"
+	id         = "72058000000000001"
+
+	errCreateFailed = "cannot create SegmentGroup"
+	errUpdateFailed = "cannot update SegmentGroup"
+	errDeleteFailed = "cannot delete SegmentGroup"
+)
+
+var (
+	errBoom     = errors.New("boom")
+	errNotFound = &zpaclient.APIError{StatusCode: http.StatusNotFound, Class: zpaclient.ErrorClassNotFound}
+	errConflict = &zpaclient.APIError{StatusCode: http.StatusBadRequest, ID: "duplicate.item", Class: zpaclient.ErrorClassConflict}
+)
+
+type sgModifier func(*v1alpha1.SegmentGroup)
+
+func withExternalName(n string) sgModifier {
+	return func(cr *v1alpha1.SegmentGroup) { meta.SetExternalName(cr, n) }
+}
+
+func withSpec(fn func(*v1alpha1.SegmentGroupParameters)) sgModifier {
+	return func(cr *v1alpha1.SegmentGroup) { fn(&cr.Spec.ForProvider) }
+}
+
+func withObservation(o v1alpha1.Observation) sgModifier {
+	return func(cr *v1alpha1.SegmentGroup) { cr.Status.AtProvider = o }
+}
+
+func withConditions(c ...xpv1.Condition) sgModifier {
+	return func(cr *v1alpha1.SegmentGroup) { cr.Status.SetConditions(c...) }
+}
+
+func segmentGroup(m ...sgModifier) *v1alpha1.SegmentGroup {
+	cr := &v1alpha1.SegmentGroup{
+		Spec: v1alpha1.SegmentGroupSpec{
+			ForProvider: v1alpha1.SegmentGroupParameters{
+				Name:                zpaclient.String("example"),
+				ConfigSpace:         "DEFAULT",
+				Description:         "managed by crossplane",
+				Enabled:             zpaclient.Bool(true),
+				TCPKeepAliveEnabled: "1",
+			},
+		},
+	}
+	meta.SetExternalName(cr, id)
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+func payload(m ...func(*models.SegmentGroup)) *models.SegmentGroup {
+	p := &models.SegmentGroup{
+		ID:                  id,
+		Name:                zpaclient.String("example"),
+		ConfigSpace:         "DEFAULT",
+		Description:         "managed by crossplane",
+		Enabled:             true,
+		TCPKeepAliveEnabled: "1",
+		ModifiedBy:          "72057594037928115",
+		ModifiedTime:        "1650000000",
+	}
+	for _, f := range m {
+		f(p)
+	}
+	return p
+}
+
+func observation(m ...func(*v1alpha1.Observation)) v1alpha1.Observation {
+	o := v1alpha1.Observation{
+		ID:                  id,
+		Name:                "example",
+		ConfigSpace:         "DEFAULT",
+		Description:         "managed by crossplane",
+		Enabled:             true,
+		TCPKeepAliveEnabled: "1",
+		ModifiedBy:          "72057594037928115",
+		ModifiedTime:        "1650000000",
+	}
+	for _, f := range m {
+		f(&o)
+	}
+	return o
+}
+
+type mocks struct {
+	sg     *mocksg.MockClientService
+	policy *mockpolicy.MockClientService
+}
+
+func newExternal(t *testing.T, kube client.Client, mock func(mocks)) *adapter.External[*v1alpha1.SegmentGroup, *models.SegmentGroup] {
+	ctrl := gomock.NewController(t)
+	m := mocks{
+		sg:     mocksg.NewMockClientService(ctrl),
+		policy: mockpolicy.NewMockClientService(ctrl),
+	}
+	if mock != nil {
+		mock(m)
+	}
+	c := &adapter.Client{
+		ZscalerPrivateAccessAPIPortal: &zpa.ZscalerPrivateAccessAPIPortal{
+			SegmentGroupController: m.sg,
+			PolicySetController:    m.policy,
+		},
+		CustomerID: customerID,
+		Kube:       kube,
+	}
+	return adapter.NewExternal[*v1alpha1.SegmentGroup, *models.SegmentGroup](c, kind{}, event.NewNopRecorder())
+}
+
+var equateStatus = []cmp.Option{
+	test.EquateConditions(),
+	cmpopts.IgnoreFields(zpav1alpha1.Drift{}, "DetectedTime"),
+}
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		mock func(mocks)
+		cr   *v1alpha1.SegmentGroup
+	}
+	type want struct {
+		cr     *v1alpha1.SegmentGroup
+		result managed.ExternalObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoExternalName": {
+			reason: "A SegmentGroup without external name does not exist yet.",
+			args: args{
+				cr: segmentGroup(withExternalName("")),
+			},
+			want: want{
+				cr:     segmentGroup(withExternalName("")),
+				result: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"NotFound": {
+			reason: "A SegmentGroup that is missing in ZPA does not exist.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				cr:     segmentGroup(),
+				result: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"DescribeFailed": {
+			reason: "Errors reading the SegmentGroup should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				cr:  segmentGroup(),
+				err: errors.Wrap(errBoom, errDescribeFailed),
+			},
+		},
+		"UpToDate": {
+			reason: "A SegmentGroup that matches ZPA is up to date.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: payload()}, nil)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				cr:     segmentGroup(withObservation(observation()), withConditions(xpv1.Available())),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"LateInitialize": {
+			reason: "Parameters that are only set in ZPA should be late initialized.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: payload()}, nil)
+				},
+				cr: segmentGroup(withSpec(func(p *v1alpha1.SegmentGroupParameters) {
+					p.ConfigSpace = ""
+					p.TCPKeepAliveEnabled = ""
+				})),
+			},
+			want: want{
+				cr:     segmentGroup(withObservation(observation()), withConditions(xpv1.Available())),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
+		"Drifted": {
+			reason: "A SegmentGroup whose description was changed in ZPA is not up to date.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: payload(func(p *models.SegmentGroup) {
+						p.Description = "changed"
+					})}, nil)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				cr: segmentGroup(
+					withObservation(observation(func(o *v1alpha1.Observation) {
+						o.Description = "changed"
+						o.LastDrift = &zpav1alpha1.Drift{
+							ModifiedBy:   "72057594037928115",
+							ModifiedTime: "1650000000",
+							Fields:       []zpav1alpha1.FieldDrift{{Path: "description", Desired: "managed by crossplane", Observed: "changed"}},
+						}
+					})),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"IgnoredDrift": {
+			reason: "Drift of parameters listed in ignoreDrift should not make a SegmentGroup outdated.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: payload(func(p *models.SegmentGroup) {
+						p.Description = "changed"
+					})}, nil)
+				},
+				cr: segmentGroup(withSpec(func(p *v1alpha1.SegmentGroupParameters) {
+					p.IgnoreDrift = []string{"spec.forProvider.description"}
+				})),
+			},
+			want: want{
+				cr: segmentGroup(
+					withSpec(func(p *v1alpha1.SegmentGroupParameters) {
+						p.IgnoreDrift = []string{"spec.forProvider.description"}
+					}),
+					withObservation(observation(func(o *v1alpha1.Observation) { o.Description = "changed" })),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, nil, tc.args.mock)
+			got, err := e.Observe(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		mock func(mocks)
+		cr   *v1alpha1.SegmentGroup
+	}
+	type want struct {
+		cr     *v1alpha1.SegmentGroup
+		result managed.ExternalCreation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "The ID of the created SegmentGroup should become its external name.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().AddSegmentGroupUsingPOST1(gomock.Any()).DoAndReturn(
+						func(params *segment_group_controller.AddSegmentGroupUsingPOST1Params, _ ...segment_group_controller.ClientOption) (*segment_group_controller.AddSegmentGroupUsingPOST1Created, error) {
+							if diff := cmp.Diff(customerID, params.CustomerID); diff != "" {
+								t.Errorf("AddSegmentGroupUsingPOST1(...): -want customerID, +got:\n%s", diff)
+							}
+							return &segment_group_controller.AddSegmentGroupUsingPOST1Created{Payload: payload()}, nil
+						})
+				},
+				cr: segmentGroup(withExternalName("")),
+			},
+			want: want{
+				cr:     segmentGroup(),
+				result: managed.ExternalCreation{ExternalNameAssigned: true},
+			},
+		},
+		"CreateFailed": {
+			reason: "Errors creating the SegmentGroup should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().AddSegmentGroupUsingPOST1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: segmentGroup(withExternalName("")),
+			},
+			want: want{
+				cr:  segmentGroup(withExternalName("")),
+				err: errors.Wrap(errBoom, errCreateFailed),
+			},
+		},
+		"NameConflict": {
+			reason: "A SegmentGroup whose name is taken should report the conflict.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().AddSegmentGroupUsingPOST1(gomock.Any()).Return(nil, errConflict)
+					m.sg.EXPECT().GetAllSegmentGroupsUsingGET1(gomock.Any()).Return(&segment_group_controller.GetAllSegmentGroupsUsingGET1OK{
+						Payload: &models.PageListOfSegmentGroup{List: []*models.SegmentGroup{payload()}, TotalPages: 1},
+					}, nil)
+				},
+				cr: segmentGroup(withExternalName("")),
+			},
+			want: want{
+				cr:  segmentGroup(withExternalName("")),
+				err: errors.Wrap(&zpaclient.NameConflictError{Kind: "SegmentGroup", Name: "example", IDs: []string{id}}, errCreateFailed),
+			},
+		},
+		"NameConflictAdopt": {
+			reason: "A SegmentGroup whose name is taken should adopt the existing object if its policy says so.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().AddSegmentGroupUsingPOST1(gomock.Any()).Return(nil, errConflict)
+					m.sg.EXPECT().GetAllSegmentGroupsUsingGET1(gomock.Any()).Return(&segment_group_controller.GetAllSegmentGroupsUsingGET1OK{
+						Payload: &models.PageListOfSegmentGroup{List: []*models.SegmentGroup{payload()}, TotalPages: 1},
+					}, nil)
+				},
+				cr: segmentGroup(withExternalName(""), withSpec(func(p *v1alpha1.SegmentGroupParameters) {
+					p.NameConflictPolicy = zpaclient.NameConflictPolicyAdopt
+				})),
+			},
+			want: want{
+				cr: segmentGroup(withSpec(func(p *v1alpha1.SegmentGroupParameters) {
+					p.NameConflictPolicy = zpaclient.NameConflictPolicyAdopt
+				})),
+				result: managed.ExternalCreation{ExternalNameAssigned: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, nil, tc.args.mock)
+			got, err := e.Create(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		mock func(mocks)
+		cr   *v1alpha1.SegmentGroup
+	}
+	type want struct {
+		err error
+	}
+
+	live := payload(func(p *models.SegmentGroup) {
+		p.Description = "changed"
+		p.Applications = []*models.Application{{ID: "72058000000000002", Name: zpaclient.String("app")}}
+	})
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "Update should send the desired parameters merged with the fields it does not model.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: live}, nil)
+					m.sg.EXPECT().UpdateSegmentGroupUsingPUT1(gomock.Any()).DoAndReturn(
+						func(params *segment_group_controller.UpdateSegmentGroupUsingPUT1Params, _ ...segment_group_controller.ClientOption) (*segment_group_controller.UpdateSegmentGroupUsingPUT1Created, *segment_group_controller.UpdateSegmentGroupUsingPUT1NoContent, error) {
+							want := payload(func(p *models.SegmentGroup) { p.Applications = live.Applications })
+							if diff := cmp.Diff(want, params.SegmentGroup); diff != "" {
+								t.Errorf("UpdateSegmentGroupUsingPUT1(...): -want payload, +got:\n%s", diff)
+							}
+							return nil, &segment_group_controller.UpdateSegmentGroupUsingPUT1NoContent{}, nil
+						})
+				},
+				cr: segmentGroup(),
+			},
+		},
+		"IgnoredDrift": {
+			reason: "Update should keep the value in ZPA of parameters listed in ignoreDrift.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: live}, nil)
+					m.sg.EXPECT().UpdateSegmentGroupUsingPUT1(gomock.Any()).DoAndReturn(
+						func(params *segment_group_controller.UpdateSegmentGroupUsingPUT1Params, _ ...segment_group_controller.ClientOption) (*segment_group_controller.UpdateSegmentGroupUsingPUT1Created, *segment_group_controller.UpdateSegmentGroupUsingPUT1NoContent, error) {
+							if diff := cmp.Diff("changed", params.SegmentGroup.Description); diff != "" {
+								t.Errorf("UpdateSegmentGroupUsingPUT1(...): -want description, +got:\n%s", diff)
+							}
+							return nil, &segment_group_controller.UpdateSegmentGroupUsingPUT1NoContent{}, nil
+						})
+				},
+				cr: segmentGroup(withSpec(func(p *v1alpha1.SegmentGroupParameters) {
+					p.IgnoreDrift = []string{"description"}
+				})),
+			},
+		},
+		"DescribeFailed": {
+			reason: "Errors reading the SegmentGroup before updating it should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errDescribeFailed), errUpdateFailed),
+			},
+		},
+		"UpdateFailed": {
+			reason: "Errors updating the SegmentGroup should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: live}, nil)
+					m.sg.EXPECT().UpdateSegmentGroupUsingPUT1(gomock.Any()).Return(nil, nil, errBoom)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, nil, tc.args.mock)
+			_, err := e.Update(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		kube client.Client
+		mock func(mocks)
+		cr   *v1alpha1.SegmentGroup
+	}
+	type want struct {
+		cr  *v1alpha1.SegmentGroup
+		err error
+	}
+
+	noApplicationSegments := &test.MockClient{MockList: test.NewMockListFn(nil)}
+	rule := &models.PolicyRule{
+		ID:   "72058000000000070",
+		Name: zpaclient.String("allow"),
+		Conditions: []*models.ConditionSet{{Operands: []*models.Operand{
+			{ObjectType: zpaclient.ObjectTypeSegmentGroup, RHS: id},
+		}}},
+	}
+	listRules := func(rules ...*models.PolicyRule) func(m mocks) {
+		return func(m mocks) {
+			m.policy.EXPECT().GetPolicyRulesByPageUsingGET1(gomock.Any()).DoAndReturn(
+				func(params *policy_set_controller.GetPolicyRulesByPageUsingGET1Params, _ ...policy_set_controller.ClientOption) (*policy_set_controller.GetPolicyRulesByPageUsingGET1OK, error) {
+					list := []*models.PolicyRule{}
+					if params.PolicyType == "ACCESS_POLICY" {
+						list = rules
+					}
+					return &policy_set_controller.GetPolicyRulesByPageUsingGET1OK{
+						Payload: &models.PageListOfPolicyRule{List: list, TotalPages: 1},
+					}, nil
+				}).AnyTimes()
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "A SegmentGroup without dependents should be deleted.",
+			args: args{
+				kube: noApplicationSegments,
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: payload()}, nil)
+					listRules()(m)
+					m.sg.EXPECT().DeleteSegmentGroupUsingDELETE1(gomock.Any()).Return(&segment_group_controller.DeleteSegmentGroupUsingDELETE1NoContent{}, nil)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				cr: segmentGroup(),
+			},
+		},
+		"NotFound": {
+			reason: "A SegmentGroup that is already gone should be deleted successfully.",
+			args: args{
+				kube: noApplicationSegments,
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(nil, errNotFound)
+					m.sg.EXPECT().DeleteSegmentGroupUsingDELETE1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				cr: segmentGroup(),
+			},
+		},
+		"HasDependents": {
+			reason: "A SegmentGroup that is used by ApplicationSegments should not be deleted.",
+			args: args{
+				kube: &test.MockClient{MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					as := appv1alpha1.ApplicationSegment{}
+					as.SetName("app")
+					as.Spec.ForProvider.SegmentGroupID = zpaclient.String(id)
+					meta.SetExternalName(&as, "72058000000000002")
+					o.(*appv1alpha1.ApplicationSegmentList).Items = []appv1alpha1.ApplicationSegment{as}
+					return nil
+				})},
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: payload(func(p *models.SegmentGroup) {
+						p.Applications = []*models.Application{
+							{ID: "72058000000000002", Name: zpaclient.String("app")},
+							{ID: "72058000000000003", Name: zpaclient.String("portal")},
+						}
+					})}, nil)
+					listRules()(m)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				cr:  segmentGroup(withConditions(zpaclient.HasDependents([]string{"ApplicationSegment app", `ZPA ApplicationSegment "portal" (72058000000000003)`}))),
+				err: errors.Wrap(errors.New(`still used by ApplicationSegment app, ZPA ApplicationSegment "portal" (72058000000000003)`), errDeleteFailed),
+			},
+		},
+		"ReferencedByPolicyRules": {
+			reason: "A SegmentGroup that policy rules reference should not be deleted.",
+			args: args{
+				kube: noApplicationSegments,
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: payload()}, nil)
+					listRules(rule)(m)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				cr:  segmentGroup(withConditions(zpaclient.HasDependents([]string{`policy rule "allow" (72058000000000070)`}))),
+				err: errors.Wrap(errors.New(`still used by policy rule "allow" (72058000000000070)`), errDeleteFailed),
+			},
+		},
+		"ListPolicyRulesFailed": {
+			reason: "Errors listing policy rules should be wrapped.",
+			args: args{
+				kube: noApplicationSegments,
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: payload()}, nil)
+					m.policy.EXPECT().GetPolicyRulesByPageUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				cr:  segmentGroup(),
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot list ACCESS_POLICY rules"), errDeleteFailed),
+			},
+		},
+		"ListFailed": {
+			reason: "Errors listing ApplicationSegments should be wrapped.",
+			args: args{
+				kube: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				cr:   segmentGroup(),
+			},
+			want: want{
+				cr:  segmentGroup(),
+				err: errors.Wrap(errors.Wrap(errBoom, errListApplicationSegments), errDeleteFailed),
+			},
+		},
+		"DeleteFailed": {
+			reason: "Errors deleting the SegmentGroup should be wrapped.",
+			args: args{
+				kube: noApplicationSegments,
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: payload()}, nil)
+					listRules()(m)
+					m.sg.EXPECT().DeleteSegmentGroupUsingDELETE1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: segmentGroup(),
+			},
+			want: want{
+				cr:  segmentGroup(),
+				err: errors.Wrap(errBoom, errDeleteFailed),
+			},
+		},
+		"NoLongerUsed": {
+			reason: "A SegmentGroup whose deletion failed after its dependents are gone should not report the stale dependents.",
+			args: args{
+				kube: noApplicationSegments,
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetSegmentGroupUsingGET1(gomock.Any()).Return(&segment_group_controller.GetSegmentGroupUsingGET1OK{Payload: payload()}, nil)
+					listRules()(m)
+					m.sg.EXPECT().DeleteSegmentGroupUsingDELETE1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: segmentGroup(withConditions(zpaclient.HasDependents([]string{"ApplicationSegment app"}))),
+			},
+			want: want{
+				cr:  segmentGroup(withConditions(zpaclient.DeletionNotBlocked())),
+				err: errors.Wrap(errBoom, errDeleteFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, tc.args.kube, tc.args.mock)
+			err := e.Delete(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,580 @@
+/*
+Copyright 2022 The Crossplane Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	zpa "github.com/haarchri/zpa-go-client/pkg/client"
+	"github.com/haarchri/zpa-go-client/pkg/client/app_server_controller"
+	"github.com/haarchri/zpa-go-client/pkg/models"
+
+	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/server/v1alpha1"
+	zpav1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	mockserver "github.com/crossplane-contrib/provider-zpa/pkg/client/mock/app_server_controller"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+)
+
+const (
+	customerID  = "216196257331281920"
+	id          = "72058000000000001"
+	serverGroup = "72058000000000010"
+
+	errCreateFailed   = "cannot create Server"
+	errUpdateFailed   = "cannot update Server"
+	errDescribeFailed = "cannot describe Server"
+	errDeleteFailed   = "cannot delete Server"
+)
+
+var (
+	errBoom     = errors.New("boom")
+	errNotFound = &zpaclient.APIError{StatusCode: http.StatusNotFound, Class: zpaclient.ErrorClassNotFound}
+)
+
+type serverModifier func(*v1alpha1.Server)
+
+func withExternalName(n string) serverModifier {
+	return func(cr *v1alpha1.Server) { meta.SetExternalName(cr, n) }
+}
+
+func withSpec(fn func(*v1alpha1.ServerParameters)) serverModifier {
+	return func(cr *v1alpha1.Server) { fn(&cr.Spec.ForProvider) }
+}
+
+func withObservation(o v1alpha1.Observation) serverModifier {
+	return func(cr *v1alpha1.Server) { cr.Status.AtProvider = o }
+}
+
+func withConditions(c ...xpv1.Condition) serverModifier {
+	return func(cr *v1alpha1.Server) { cr.Status.SetConditions(c...) }
+}
+
+func server(m ...serverModifier) *v1alpha1.Server {
+	cr := &v1alpha1.Server{
+		Spec: v1alpha1.ServerSpec{
+			ForProvider: v1alpha1.ServerParameters{
+				Name:         zpaclient.String("example"),
+				Address:      "10.0.0.1",
+				ConfigSpace:  "DEFAULT",
+				Description:  "managed by crossplane",
+				Enabled:      zpaclient.Bool(true),
+				ServerGroups: []string{serverGroup},
+			},
+		},
+	}
+	meta.SetExternalName(cr, id)
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+func payload(m ...func(*models.ApplicationServer)) *models.ApplicationServer {
+	p := &models.ApplicationServer{
+		ID:                id,
+		Name:              zpaclient.String("example"),
+		Address:           "10.0.0.1",
+		ConfigSpace:       "DEFAULT",
+		Description:       "managed by crossplane",
+		Enabled:           true,
+		AppServerGroupIds: []string{serverGroup},
+		ModifiedBy:        "72057594037928115",
+		ModifiedTime:      "1650000000",
+	}
+	for _, f := range m {
+		f(p)
+	}
+	return p
+}
+
+func observation(m ...func(*v1alpha1.Observation)) v1alpha1.Observation {
+	o := v1alpha1.Observation{
+		ID:           id,
+		Name:         "example",
+		Address:      "10.0.0.1",
+		ConfigSpace:  "DEFAULT",
+		Description:  "managed by crossplane",
+		Enabled:      true,
+		ServerGroups: []string{serverGroup},
+		ModifiedBy:   "72057594037928115",
+		ModifiedTime: "1650000000",
+	}
+	for _, f := range m {
+		f(&o)
+	}
+	return o
+}
+
+func newExternal(t *testing.T, mock func(*mockserver.MockClientService)) *adapter.External[*v1alpha1.Server, *models.ApplicationServer] {
+	ctrl := gomock.NewController(t)
+	m := mockserver.NewMockClientService(ctrl)
+	if mock != nil {
+		mock(m)
+	}
+	c := &adapter.Client{
+		ZscalerPrivateAccessAPIPortal: &zpa.ZscalerPrivateAccessAPIPortal{AppServerController: m},
+		CustomerID:                    customerID,
+	}
+	return adapter.NewExternal[*v1alpha1.Server, *models.ApplicationServer](c, kind{}, event.NewNopRecorder())
+}
+
+var equateStatus = []cmp.Option{
+	test.EquateConditions(),
+	cmpopts.IgnoreFields(zpav1alpha1.Drift{}, "DetectedTime"),
+}
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		mock func(*mockserver.MockClientService)
+		cr   *v1alpha1.Server
+	}
+	type want struct {
+		cr     *v1alpha1.Server
+		result managed.ExternalObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoExternalName": {
+			reason: "A Server without external name does not exist yet.",
+			args: args{
+				cr: server(withExternalName("")),
+			},
+			want: want{
+				cr:     server(withExternalName("")),
+				result: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"ImportByName": {
+			reason: "A Server without external name should adopt the only Server with its name if importByName is set.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAllAppServersUsingGET1(gomock.Any()).Return(&app_server_controller.GetAllAppServersUsingGET1OK{
+						Payload: &models.PageListOfApplicationServer{List: []*models.ApplicationServer{payload()}, TotalPages: 1},
+					}, nil)
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(&app_server_controller.GetAppServerUsingGET1OK{Payload: payload()}, nil)
+				},
+				cr: server(withExternalName(""), withSpec(func(p *v1alpha1.ServerParameters) { p.ImportByName = zpaclient.Bool(true) })),
+			},
+			want: want{
+				cr: server(
+					withSpec(func(p *v1alpha1.ServerParameters) { p.ImportByName = zpaclient.Bool(true) }),
+					withObservation(observation()),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
+		"NotFound": {
+			reason: "A Server that is missing in ZPA does not exist.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: server(),
+			},
+			want: want{
+				cr:     server(),
+				result: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"DescribeFailed": {
+			reason: "Errors reading the Server should be wrapped.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: server(),
+			},
+			want: want{
+				cr:  server(),
+				err: errors.Wrap(errBoom, errDescribeFailed),
+			},
+		},
+		"UpToDate": {
+			reason: "A Server that matches ZPA is up to date.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(&app_server_controller.GetAppServerUsingGET1OK{Payload: payload()}, nil)
+				},
+				cr: server(),
+			},
+			want: want{
+				cr:     server(withObservation(observation()), withConditions(xpv1.Available())),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"LateInitialize": {
+			reason: "The config space should be late initialized from ZPA.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(&app_server_controller.GetAppServerUsingGET1OK{Payload: payload()}, nil)
+				},
+				cr: server(withSpec(func(p *v1alpha1.ServerParameters) { p.ConfigSpace = "" })),
+			},
+			want: want{
+				cr:     server(withObservation(observation()), withConditions(xpv1.Available())),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
+		"Drifted": {
+			reason: "A Server that was removed from its server group in ZPA is not up to date.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(&app_server_controller.GetAppServerUsingGET1OK{Payload: payload(func(p *models.ApplicationServer) {
+						p.AppServerGroupIds = nil
+					})}, nil)
+				},
+				cr: server(),
+			},
+			want: want{
+				cr: server(
+					withObservation(observation(func(o *v1alpha1.Observation) {
+						o.ServerGroups = nil
+						o.LastDrift = &zpav1alpha1.Drift{
+							ModifiedBy:   "72057594037928115",
+							ModifiedTime: "1650000000",
+							Fields:       []zpav1alpha1.FieldDrift{{Path: "serverGroups", Desired: "[" + serverGroup + "]", Observed: "[]"}},
+						}
+					})),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, tc.args.mock)
+			got, err := e.Observe(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		mock func(*mockserver.MockClientService)
+		cr   *v1alpha1.Server
+	}
+	type want struct {
+		cr     *v1alpha1.Server
+		result managed.ExternalCreation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "The ID of the created Server should become its external name.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().AddAppServerUsingPOST1(gomock.Any()).DoAndReturn(
+						func(params *app_server_controller.AddAppServerUsingPOST1Params, _ ...app_server_controller.ClientOption) (*app_server_controller.AddAppServerUsingPOST1Created, error) {
+							want := payload(func(p *models.ApplicationServer) { p.ID, p.ModifiedBy, p.ModifiedTime = "", "", "" })
+							if diff := cmp.Diff(want, params.Server); diff != "" {
+								t.Errorf("AddAppServerUsingPOST1(...): -want payload, +got:\n%s", diff)
+							}
+							return &app_server_controller.AddAppServerUsingPOST1Created{Payload: payload()}, nil
+						})
+				},
+				cr: server(withExternalName("")),
+			},
+			want: want{
+				cr:     server(),
+				result: managed.ExternalCreation{ExternalNameAssigned: true},
+			},
+		},
+		"CreateFailed": {
+			reason: "Errors creating the Server should be wrapped.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().AddAppServerUsingPOST1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: server(withExternalName("")),
+			},
+			want: want{
+				cr:  server(withExternalName("")),
+				err: errors.Wrap(errBoom, errCreateFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, tc.args.mock)
+			got, err := e.Create(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		mock func(*mockserver.MockClientService)
+		cr   *v1alpha1.Server
+	}
+	type want struct {
+		err error
+	}
+
+	live := payload(func(p *models.ApplicationServer) {
+		p.Address = "10.0.0.2"
+		p.AppServerGroupIds = []string{serverGroup, "72058000000000011"}
+	})
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "Update should send the desired parameters.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(&app_server_controller.GetAppServerUsingGET1OK{Payload: live}, nil)
+					m.EXPECT().UpdateAppServerUsingPUT1(gomock.Any()).DoAndReturn(
+						func(params *app_server_controller.UpdateAppServerUsingPUT1Params, _ ...app_server_controller.ClientOption) (*app_server_controller.UpdateAppServerUsingPUT1Created, *app_server_controller.UpdateAppServerUsingPUT1NoContent, error) {
+							if diff := cmp.Diff(payload(), params.Server); diff != "" {
+								t.Errorf("UpdateAppServerUsingPUT1(...): -want payload, +got:\n%s", diff)
+							}
+							return nil, &app_server_controller.UpdateAppServerUsingPUT1NoContent{}, nil
+						})
+				},
+				cr: server(),
+			},
+		},
+		"IgnoredDrift": {
+			reason: "Update should keep the server groups in ZPA if they are listed in ignoreDrift.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(&app_server_controller.GetAppServerUsingGET1OK{Payload: live}, nil)
+					m.EXPECT().UpdateAppServerUsingPUT1(gomock.Any()).DoAndReturn(
+						func(params *app_server_controller.UpdateAppServerUsingPUT1Params, _ ...app_server_controller.ClientOption) (*app_server_controller.UpdateAppServerUsingPUT1Created, *app_server_controller.UpdateAppServerUsingPUT1NoContent, error) {
+							want := payload(func(p *models.ApplicationServer) { p.AppServerGroupIds = live.AppServerGroupIds })
+							if diff := cmp.Diff(want, params.Server); diff != "" {
+								t.Errorf("UpdateAppServerUsingPUT1(...): -want payload, +got:\n%s", diff)
+							}
+							return nil, &app_server_controller.UpdateAppServerUsingPUT1NoContent{}, nil
+						})
+				},
+				cr: server(withSpec(func(p *v1alpha1.ServerParameters) { p.IgnoreDrift = []string{"serverGroups"} })),
+			},
+		},
+		"DescribeFailed": {
+			reason: "Errors reading the Server before updating it should be wrapped.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: server(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errDescribeFailed), errUpdateFailed),
+			},
+		},
+		"UpdateFailed": {
+			reason: "Errors updating the Server should be wrapped.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(&app_server_controller.GetAppServerUsingGET1OK{Payload: live}, nil)
+					m.EXPECT().UpdateAppServerUsingPUT1(gomock.Any()).Return(nil, nil, errBoom)
+				},
+				cr: server(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, tc.args.mock)
+			_, err := e.Update(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		mock func(*mockserver.MockClientService)
+		cr   *v1alpha1.Server
+	}
+	type want struct {
+		err error
+	}
+
+	live := payload(func(p *models.ApplicationServer) {
+		p.ModifiedBy = "72057594037928116"
+		p.Description = "changed in ZPA"
+	})
+	getLive := func(m *mockserver.MockClientService) {
+		m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(&app_server_controller.GetAppServerUsingGET1OK{Payload: live}, nil)
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "A Server should be removed from its server groups, keeping the fields the provider does not model, before it is deleted.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					gomock.InOrder(
+						m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(&app_server_controller.GetAppServerUsingGET1OK{Payload: live}, nil),
+						m.EXPECT().UpdateAppServerUsingPUT1(gomock.Any()).DoAndReturn(
+							func(params *app_server_controller.UpdateAppServerUsingPUT1Params, _ ...app_server_controller.ClientOption) (*app_server_controller.UpdateAppServerUsingPUT1Created, *app_server_controller.UpdateAppServerUsingPUT1NoContent, error) {
+								want := payload(func(p *models.ApplicationServer) {
+									p.ModifiedBy = live.ModifiedBy
+									p.AppServerGroupIds = []string{}
+								})
+								if diff := cmp.Diff(want, params.Server); diff != "" {
+									t.Errorf("UpdateAppServerUsingPUT1(...): -want payload, +got:\n%s", diff)
+								}
+								return nil, &app_server_controller.UpdateAppServerUsingPUT1NoContent{}, nil
+							}),
+						m.EXPECT().DeleteAppServerUsingDELETE1(gomock.Any()).Return(&app_server_controller.DeleteAppServerUsingDELETE1NoContent{}, nil),
+					)
+				},
+				cr: server(),
+			},
+		},
+		"IgnoredServerGroups": {
+			reason: "A Server should be removed from its server groups even if they are listed in ignoreDrift.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(&app_server_controller.GetAppServerUsingGET1OK{Payload: live}, nil)
+					m.EXPECT().UpdateAppServerUsingPUT1(gomock.Any()).DoAndReturn(
+						func(params *app_server_controller.UpdateAppServerUsingPUT1Params, _ ...app_server_controller.ClientOption) (*app_server_controller.UpdateAppServerUsingPUT1Created, *app_server_controller.UpdateAppServerUsingPUT1NoContent, error) {
+							if len(params.Server.AppServerGroupIds) != 0 {
+								t.Errorf("UpdateAppServerUsingPUT1(...): want no server groups, got %v", params.Server.AppServerGroupIds)
+							}
+							return nil, &app_server_controller.UpdateAppServerUsingPUT1NoContent{}, nil
+						})
+					m.EXPECT().DeleteAppServerUsingDELETE1(gomock.Any()).Return(&app_server_controller.DeleteAppServerUsingDELETE1NoContent{}, nil)
+				},
+				cr: server(withSpec(func(p *v1alpha1.ServerParameters) { p.IgnoreDrift = []string{"serverGroups"} })),
+			},
+		},
+		"NoServerGroups": {
+			reason: "A Server without server groups should be deleted right away.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().DeleteAppServerUsingDELETE1(gomock.Any()).Return(&app_server_controller.DeleteAppServerUsingDELETE1NoContent{}, nil)
+				},
+				cr: server(withSpec(func(p *v1alpha1.ServerParameters) { p.ServerGroups = nil })),
+			},
+		},
+		"NotFound": {
+			reason: "A Server that is already gone should be deleted successfully.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					m.EXPECT().GetAppServerUsingGET1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: server(),
+			},
+		},
+		"DeletedMeanwhile": {
+			reason: "A Server that disappears after it was removed from its server groups should be deleted successfully.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					getLive(m)
+					m.EXPECT().UpdateAppServerUsingPUT1(gomock.Any()).Return(nil, &app_server_controller.UpdateAppServerUsingPUT1NoContent{}, nil)
+					m.EXPECT().DeleteAppServerUsingDELETE1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: server(),
+			},
+		},
+		"RemoveFromServerGroupsFailed": {
+			reason: "Errors removing the Server from its server groups should be wrapped.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					getLive(m)
+					m.EXPECT().UpdateAppServerUsingPUT1(gomock.Any()).Return(nil, nil, errBoom)
+				},
+				cr: server(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errRemoveFromServerGroups), errDeleteFailed),
+			},
+		},
+		"DeleteFailed": {
+			reason: "Errors deleting the Server should be wrapped.",
+			args: args{
+				mock: func(m *mockserver.MockClientService) {
+					getLive(m)
+					m.EXPECT().UpdateAppServerUsingPUT1(gomock.Any()).Return(nil, &app_server_controller.UpdateAppServerUsingPUT1NoContent{}, nil)
+					m.EXPECT().DeleteAppServerUsingDELETE1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: server(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, tc.args.mock)
+			err := e.Delete(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
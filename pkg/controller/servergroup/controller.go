@@ -16,307 +16,348 @@ package servergroup
 import (
 	"context"
 
-	"github.com/go-openapi/runtime"
-	"github.com/go-openapi/strfmt"
-	"github.com/google/go-cmp/cmp"
-
 	"github.com/pkg/errors"
+	"k8s.io/utils/strings/slices"
 	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	v1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
-	"github.com/crossplane/crossplane-runtime/pkg/connection"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
-	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
-	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
-	zpa "github.com/haarchri/zpa-go-client/pkg/client"
 	"github.com/haarchri/zpa-go-client/pkg/client/connector_group_controller"
 	"github.com/haarchri/zpa-go-client/pkg/client/server_group_controller"
 	"github.com/haarchri/zpa-go-client/pkg/models"
 
+	appv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
 	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/servergroup/v1alpha1"
-	store "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpav1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
 	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
-	"github.com/crossplane-contrib/provider-zpa/pkg/features"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
 )
 
 const (
-	errNotServer                    = "managed resource is not an Server custom resource"
-	errCreateConnectorGroupNotFound = "cannot find needed ConnectorGroup create failed"
-	errUpdateConnectorGroupNotFound = "cannot find needed ConnectorGroup update failed"
-	errCreateFailed                 = "cannot create Server"
-	errUpdateFailed                 = "connot update Server"
-	errDescribeFailed               = "cannot describe Server"
-	errDeleteFailed                 = "cannot delete Server"
+	errConnectorGroupNotFound  = "cannot get AppConnectorGroup"
+	errDescribeFailed          = "cannot describe ServerGroup"
+	errListApplicationSegments = "cannot list ApplicationSegments"
 )
 
-// SetupServerGroup adds a controller that reconciles Servers.
+// SetupServerGroup adds a controller that reconciles ServerGroups.
 func SetupServerGroup(mgr ctrl.Manager, o controller.Options) error {
-	name := managed.ControllerName(v1alpha1.ServerGroupKind)
-
-	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
-	if o.Features.Enabled(features.EnableAlphaExternalSecretStores) {
-		cps = append(cps, connection.NewDetailsManager(mgr.GetClient(), store.StoreConfigGroupVersionKind))
-	}
-
-	return ctrl.NewControllerManagedBy(mgr).
-		Named(name).
-		WithOptions(o.ForControllerRuntime()).
-		For(&v1alpha1.ServerGroup{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(v1alpha1.ServerGroupGroupVersionKind),
-			managed.WithExternalConnecter(&connector{kube: mgr.GetClient(), newClientFn: zpa.New}),
-			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient())),
-			managed.WithConnectionPublishers(),
-			managed.WithReferenceResolver(managed.NewAPISimpleReferenceResolver(mgr.GetClient())),
-			managed.WithLogger(o.Logger.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithConnectionPublishers(cps...)))
+	return adapter.Setup[*v1alpha1.ServerGroup, *models.ServerGroupDTO](mgr, o, kind{}, v1alpha1.ServerGroupGroupVersionKind, &v1alpha1.ServerGroup{})
 }
 
-type connector struct {
-	kube        client.Client
-	newClientFn func(transport runtime.ClientTransport, formats strfmt.Registry) *zpa.ZscalerPrivateAccessAPIPortal
+// NewKind returns the kind that reconciles ServerGroups.
+func NewKind() adapter.Kind[*v1alpha1.ServerGroup, *models.ServerGroupDTO] {
+	return kind{}
 }
 
-type external struct {
-	client *zpa.ZscalerPrivateAccessAPIPortal
-	kube   client.Client
-}
+// kind reconciles ServerGroups with ZPA server groups.
+type kind struct{}
 
-func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-	_, ok := mg.(*v1alpha1.ServerGroup)
-	if !ok {
-		return nil, errors.New(errNotServer)
-	}
+func (kind) Kind() string { return v1alpha1.ServerGroupKind }
 
-	cfg, err := zpaclient.GetConfig(ctx, c.kube, mg)
-	if err != nil {
-		return nil, err
+func (kind) Parameters(cr *v1alpha1.ServerGroup) adapter.Parameters {
+	p := cr.Spec.ForProvider
+	return adapter.Parameters{
+		Name:               p.Name,
+		ImportByName:       zpaclient.BoolValue(p.ImportByName),
+		NameConflictPolicy: p.NameConflictPolicy,
+		IgnoreDrift:        p.IgnoreDrift,
+		ObserveOnly:        zpaclient.BoolValue(p.ObserveOnly),
 	}
+}
 
-	client := c.newClientFn(cfg, strfmt.Default)
-	return &external{client, c.kube}, nil
+// Fields are the JSON names in the ZPA API of the fields Update sets from the
+// parameters of a ServerGroup.
+func (kind) Fields() adapter.Fields {
+	return adapter.Fields{Modelled: []string{
+		"appConnectorGroups",
+		"configSpace",
+		"description",
+		"dynamicDiscovery",
+		"enabled",
+		"ipAnchored",
+		"name",
+	}}
 }
 
-func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
-	cr, ok := mg.(*v1alpha1.ServerGroup)
-	if !ok {
-		return managed.ExternalObservation{}, errors.New(errNotServer)
+func (kind) ToAPI(ctx context.Context, c *adapter.Client, cr *v1alpha1.ServerGroup) (*models.ServerGroupDTO, error) {
+	obj := &models.ServerGroupDTO{
+		Name:             cr.Spec.ForProvider.Name,
+		ConfigSpace:      cr.Spec.ForProvider.ConfigSpace,
+		Description:      cr.Spec.ForProvider.Description,
+		Enabled:          zpaclient.BoolValue(cr.Spec.ForProvider.Enabled),
+		DynamicDiscovery: cr.Spec.ForProvider.DynamicDiscovery,
+		IPAnchored:       zpaclient.BoolValue(cr.Spec.ForProvider.IPAnchored),
 	}
 
-	id := meta.GetExternalName(cr)
-	if id == "" {
-		return managed.ExternalObservation{
-			ResourceExists:   false,
-			ResourceUpToDate: false,
-		}, nil
-	}
+	for _, id := range cr.Spec.ForProvider.AppConnectorGroups {
+		// we need required AppConnectorGroupName
+		req := &connector_group_controller.GetAppConnectorGroupUsingGET1Params{
+			Context:             ctx,
+			CustomerID:          c.CustomerID,
+			AppConnectorGroupID: id,
+		}
+		resp, err := c.ConnectorGroupController.GetAppConnectorGroupUsingGET1(req)
+		if err != nil {
+			return nil, errors.Wrap(err, errConnectorGroupNotFound)
+		}
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
-	req := &server_group_controller.GetServerGroupUsingGET1Params{
-		Context:    ctx,
-		GroupID:    id,
-		CustomerID: customerID,
-	}
-	resp, reqErr := e.client.ServerGroupController.GetServerGroupUsingGET1(req)
-	if reqErr != nil {
-		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(resource.Ignore(IsNotFound, reqErr), errDescribeFailed)
+		obj.AppConnectorGroups = append(obj.AppConnectorGroups, &models.AppConnectorGroup{
+			Name: resp.Payload.Name,
+			ID:   id,
+		})
 	}
 
-	cr.Status.AtProvider = generateObservation(resp)
+	return obj, nil
+}
 
-	currentSpec := cr.Spec.ForProvider.DeepCopy()
-	e.LateInitialize(cr, resp)
+func (kind) FromAPI(cr *v1alpha1.ServerGroup, obj *models.ServerGroupDTO) {
+	cr.Status.AtProvider = generateObservation(obj)
+}
 
-	cr.Status.SetConditions(v1.Available())
+func (kind) LateInitialize(cr *v1alpha1.ServerGroup, obj *models.ServerGroupDTO) {
+	if cr.Spec.ForProvider.ConfigSpace == "" && obj.ConfigSpace != "" {
+		cr.Spec.ForProvider.ConfigSpace = obj.ConfigSpace
+	}
 
-	return managed.ExternalObservation{
-		ResourceExists:          true,
-		ResourceUpToDate:        isUpToDate(&cr.Spec.ForProvider, resp),
-		ResourceLateInitialized: !cmp.Equal(&cr.Spec.ForProvider, currentSpec),
-	}, nil
+	if cr.Spec.ForProvider.IPAnchored == nil {
+		cr.Spec.ForProvider.IPAnchored = zpaclient.Bool(obj.IPAnchored)
+	}
 }
 
-func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
-	cr, ok := mg.(*v1alpha1.ServerGroup)
-	if !ok {
-		return managed.ExternalCreation{}, errors.New(errNotServer)
+func (kind) Compare(cr *v1alpha1.ServerGroup, obj *models.ServerGroupDTO) adapter.Comparison {
+	return adapter.Comparison{
+		Drifted:      driftedFields(&cr.Spec.ForProvider, obj),
+		Desired:      &cr.Spec.ForProvider,
+		Observed:     observedParameters(obj),
+		ModifiedBy:   obj.ModifiedBy,
+		ModifiedTime: obj.ModifiedTime,
 	}
+}
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
-	req := &server_group_controller.AddAppServerGroupUsingPOST1Params{
+func (kind) LastDrift(cr *v1alpha1.ServerGroup) *zpav1alpha1.Drift {
+	return cr.Status.AtProvider.LastDrift
+}
+
+func (kind) SetLastDrift(cr *v1alpha1.ServerGroup, d *zpav1alpha1.Drift) {
+	cr.Status.AtProvider.LastDrift = d
+}
+
+func (kind) Get(ctx context.Context, c *adapter.Client, id string) (*models.ServerGroupDTO, error) {
+	req := &server_group_controller.GetServerGroupUsingGET1Params{
 		Context:    ctx,
-		CustomerID: customerID,
-		Group: &models.ServerGroupDTO{
-			Name:             cr.Spec.ForProvider.Name,
-			ConfigSpace:      cr.Spec.ForProvider.ConfigSpace,
-			Description:      cr.Spec.ForProvider.Description,
-			Enabled:          zpaclient.BoolValue(cr.Spec.ForProvider.Enabled),
-			DynamicDiscovery: cr.Spec.ForProvider.DynamicDiscovery,
-			IPAnchored:       zpaclient.BoolValue(cr.Spec.ForProvider.IPAnchored),
-		},
+		GroupID:    id,
+		CustomerID: c.CustomerID,
 	}
+	resp, err := c.ServerGroupController.GetServerGroupUsingGET1(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Payload, nil
+}
 
-	for i := range cr.Spec.ForProvider.AppConnectorGroups {
-
-		// we need required AppConnectorGroupName
-		connectorreq := &connector_group_controller.GetAppConnectorGroupUsingGET1Params{
-			Context:             ctx,
-			CustomerID:          customerID,
-			AppConnectorGroupID: cr.Spec.ForProvider.AppConnectorGroups[i],
+func (kind) List(ctx context.Context, c *adapter.Client, name string) ([]zpaclient.NamedObject, error) {
+	objs := make([]zpaclient.NamedObject, 0)
+	for page := int32(1); ; page++ {
+		req := &server_group_controller.GetAllServerGroupsUsingGET1Params{
+			Context:    ctx,
+			CustomerID: c.CustomerID,
+			Page:       page,
+			Pagesize:   zpaclient.ListPageSize,
+			Search:     name,
 		}
-		connectorresp, err := e.client.ConnectorGroupController.GetAppConnectorGroupUsingGET1(connectorreq)
+		resp, err := c.ServerGroupController.GetAllServerGroupsUsingGET1(req)
 		if err != nil {
-			return managed.ExternalCreation{}, errors.Wrap(err, errCreateConnectorGroupNotFound)
+			return nil, err
 		}
 
-		req.Group.AppConnectorGroups = append(
-			req.Group.AppConnectorGroups,
-			&models.AppConnectorGroup{
-				Name: connectorresp.Payload.Name,
-				ID:   cr.Spec.ForProvider.AppConnectorGroups[i],
-			})
-	}
+		for _, obj := range resp.Payload.List {
+			objs = append(objs, zpaclient.NamedObject{ID: obj.ID, Name: obj.Name})
+		}
 
-	resp, err := e.client.ServerGroupController.AddAppServerGroupUsingPOST1(req)
-	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+		if page >= resp.Payload.TotalPages {
+			break
+		}
 	}
 
-	meta.SetExternalName(cr, resp.Payload.ID)
-	return managed.ExternalCreation{
-		ExternalNameAssigned: true,
-	}, nil
-
+	return objs, nil
 }
 
-func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	cr, ok := mg.(*v1alpha1.ServerGroup)
-	if !ok {
-		return managed.ExternalUpdate{}, errors.New(errNotServer)
+func (kind) Create(ctx context.Context, c *adapter.Client, obj *models.ServerGroupDTO) (string, error) {
+	req := &server_group_controller.AddAppServerGroupUsingPOST1Params{
+		Context:    ctx,
+		CustomerID: c.CustomerID,
+		Group:      obj,
+	}
+	resp, err := c.ServerGroupController.AddAppServerGroupUsingPOST1(req)
+	if err != nil {
+		return "", err
 	}
+	return resp.Payload.ID, nil
+}
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
+func (kind) Update(ctx context.Context, c *adapter.Client, id string, obj *models.ServerGroupDTO) error {
 	req := &server_group_controller.UpdateAppServerGroupUsingPUT1Params{
 		Context:    ctx,
-		CustomerID: customerID,
-		GroupID:    meta.GetExternalName(cr),
-		Group: &models.ServerGroupDTO{
-			Name:             cr.Spec.ForProvider.Name,
-			ConfigSpace:      cr.Spec.ForProvider.ConfigSpace,
-			Description:      cr.Spec.ForProvider.Description,
-			Enabled:          zpaclient.BoolValue(cr.Spec.ForProvider.Enabled),
-			DynamicDiscovery: cr.Spec.ForProvider.DynamicDiscovery,
-			IPAnchored:       zpaclient.BoolValue(cr.Spec.ForProvider.IPAnchored),
-		},
-	}
-
-	for i := range cr.Spec.ForProvider.AppConnectorGroups {
-		// we need required AppConnectorGroupName
-		connectorreq := &connector_group_controller.GetAppConnectorGroupUsingGET1Params{
-			Context:             ctx,
-			CustomerID:          customerID,
-			AppConnectorGroupID: cr.Spec.ForProvider.AppConnectorGroups[i],
-		}
-		connectorresp, err := e.client.ConnectorGroupController.GetAppConnectorGroupUsingGET1(connectorreq)
-		if err != nil {
-			return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateConnectorGroupNotFound)
-		}
-
-		req.Group.AppConnectorGroups = append(
-			req.Group.AppConnectorGroups,
-			&models.AppConnectorGroup{
-				Name: connectorresp.Payload.Name,
-				ID:   cr.Spec.ForProvider.AppConnectorGroups[i],
-			})
-	}
-
-	if _, _, err := e.client.ServerGroupController.UpdateAppServerGroupUsingPUT1(req); err != nil {
-		return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateFailed)
+		CustomerID: c.CustomerID,
+		GroupID:    id,
+		Group:      obj,
 	}
-
-	return managed.ExternalUpdate{}, nil
+	_, _, err := c.ServerGroupController.UpdateAppServerGroupUsingPUT1(req)
+	return err
 }
 
-func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
-	cr, ok := mg.(*v1alpha1.ServerGroup)
-	if !ok {
-		return errors.New(errNotServer)
+// Delete deletes the supplied ServerGroup unless ApplicationSegments use it.
+func (kind) Delete(ctx context.Context, c *adapter.Client, cr *v1alpha1.ServerGroup, id string) error {
+	dependents, err := dependents(ctx, c, cr, id)
+	if err != nil {
+		return err
 	}
-
-	id := meta.GetExternalName(cr)
-	if id == "" {
-		return errors.New(errNotServer)
+	if len(dependents) > 0 {
+		cond := zpaclient.HasDependents(dependents)
+		cr.SetConditions(cond)
+		return errors.New(cond.Message)
 	}
+	zpaclient.UnblockDeletion(cr)
 
-	customerID, _ := zpaclient.CustomerID(ctx, e.kube, mg)
 	req := &server_group_controller.DeleteAppServerGroupUsingDELETE1Params{
 		Context:    ctx,
 		GroupID:    id,
-		CustomerID: customerID,
-	}
-
-	_, err := e.client.ServerGroupController.DeleteAppServerGroupUsingDELETE1(req)
-	if err != nil {
-		return errors.Wrap(err, errDeleteFailed)
+		CustomerID: c.CustomerID,
 	}
+	_, err = c.ServerGroupController.DeleteAppServerGroupUsingDELETE1(req)
+	return err
+}
 
-	return nil
+func (kind) Import(obj *models.ServerGroupDTO) *v1alpha1.ServerGroup {
+	cr := &v1alpha1.ServerGroup{Spec: v1alpha1.ServerGroupSpec{ForProvider: *observedParameters(obj)}}
+	meta.SetExternalName(cr, obj.ID)
+	return cr
 }
 
-func (e *external) LateInitialize(cr *v1alpha1.ServerGroup, obj *server_group_controller.GetServerGroupUsingGET1OK) { // nolint:gocyclo
+// dependents describes the ApplicationSegments in the cluster whose
+// ServerGroups contain the supplied ServerGroup and the applications ZPA
+// reports for it.
+func dependents(ctx context.Context, c *adapter.Client, cr *v1alpha1.ServerGroup, id string) ([]string, error) {
+	l := &appv1alpha1.ApplicationSegmentList{}
+	if err := c.Kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListApplicationSegments)
+	}
 
-	if cr.Spec.ForProvider.ConfigSpace == "" && obj.Payload.ConfigSpace != "" {
-		cr.Spec.ForProvider.ConfigSpace = obj.Payload.ConfigSpace
+	mgs := make([]resource.Managed, 0)
+	for i := range l.Items {
+		as := &l.Items[i]
+		if slices.Contains(as.Spec.ForProvider.ServerGroups, id) && zpaclient.SameProviderConfig(as, cr) {
+			mgs = append(mgs, as)
+		}
 	}
 
-	if cr.Spec.ForProvider.IPAnchored == nil && zpaclient.Bool(obj.Payload.IPAnchored) != nil {
-		cr.Spec.ForProvider.IPAnchored = zpaclient.Bool(obj.Payload.IPAnchored)
+	obj, err := kind{}.Get(ctx, c, id)
+	if zpaclient.IsNotFound(err) {
+		return zpaclient.DescribeDependents("ApplicationSegment", mgs, nil), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errDescribeFailed)
+	}
+
+	objs := make([]zpaclient.NamedObject, 0, len(obj.Applications))
+	for _, app := range obj.Applications {
+		objs = append(objs, zpaclient.NamedObject{ID: app.ID, Name: app.Name})
 	}
 
+	return zpaclient.DescribeDependents("ApplicationSegment", mgs, objs), nil
 }
 
-// generateObservation generates observation for the input object server_group_controller.GetServerGroupUsingGET1OK
-func generateObservation(in *server_group_controller.GetServerGroupUsingGET1OK) v1alpha1.Observation {
+// generateObservation generates observation for the input object models.ServerGroupDTO
+func generateObservation(obj *models.ServerGroupDTO) v1alpha1.Observation {
 	cr := v1alpha1.Observation{}
 
-	obj := in.Payload
-
 	cr.CreationTime = obj.CreationTime
 	cr.ID = obj.ID
 	cr.ModifiedBy = obj.ModifiedBy
 	cr.ModifiedTime = obj.ModifiedTime
+	cr.Name = obj.Name
+	cr.ConfigSpace = obj.ConfigSpace
+	cr.Description = obj.Description
+	cr.DynamicDiscovery = obj.DynamicDiscovery
+	cr.Enabled = obj.Enabled
+	cr.IPAnchored = obj.IPAnchored
+	for _, g := range obj.AppConnectorGroups {
+		if g != nil {
+			cr.AppConnectorGroups = append(cr.AppConnectorGroups, v1alpha1.NameID{ID: g.ID, Name: zpaclient.StringValue(g.Name)})
+		}
+	}
+	for _, app := range obj.Applications {
+		if app != nil {
+			cr.Applications = append(cr.Applications, v1alpha1.NameID{ID: app.ID, Name: app.Name})
+		}
+	}
+	for _, s := range obj.Servers {
+		if s != nil {
+			cr.Servers = append(cr.Servers, v1alpha1.NameID{ID: s.ID, Name: zpaclient.StringValue(s.Name)})
+		}
+	}
 
 	return cr
 }
 
-// isUpToDate checks whether there is a change in any of the modifiable fields.
-func isUpToDate(cr *v1alpha1.ServerGroupParameters, gobj *server_group_controller.GetServerGroupUsingGET1OK) bool { // nolint:gocyclo
-	obj := gobj.Payload
+// observedParameters returns the parameters of the supplied ZPA object.
+func observedParameters(obj *models.ServerGroupDTO) *v1alpha1.ServerGroupParameters {
+	p := &v1alpha1.ServerGroupParameters{
+		Enabled:          zpaclient.Bool(obj.Enabled),
+		Description:      obj.Description,
+		IPAnchored:       zpaclient.Bool(obj.IPAnchored),
+		ConfigSpace:      obj.ConfigSpace,
+		DynamicDiscovery: obj.DynamicDiscovery,
+		Name:             obj.Name,
+	}
+	for _, g := range obj.AppConnectorGroups {
+		if g != nil {
+			p.AppConnectorGroups = append(p.AppConnectorGroups, g.ID)
+		}
+	}
+	return p
+}
+
+// driftedFields returns the modifiable fields whose desired value differs from
+// the value in ZPA.
+func driftedFields(cr *v1alpha1.ServerGroupParameters, obj *models.ServerGroupDTO) []string { // nolint:gocyclo
+	fields := make([]string, 0)
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.Description), zpaclient.StringToPtr(obj.Description)) {
-		return false
+		fields = append(fields, "description")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.ConfigSpace), zpaclient.StringToPtr(obj.ConfigSpace)) {
-		return false
+		fields = append(fields, "configSpace")
 	}
 
 	if !zpaclient.IsEqualBool(cr.IPAnchored, zpaclient.Bool(obj.IPAnchored)) {
-		return false
+		fields = append(fields, "ipAnchored")
 	}
 
 	if !zpaclient.IsEqualBool(zpaclient.Bool(cr.DynamicDiscovery), zpaclient.Bool(obj.DynamicDiscovery)) {
-		return false
+		fields = append(fields, "dynamicDiscovery")
 	}
 
 	if !zpaclient.IsEqualString(zpaclient.StringToPtr(cr.Name), zpaclient.StringToPtr(obj.Name)) {
-		return false
+		fields = append(fields, "name")
+	}
+
+	if !zpaclient.IsEqualBool(cr.Enabled, zpaclient.Bool(obj.Enabled)) {
+		fields = append(fields, "enabled")
+	}
+
+	appConnectorGroups := make([]string, 0, len(obj.AppConnectorGroups))
+	for _, g := range obj.AppConnectorGroups {
+		if g != nil {
+			appConnectorGroups = append(appConnectorGroups, g.ID)
+		}
+	}
+	if !zpaclient.IsEqualStringArrayContent(cr.AppConnectorGroups, appConnectorGroups) {
+		fields = append(fields, "appConnectorGroups")
 	}
 
-	return true
+	return fields
 }
@@ -0,0 +1,671 @@
+/*
+Copyright 2022 The Crossplane Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servergroup
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	zpa "github.com/haarchri/zpa-go-client/pkg/client"
+	"github.com/haarchri/zpa-go-client/pkg/client/connector_group_controller"
+	"github.com/haarchri/zpa-go-client/pkg/client/server_group_controller"
+	"github.com/haarchri/zpa-go-client/pkg/models"
+
+	appv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	v1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/servergroup/v1alpha1"
+	zpav1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	mockcg "github.com/crossplane-contrib/provider-zpa/pkg/client/mock/connector_group_controller"
+	mocksg "github.com/crossplane-contrib/provider-zpa/pkg/client/mock/server_group_controller"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+)
+
+const (
+	customerID     = "216196257331281920"
+	id             = "72058000000000001"
+	connectorGroup = "72058000000000010"
+
+	errCreateFailed = "cannot create ServerGroup"
+	errUpdateFailed = "cannot update ServerGroup"
+	errDeleteFailed = "cannot delete ServerGroup"
+)
+
+var (
+	errBoom     = errors.New("boom")
+	errNotFound = &zpaclient.APIError{StatusCode: http.StatusNotFound, Class: zpaclient.ErrorClassNotFound}
+	errConflict = &zpaclient.APIError{StatusCode: http.StatusBadRequest, ID: "duplicate.item", Class: zpaclient.ErrorClassConflict}
+)
+
+type sgModifier func(*v1alpha1.ServerGroup)
+
+func withExternalName(n string) sgModifier {
+	return func(cr *v1alpha1.ServerGroup) { meta.SetExternalName(cr, n) }
+}
+
+func withSpec(fn func(*v1alpha1.ServerGroupParameters)) sgModifier {
+	return func(cr *v1alpha1.ServerGroup) { fn(&cr.Spec.ForProvider) }
+}
+
+func withObservation(o v1alpha1.Observation) sgModifier {
+	return func(cr *v1alpha1.ServerGroup) { cr.Status.AtProvider = o }
+}
+
+func withConditions(c ...xpv1.Condition) sgModifier {
+	return func(cr *v1alpha1.ServerGroup) { cr.Status.SetConditions(c...) }
+}
+
+func serverGroup(m ...sgModifier) *v1alpha1.ServerGroup {
+	cr := &v1alpha1.ServerGroup{
+		Spec: v1alpha1.ServerGroupSpec{
+			ForProvider: v1alpha1.ServerGroupParameters{
+				Name:               "example",
+				ConfigSpace:        "DEFAULT",
+				Description:        "managed by crossplane",
+				Enabled:            zpaclient.Bool(true),
+				IPAnchored:         zpaclient.Bool(false),
+				DynamicDiscovery:   true,
+				AppConnectorGroups: []string{connectorGroup},
+			},
+		},
+	}
+	meta.SetExternalName(cr, id)
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+func payload(m ...func(*models.ServerGroupDTO)) *models.ServerGroupDTO {
+	p := &models.ServerGroupDTO{
+		ID:                 id,
+		Name:               "example",
+		ConfigSpace:        "DEFAULT",
+		Description:        "managed by crossplane",
+		Enabled:            true,
+		DynamicDiscovery:   true,
+		AppConnectorGroups: []*models.AppConnectorGroup{{ID: connectorGroup, Name: zpaclient.String("connectors")}},
+		ModifiedBy:         "72057594037928115",
+		ModifiedTime:       "1650000000",
+	}
+	for _, f := range m {
+		f(p)
+	}
+	return p
+}
+
+func observation(m ...func(*v1alpha1.Observation)) v1alpha1.Observation {
+	o := v1alpha1.Observation{
+		ID:                 id,
+		Name:               "example",
+		ConfigSpace:        "DEFAULT",
+		Description:        "managed by crossplane",
+		Enabled:            true,
+		DynamicDiscovery:   true,
+		AppConnectorGroups: []v1alpha1.NameID{{ID: connectorGroup, Name: "connectors"}},
+		ModifiedBy:         "72057594037928115",
+		ModifiedTime:       "1650000000",
+	}
+	for _, f := range m {
+		f(&o)
+	}
+	return o
+}
+
+type mocks struct {
+	sg *mocksg.MockClientService
+	cg *mockcg.MockClientService
+}
+
+func newExternal(t *testing.T, kube client.Client, mock func(mocks)) *adapter.External[*v1alpha1.ServerGroup, *models.ServerGroupDTO] {
+	ctrl := gomock.NewController(t)
+	m := mocks{sg: mocksg.NewMockClientService(ctrl), cg: mockcg.NewMockClientService(ctrl)}
+	if mock != nil {
+		mock(m)
+	}
+	c := &adapter.Client{
+		ZscalerPrivateAccessAPIPortal: &zpa.ZscalerPrivateAccessAPIPortal{ServerGroupController: m.sg, ConnectorGroupController: m.cg},
+		CustomerID:                    customerID,
+		Kube:                          kube,
+	}
+	return adapter.NewExternal[*v1alpha1.ServerGroup, *models.ServerGroupDTO](c, kind{}, event.NewNopRecorder())
+}
+
+func getConnectorGroup(m mocks) {
+	m.cg.EXPECT().GetAppConnectorGroupUsingGET1(gomock.Any()).Return(&connector_group_controller.GetAppConnectorGroupUsingGET1OK{
+		Payload: &models.AppConnectorGroup{ID: connectorGroup, Name: zpaclient.String("connectors")},
+	}, nil)
+}
+
+var equateStatus = []cmp.Option{
+	test.EquateConditions(),
+	cmpopts.IgnoreFields(zpav1alpha1.Drift{}, "DetectedTime"),
+}
+
+func TestObserve(t *testing.T) {
+	type args struct {
+		mock func(mocks)
+		cr   *v1alpha1.ServerGroup
+	}
+	type want struct {
+		cr     *v1alpha1.ServerGroup
+		result managed.ExternalObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoExternalName": {
+			reason: "A ServerGroup without external name does not exist yet.",
+			args: args{
+				cr: serverGroup(withExternalName("")),
+			},
+			want: want{
+				cr:     serverGroup(withExternalName("")),
+				result: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"ImportByName": {
+			reason: "A ServerGroup without external name should adopt the only ServerGroup with its name if importByName is set.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetAllServerGroupsUsingGET1(gomock.Any()).Return(&server_group_controller.GetAllServerGroupsUsingGET1OK{
+						Payload: &models.PageListOfServerGroupDTO{List: []*models.ServerGroupDTO{payload()}, TotalPages: 1},
+					}, nil)
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: payload()}, nil)
+				},
+				cr: serverGroup(withExternalName(""), withSpec(func(p *v1alpha1.ServerGroupParameters) {
+					p.ImportByName = zpaclient.Bool(true)
+				})),
+			},
+			want: want{
+				cr: serverGroup(
+					withSpec(func(p *v1alpha1.ServerGroupParameters) { p.ImportByName = zpaclient.Bool(true) }),
+					withObservation(observation()),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
+		"NotFound": {
+			reason: "A ServerGroup that is missing in ZPA does not exist.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				cr:     serverGroup(),
+				result: managed.ExternalObservation{ResourceExists: false},
+			},
+		},
+		"DescribeFailed": {
+			reason: "Errors reading the ServerGroup should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				cr:  serverGroup(),
+				err: errors.Wrap(errBoom, errDescribeFailed),
+			},
+		},
+		"UpToDate": {
+			reason: "A ServerGroup that matches ZPA is up to date.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: payload()}, nil)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				cr:     serverGroup(withObservation(observation()), withConditions(xpv1.Available())),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+		"LateInitialize": {
+			reason: "Parameters that are only set in ZPA should be late initialized.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: payload()}, nil)
+				},
+				cr: serverGroup(withSpec(func(p *v1alpha1.ServerGroupParameters) {
+					p.ConfigSpace = ""
+					p.IPAnchored = nil
+				})),
+			},
+			want: want{
+				cr:     serverGroup(withObservation(observation()), withConditions(xpv1.Available())),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			},
+		},
+		"Drifted": {
+			reason: "A ServerGroup whose connector groups were changed in ZPA is not up to date.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: payload(func(p *models.ServerGroupDTO) {
+						p.AppConnectorGroups = nil
+					})}, nil)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				cr: serverGroup(
+					withObservation(observation(func(o *v1alpha1.Observation) {
+						o.AppConnectorGroups = nil
+						o.LastDrift = &zpav1alpha1.Drift{
+							ModifiedBy:   "72057594037928115",
+							ModifiedTime: "1650000000",
+							Fields:       []zpav1alpha1.FieldDrift{{Path: "appConnectorGroups", Desired: "[" + connectorGroup + "]", Observed: "[]"}},
+						}
+					})),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			},
+		},
+		"IgnoredDrift": {
+			reason: "Drift of parameters listed in ignoreDrift should not make a ServerGroup outdated.",
+			args: args{
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: payload(func(p *models.ServerGroupDTO) {
+						p.Description = "changed"
+					})}, nil)
+				},
+				cr: serverGroup(withSpec(func(p *v1alpha1.ServerGroupParameters) {
+					p.IgnoreDrift = []string{"description"}
+				})),
+			},
+			want: want{
+				cr: serverGroup(
+					withSpec(func(p *v1alpha1.ServerGroupParameters) { p.IgnoreDrift = []string{"description"} }),
+					withObservation(observation(func(o *v1alpha1.Observation) { o.Description = "changed" })),
+					withConditions(xpv1.Available()),
+				),
+				result: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, nil, tc.args.mock)
+			got, err := e.Observe(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type args struct {
+		mock func(mocks)
+		cr   *v1alpha1.ServerGroup
+	}
+	type want struct {
+		cr     *v1alpha1.ServerGroup
+		result managed.ExternalCreation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "The ID of the created ServerGroup should become its external name.",
+			args: args{
+				mock: func(m mocks) {
+					getConnectorGroup(m)
+					m.sg.EXPECT().AddAppServerGroupUsingPOST1(gomock.Any()).DoAndReturn(
+						func(params *server_group_controller.AddAppServerGroupUsingPOST1Params, _ ...server_group_controller.ClientOption) (*server_group_controller.AddAppServerGroupUsingPOST1Created, error) {
+							want := []*models.AppConnectorGroup{{ID: connectorGroup, Name: zpaclient.String("connectors")}}
+							if diff := cmp.Diff(want, params.Group.AppConnectorGroups); diff != "" {
+								t.Errorf("AddAppServerGroupUsingPOST1(...): -want appConnectorGroups, +got:\n%s", diff)
+							}
+							return &server_group_controller.AddAppServerGroupUsingPOST1Created{Payload: payload()}, nil
+						})
+				},
+				cr: serverGroup(withExternalName("")),
+			},
+			want: want{
+				cr:     serverGroup(),
+				result: managed.ExternalCreation{ExternalNameAssigned: true},
+			},
+		},
+		"ConnectorGroupNotFound": {
+			reason: "Errors reading a referenced connector group should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					m.cg.EXPECT().GetAppConnectorGroupUsingGET1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: serverGroup(withExternalName("")),
+			},
+			want: want{
+				cr:  serverGroup(withExternalName("")),
+				err: errors.Wrap(errors.Wrap(errNotFound, errConnectorGroupNotFound), errCreateFailed),
+			},
+		},
+		"CreateFailed": {
+			reason: "Errors creating the ServerGroup should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					getConnectorGroup(m)
+					m.sg.EXPECT().AddAppServerGroupUsingPOST1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: serverGroup(withExternalName("")),
+			},
+			want: want{
+				cr:  serverGroup(withExternalName("")),
+				err: errors.Wrap(errBoom, errCreateFailed),
+			},
+		},
+		"NameConflict": {
+			reason: "A ServerGroup whose name is taken should report the conflict.",
+			args: args{
+				mock: func(m mocks) {
+					getConnectorGroup(m)
+					m.sg.EXPECT().AddAppServerGroupUsingPOST1(gomock.Any()).Return(nil, errConflict)
+					m.sg.EXPECT().GetAllServerGroupsUsingGET1(gomock.Any()).Return(&server_group_controller.GetAllServerGroupsUsingGET1OK{
+						Payload: &models.PageListOfServerGroupDTO{List: []*models.ServerGroupDTO{payload()}, TotalPages: 1},
+					}, nil)
+				},
+				cr: serverGroup(withExternalName("")),
+			},
+			want: want{
+				cr:  serverGroup(withExternalName("")),
+				err: errors.Wrap(&zpaclient.NameConflictError{Kind: "ServerGroup", Name: "example", IDs: []string{id}}, errCreateFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, nil, tc.args.mock)
+			got, err := e.Create(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		mock func(mocks)
+		cr   *v1alpha1.ServerGroup
+	}
+	type want struct {
+		err error
+	}
+
+	live := payload(func(p *models.ServerGroupDTO) {
+		p.Description = "changed"
+		p.Applications = []*models.NameIDDto{{ID: "72058000000000002", Name: "app"}}
+	})
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "Update should send the desired parameters merged with the fields it does not model.",
+			args: args{
+				mock: func(m mocks) {
+					getConnectorGroup(m)
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: live}, nil)
+					m.sg.EXPECT().UpdateAppServerGroupUsingPUT1(gomock.Any()).DoAndReturn(
+						func(params *server_group_controller.UpdateAppServerGroupUsingPUT1Params, _ ...server_group_controller.ClientOption) (*server_group_controller.UpdateAppServerGroupUsingPUT1Created, *server_group_controller.UpdateAppServerGroupUsingPUT1NoContent, error) {
+							want := payload(func(p *models.ServerGroupDTO) { p.Applications = live.Applications })
+							if diff := cmp.Diff(want, params.Group); diff != "" {
+								t.Errorf("UpdateAppServerGroupUsingPUT1(...): -want payload, +got:\n%s", diff)
+							}
+							return nil, &server_group_controller.UpdateAppServerGroupUsingPUT1NoContent{}, nil
+						})
+				},
+				cr: serverGroup(),
+			},
+		},
+		"IgnoredDrift": {
+			reason: "Update should keep the value in ZPA of parameters listed in ignoreDrift.",
+			args: args{
+				mock: func(m mocks) {
+					getConnectorGroup(m)
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: live}, nil)
+					m.sg.EXPECT().UpdateAppServerGroupUsingPUT1(gomock.Any()).DoAndReturn(
+						func(params *server_group_controller.UpdateAppServerGroupUsingPUT1Params, _ ...server_group_controller.ClientOption) (*server_group_controller.UpdateAppServerGroupUsingPUT1Created, *server_group_controller.UpdateAppServerGroupUsingPUT1NoContent, error) {
+							if diff := cmp.Diff("changed", params.Group.Description); diff != "" {
+								t.Errorf("UpdateAppServerGroupUsingPUT1(...): -want description, +got:\n%s", diff)
+							}
+							return nil, &server_group_controller.UpdateAppServerGroupUsingPUT1NoContent{}, nil
+						})
+				},
+				cr: serverGroup(withSpec(func(p *v1alpha1.ServerGroupParameters) {
+					p.IgnoreDrift = []string{"description"}
+				})),
+			},
+		},
+		"ConnectorGroupNotFound": {
+			reason: "Errors reading a referenced connector group should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					m.cg.EXPECT().GetAppConnectorGroupUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errConnectorGroupNotFound), errUpdateFailed),
+			},
+		},
+		"DescribeFailed": {
+			reason: "Errors reading the ServerGroup before updating it should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					getConnectorGroup(m)
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errDescribeFailed), errUpdateFailed),
+			},
+		},
+		"UpdateFailed": {
+			reason: "Errors updating the ServerGroup should be wrapped.",
+			args: args{
+				mock: func(m mocks) {
+					getConnectorGroup(m)
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: live}, nil)
+					m.sg.EXPECT().UpdateAppServerGroupUsingPUT1(gomock.Any()).Return(nil, nil, errBoom)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, nil, tc.args.mock)
+			_, err := e.Update(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		kube client.Client
+		mock func(mocks)
+		cr   *v1alpha1.ServerGroup
+	}
+	type want struct {
+		cr  *v1alpha1.ServerGroup
+		err error
+	}
+
+	noApplicationSegments := &test.MockClient{MockList: test.NewMockListFn(nil)}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Success": {
+			reason: "A ServerGroup without dependents should be deleted.",
+			args: args{
+				kube: noApplicationSegments,
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: payload()}, nil)
+					m.sg.EXPECT().DeleteAppServerGroupUsingDELETE1(gomock.Any()).Return(&server_group_controller.DeleteAppServerGroupUsingDELETE1NoContent{}, nil)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				cr: serverGroup(),
+			},
+		},
+		"NotFound": {
+			reason: "A ServerGroup that is already gone should be deleted successfully.",
+			args: args{
+				kube: noApplicationSegments,
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(nil, errNotFound)
+					m.sg.EXPECT().DeleteAppServerGroupUsingDELETE1(gomock.Any()).Return(nil, errNotFound)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				cr: serverGroup(),
+			},
+		},
+		"HasDependents": {
+			reason: "A ServerGroup that is used by ApplicationSegments should not be deleted.",
+			args: args{
+				kube: &test.MockClient{MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+					as := appv1alpha1.ApplicationSegment{}
+					as.SetName("app")
+					as.Spec.ForProvider.ServerGroups = []string{id}
+					meta.SetExternalName(&as, "72058000000000002")
+					o.(*appv1alpha1.ApplicationSegmentList).Items = []appv1alpha1.ApplicationSegment{as}
+					return nil
+				})},
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: payload(func(p *models.ServerGroupDTO) {
+						p.Applications = []*models.NameIDDto{
+							{ID: "72058000000000002", Name: "app"},
+							{ID: "72058000000000003", Name: "portal"},
+						}
+					})}, nil)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				cr:  serverGroup(withConditions(zpaclient.HasDependents([]string{"ApplicationSegment app", `ZPA ApplicationSegment "portal" (72058000000000003)`}))),
+				err: errors.Wrap(errors.New(`still used by ApplicationSegment app, ZPA ApplicationSegment "portal" (72058000000000003)`), errDeleteFailed),
+			},
+		},
+		"ListFailed": {
+			reason: "Errors listing ApplicationSegments should be wrapped.",
+			args: args{
+				kube: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				cr:   serverGroup(),
+			},
+			want: want{
+				cr:  serverGroup(),
+				err: errors.Wrap(errors.Wrap(errBoom, errListApplicationSegments), errDeleteFailed),
+			},
+		},
+		"DeleteFailed": {
+			reason: "Errors deleting the ServerGroup should be wrapped.",
+			args: args{
+				kube: noApplicationSegments,
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: payload()}, nil)
+					m.sg.EXPECT().DeleteAppServerGroupUsingDELETE1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: serverGroup(),
+			},
+			want: want{
+				cr:  serverGroup(),
+				err: errors.Wrap(errBoom, errDeleteFailed),
+			},
+		},
+		"NoLongerUsed": {
+			reason: "A ServerGroup whose deletion failed after its dependents are gone should not report the stale dependents.",
+			args: args{
+				kube: noApplicationSegments,
+				mock: func(m mocks) {
+					m.sg.EXPECT().GetServerGroupUsingGET1(gomock.Any()).Return(&server_group_controller.GetServerGroupUsingGET1OK{Payload: payload()}, nil)
+					m.sg.EXPECT().DeleteAppServerGroupUsingDELETE1(gomock.Any()).Return(nil, errBoom)
+				},
+				cr: serverGroup(withConditions(zpaclient.HasDependents([]string{"ApplicationSegment app"}))),
+			},
+			want: want{
+				cr:  serverGroup(withConditions(zpaclient.DeletionNotBlocked())),
+				err: errors.Wrap(errBoom, errDeleteFailed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := newExternal(t, tc.args.kube, tc.args.mock)
+			err := e.Delete(context.Background(), tc.args.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cr, tc.args.cr, equateStatus...); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want cr, +got cr:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
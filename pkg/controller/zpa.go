@@ -18,8 +18,16 @@ package controller
 
 import (
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/pkg/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	appv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	segmentv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/segmentgroup/v1alpha1"
+	serverv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/server/v1alpha1"
+	servergroupv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/servergroup/v1alpha1"
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
 	applicationSegment "github.com/crossplane-contrib/provider-zpa/pkg/controller/applicationsegment"
 	"github.com/crossplane-contrib/provider-zpa/pkg/controller/config"
 	segmentGroup "github.com/crossplane-contrib/provider-zpa/pkg/controller/segmentgroup"
@@ -27,19 +35,143 @@ import (
 	serverGroup "github.com/crossplane-contrib/provider-zpa/pkg/controller/servergroup"
 )
 
+const errCheckCRD = "cannot check whether the CRD of %s is installed"
+
+// controllers are the controllers of the provider with the kinds each of them
+// reads or writes, and thus needs the CRDs of.
+var controllers = []struct {
+	kinds []schema.GroupVersionKind
+	setup func(ctrl.Manager, controller.Options) error
+}{
+	{
+		kinds: []schema.GroupVersionKind{v1alpha1.ProviderConfigGroupVersionKind, v1alpha1.ProviderConfigUsageGroupVersionKind},
+		setup: config.Setup,
+	},
+	{
+		kinds: []schema.GroupVersionKind{appv1alpha1.ApplicationSegmentGroupVersionKind, v1alpha1.ProviderConfigGroupVersionKind, v1alpha1.ProviderConfigUsageGroupVersionKind},
+		setup: applicationSegment.SetupApplicationSegment,
+	},
+	{
+		// SegmentGroups are not deleted while ApplicationSegments use them.
+		kinds: []schema.GroupVersionKind{segmentv1alpha1.SegmentGroupGroupVersionKind, appv1alpha1.ApplicationSegmentGroupVersionKind, v1alpha1.ProviderConfigGroupVersionKind, v1alpha1.ProviderConfigUsageGroupVersionKind},
+		setup: segmentGroup.SetupSegmentGroup,
+	},
+	{
+		kinds: []schema.GroupVersionKind{serverv1alpha1.ServerGroupVersionKind, v1alpha1.ProviderConfigGroupVersionKind, v1alpha1.ProviderConfigUsageGroupVersionKind},
+		setup: server.SetupServer,
+	},
+	{
+		// ServerGroups are not deleted while ApplicationSegments use them.
+		kinds: []schema.GroupVersionKind{servergroupv1alpha1.ServerGroupGroupVersionKind, appv1alpha1.ApplicationSegmentGroupVersionKind, v1alpha1.ProviderConfigGroupVersionKind, v1alpha1.ProviderConfigUsageGroupVersionKind},
+		setup: serverGroup.SetupServerGroup,
+	},
+}
+
+// webhooks are the admission webhooks of the provider with the kinds they
+// admit.
+var webhooks = []struct {
+	kinds []schema.GroupVersionKind
+	setup func(ctrl.Manager) error
+}{
+	{
+		kinds: []schema.GroupVersionKind{appv1alpha1.ApplicationSegmentGroupVersionKind},
+		setup: applicationSegment.SetupApplicationSegmentWebhook,
+	},
+}
+
 // Setup creates all Cluster API controllers with the supplied logger and adds
 // them to the supplied manager.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
-	for _, setup := range []func(ctrl.Manager, controller.Options) error{
-		config.Setup,
-		applicationSegment.SetupApplicationSegment,
-		segmentGroup.SetupSegmentGroup,
-		server.SetupServer,
-		serverGroup.SetupServerGroup,
-	} {
-		if err := setup(mgr, o); err != nil {
+	return SetupWithout(mgr, o)
+}
+
+// SetupWithout is like Setup, but skips the controllers that need any of the
+// supplied kinds, e.g. because their CRDs are not installed.
+func SetupWithout(mgr ctrl.Manager, o controller.Options, skip ...schema.GroupVersionKind) error {
+	for _, c := range controllers {
+		if containsAny(c.kinds, skip) {
+			continue
+		}
+		if err := c.setup(mgr, o); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// SetupWebhooks registers all admission webhooks with the webhook server of
+// the supplied manager.
+func SetupWebhooks(mgr ctrl.Manager) error {
+	return SetupWebhooksWithout(mgr)
+}
+
+// SetupWebhooksWithout is like SetupWebhooks, but skips the webhooks that
+// admit any of the supplied kinds.
+func SetupWebhooksWithout(mgr ctrl.Manager, skip ...schema.GroupVersionKind) error {
+	for _, w := range webhooks {
+		if containsAny(w.kinds, skip) {
+			continue
+		}
+		if err := w.setup(mgr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Kinds returns the kinds the controllers and webhooks of the provider need
+// the CRDs of.
+func Kinds() []schema.GroupVersionKind {
+	kinds := make([]schema.GroupVersionKind, 0)
+	for _, c := range controllers {
+		kinds = appendMissing(kinds, c.kinds...)
+	}
+	for _, w := range webhooks {
+		kinds = appendMissing(kinds, w.kinds...)
+	}
+	return kinds
+}
+
+// MissingKinds returns the supplied kinds that the supplied RESTMapper does
+// not know, i.e. whose CRDs are not installed.
+func MissingKinds(m apimeta.RESTMapper, kinds ...schema.GroupVersionKind) ([]schema.GroupVersionKind, error) {
+	missing := make([]schema.GroupVersionKind, 0)
+	for _, gvk := range kinds {
+		_, err := m.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if apimeta.IsNoMatchError(err) {
+			missing = append(missing, gvk)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errCheckCRD, gvk.Kind)
+		}
+	}
+	return missing, nil
+}
+
+func containsAny(kinds, other []schema.GroupVersionKind) bool {
+	for _, k := range other {
+		if contains(kinds, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(kinds []schema.GroupVersionKind, k schema.GroupVersionKind) bool {
+	for _, e := range kinds {
+		if e == k {
+			return true
+		}
+	}
+	return false
+}
+
+func appendMissing(kinds []schema.GroupVersionKind, add ...schema.GroupVersionKind) []schema.GroupVersionKind {
+	for _, k := range add {
+		if !contains(kinds, k) {
+			kinds = append(kinds, k)
+		}
+	}
+	return kinds
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	appv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	serverv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/server/v1alpha1"
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+)
+
+type errMapper struct {
+	apimeta.RESTMapper
+	err error
+}
+
+func (m errMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*apimeta.RESTMapping, error) {
+	return nil, m.err
+}
+
+func TestMissingKinds(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	installed := apimeta.NewDefaultRESTMapper(nil)
+	installed.Add(v1alpha1.ProviderConfigGroupVersionKind, apimeta.RESTScopeRoot)
+	installed.Add(appv1alpha1.ApplicationSegmentGroupVersionKind, apimeta.RESTScopeRoot)
+
+	type want struct {
+		missing []schema.GroupVersionKind
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		mapper apimeta.RESTMapper
+		kinds  []schema.GroupVersionKind
+		want   want
+	}{
+		"AllInstalled": {
+			reason: "No kind is missing if the CRDs of all kinds are installed.",
+			mapper: installed,
+			kinds:  []schema.GroupVersionKind{v1alpha1.ProviderConfigGroupVersionKind, appv1alpha1.ApplicationSegmentGroupVersionKind},
+			want:   want{missing: []schema.GroupVersionKind{}},
+		},
+		"SomeMissing": {
+			reason: "Kinds whose CRDs are not installed are missing.",
+			mapper: installed,
+			kinds:  []schema.GroupVersionKind{v1alpha1.ProviderConfigGroupVersionKind, serverv1alpha1.ServerGroupVersionKind},
+			want:   want{missing: []schema.GroupVersionKind{serverv1alpha1.ServerGroupVersionKind}},
+		},
+		"MappingFailed": {
+			reason: "Errors other than unknown kinds should be returned.",
+			mapper: errMapper{err: errBoom},
+			kinds:  []schema.GroupVersionKind{serverv1alpha1.ServerGroupVersionKind},
+			want:   want{err: errors.Wrapf(errBoom, errCheckCRD, serverv1alpha1.ServerKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := MissingKinds(tc.mapper, tc.kinds...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMissingKinds(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.missing, got); diff != "" {
+				t.Errorf("\n%s\nMissingKinds(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
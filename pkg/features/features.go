@@ -21,4 +21,9 @@ const (
 	// External Secret Stores. See the below design for more details.
 	// https://github.com/crossplane/crossplane/blob/390ddd/design/design-doc-external-secret-stores.md
 	EnableAlphaExternalSecretStores feature.Flag = "EnableAlphaExternalSecretStores"
+
+	// EnableAlphaObserveOnly enables alpha support for managed resources
+	// that set observeOnly, i.e. that only observe their ZPA object and
+	// report its drift without ever changing it.
+	EnableAlphaObserveOnly feature.Flag = "EnableAlphaObserveOnly"
 )
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-zpa/apis"
+)
+
+const (
+	errReadManifest   = "cannot read manifest %s"
+	errDecodeManifest = "cannot decode %s %s of manifest %s"
+)
+
+// ReadManifests reads the managed resources of the provider from the YAML and
+// JSON manifests in the supplied directory and its subdirectories. Other
+// objects, e.g. ProviderConfigs or Secrets, are skipped.
+func ReadManifests(dir string) ([]resource.Managed, error) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, errScheme)
+	}
+
+	mgs := make([]resource.Managed, 0)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+		read, err := readManifest(s, path)
+		if err != nil {
+			return err
+		}
+		mgs = append(mgs, read...)
+		return nil
+	})
+	return mgs, err
+}
+
+// readManifest reads the managed resources of the provider from the supplied
+// manifest, which may contain several documents.
+func readManifest(s *runtime.Scheme, path string) ([]resource.Managed, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, errReadManifest, path)
+	}
+	defer f.Close() // nolint:errcheck
+
+	mgs := make([]resource.Managed, 0)
+	d := yaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		err := d.Decode(&u.Object)
+		if errors.Is(err, io.EOF) {
+			return mgs, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errReadManifest, path)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+
+		obj, err := s.New(u.GroupVersionKind())
+		if runtime.IsNotRegisteredError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errDecodeManifest, u.GetKind(), u.GetName(), path)
+		}
+		mg, ok := obj.(resource.Managed)
+		if !ok {
+			continue
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, mg); err != nil {
+			return nil, errors.Wrapf(err, errDecodeManifest, u.GetKind(), u.GetName(), path)
+		}
+		mgs = append(mgs, mg)
+	}
+}
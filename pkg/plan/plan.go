@@ -0,0 +1,387 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan tells what the provider would change in a ZPA tenant to
+// reconcile a set of managed resources, without changing anything. It uses
+// the same lookup, comparison and late initialization as the controllers.
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-zpa/apis"
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+	application "github.com/crossplane-contrib/provider-zpa/pkg/controller/applicationsegment"
+	segment "github.com/crossplane-contrib/provider-zpa/pkg/controller/segmentgroup"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/server"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/servergroup"
+	"github.com/crossplane-contrib/provider-zpa/pkg/tenant"
+)
+
+const (
+	errScheme            = "cannot build scheme"
+	errResolveReferences = "cannot resolve references"
+	errObserveOnly       = "%s does not exist in ZPA and is observe only, so it is not created"
+)
+
+// An Action the provider would take.
+type Action string
+
+// Actions the provider would take.
+const (
+	// Create creates a new object.
+	Create Action = "create"
+
+	// Update changes the fields of an object that differ from the
+	// parameters of its resource.
+	Update Action = "update"
+
+	// Delete deletes the object of a resource that is being deleted and
+	// whose deletion policy is Delete, or, if DeleteUnmanaged is set, an
+	// object that no resource manages.
+	Delete Action = "delete"
+
+	// Fail is the action for a resource the provider cannot reconcile,
+	// e.g. because it imports by name an object that does not exist.
+	Fail Action = "fail"
+)
+
+// A Change the provider would make to an object of the tenant.
+type Change struct {
+	Action Action
+
+	// Kind of the resource, e.g. SegmentGroup.
+	Kind string
+
+	// Resource is the name of the managed resource of the object. It is
+	// empty for deletions of unmanaged objects.
+	Resource string
+
+	// Name and ID are the name and the ID of the object in ZPA. The ID is
+	// empty for creations.
+	Name string
+	ID   string
+
+	// Fields are the fields that are created or updated, with their
+	// desired and observed value.
+	Fields []v1alpha1.FieldDrift
+
+	// Error tells why the provider cannot reconcile the resource.
+	Error string
+}
+
+// A Plan of the changes the provider would make to a tenant, in the order it
+// would make them: SegmentGroups, ServerGroups, Servers and
+// ApplicationSegments are created and updated, and then deleted in reverse
+// order.
+type Plan struct {
+	Changes []Change
+
+	// Unchanged is the number of resources whose object is up to date.
+	Unchanged int
+}
+
+// Options of a Plan.
+type Options struct {
+	// DeleteUnmanaged plans to delete the objects of the tenant no resource
+	// manages, as if the resources described the whole tenant. The
+	// provider never deletes such objects, so by default they are kept.
+	DeleteUnmanaged bool
+}
+
+// A kindPlanner plans the changes of the resources of a kind.
+type kindPlanner interface {
+	// match matches the resources with the objects they manage, and
+	// returns them with the IDs of their objects as external names, so
+	// that resources referencing them can be resolved.
+	match() []client.Object
+
+	// plan returns the creations and updates of the resources, resolving
+	// their references with the supplied client.
+	plan(ctx context.Context, kube client.Reader) ([]Change, int)
+
+	// deletes returns the deletions of the objects of the resources that
+	// are being deleted, and of the objects no resource manages if
+	// unmanaged is true. It also returns the number of resources being
+	// deleted whose object is kept, as their deletion policy is Orphan or
+	// the object does not exist.
+	deletes(unmanaged bool) ([]Change, int)
+}
+
+// New plans the changes the provider would make to the supplied tenant to
+// reconcile the supplied managed resources. References between the resources
+// are resolved among them; resources that would be created are referenced by
+// their name in angle brackets, e.g. <web-apps>, instead of the ID ZPA would
+// assign.
+func New(ctx context.Context, t *tenant.Tenant, mgs []resource.Managed, o Options) (*Plan, error) {
+	kinds := []kindPlanner{
+		newPlanner(segment.NewKind(), t.SegmentGroups, mgs),
+		newPlanner(servergroup.NewKind(), t.ServerGroups, mgs),
+		newPlanner(server.NewKind(), t.Servers, mgs),
+		newPlanner(application.NewKind(), t.ApplicationSegments, mgs),
+	}
+
+	objs := make([]client.Object, 0, len(mgs))
+	for _, k := range kinds {
+		objs = append(objs, k.match()...)
+	}
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		return nil, errors.Wrap(err, errScheme)
+	}
+	kube := fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+
+	p := &Plan{}
+	for _, k := range kinds {
+		changes, unchanged := k.plan(ctx, kube)
+		p.Changes = append(p.Changes, changes...)
+		p.Unchanged += unchanged
+	}
+	for i := len(kinds) - 1; i >= 0; i-- {
+		changes, kept := kinds[i].deletes(o.DeleteUnmanaged)
+		p.Changes = append(p.Changes, changes...)
+		p.Unchanged += kept
+	}
+	return p, nil
+}
+
+// Count returns the number of changes with the supplied action.
+func (p *Plan) Count(a Action) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == a {
+			n++
+		}
+	}
+	return n
+}
+
+// symbols of the actions in a printed Plan.
+var symbols = map[Action]string{Create: "+", Update: "~", Delete: "-", Fail: "!"}
+
+// Print prints the plan in a human readable form.
+func (p *Plan) Print(w io.Writer) error {
+	pw := &printer{w: w}
+	for _, c := range p.Changes {
+		switch {
+		case c.Resource == "":
+			pw.printf("%s %s %s %q (%s)\n", symbols[c.Action], c.Action, c.Kind, c.Name, c.ID)
+		case c.ID == "":
+			pw.printf("%s %s %s %s (%q)\n", symbols[c.Action], c.Action, c.Kind, c.Resource, c.Name)
+		default:
+			pw.printf("%s %s %s %s (%q, %s)\n", symbols[c.Action], c.Action, c.Kind, c.Resource, c.Name, c.ID)
+		}
+		for _, f := range c.Fields {
+			if c.Action == Create {
+				pw.printf("    %s: %s\n", f.Path, f.Desired)
+				continue
+			}
+			pw.printf("    %s: %q -> %q\n", f.Path, f.Observed, f.Desired)
+		}
+		if c.Error != "" {
+			pw.printf("    %s\n", c.Error)
+		}
+	}
+	pw.printf("Plan: %d to create, %d to update, %d to delete, %d unchanged, %d failed.\n",
+		p.Count(Create), p.Count(Update), p.Count(Delete), p.Unchanged, p.Count(Fail))
+	return pw.err
+}
+
+// A printer remembers the first error writing to w.
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) printf(format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, format, args...)
+	}
+}
+
+// A match of a resource with the object it manages.
+type match[R resource.Managed] struct {
+	cr R
+
+	// id of the object, or empty if it would be created.
+	id  string
+	err error
+}
+
+// A planner plans the changes of the resources of the kind R, whose objects
+// are of type O.
+type planner[R resource.Managed, O any] struct {
+	kind adapter.Kind[R, O]
+	crs  []R
+
+	// ids and names are the IDs and names of the objects of the tenant,
+	// and objs the objects by ID.
+	ids   []string
+	names []zpaclient.NamedObject
+	objs  map[string]O
+
+	matches []match[R]
+	managed map[string]bool
+}
+
+func newPlanner[R resource.Managed, O any](kind adapter.Kind[R, O], objs []O, mgs []resource.Managed) *planner[R, O] {
+	p := &planner[R, O]{kind: kind, objs: make(map[string]O, len(objs)), managed: map[string]bool{}}
+	for _, mg := range mgs {
+		if cr, ok := mg.(R); ok {
+			p.crs = append(p.crs, cr.DeepCopyObject().(R))
+		}
+	}
+	for _, obj := range objs {
+		cr := kind.Import(obj)
+		id := meta.GetExternalName(cr)
+		p.ids = append(p.ids, id)
+		p.names = append(p.names, zpaclient.NamedObject{ID: id, Name: kind.Parameters(cr).Name})
+		p.objs[id] = obj
+	}
+	return p
+}
+
+// match matches every resource with its object like the adapter does: by
+// its external name, by its name if it imports by name, or, if it would be
+// created, by its name if ZPA already has an object with that name. Observe
+// only resources whose object does not exist fail, as it is not created.
+func (p *planner[R, O]) match() []client.Object {
+	objs := make([]client.Object, 0, len(p.crs))
+	for _, cr := range p.crs {
+		m := match[R]{cr: cr, id: meta.GetExternalName(cr)}
+		params := p.kind.Parameters(cr)
+		if m.id == "" && params.ImportByName {
+			m.id, m.err = zpaclient.FindIDByName(p.kind.Kind(), params.Name, p.names)
+		}
+		if _, ok := p.objs[m.id]; !ok && m.err == nil && !params.ObserveOnly {
+			m.id, m.err = zpaclient.ResolveNameConflict(p.kind.Kind(), params.Name, params.NameConflictPolicy, p.names, nil)
+		}
+
+		if _, ok := p.objs[m.id]; !ok && m.err == nil && params.ObserveOnly {
+			m.id, m.err = "", errors.Errorf(errObserveOnly, p.kind.Kind())
+		}
+
+		if m.id != "" {
+			p.managed[m.id] = true
+			meta.SetExternalName(cr, m.id)
+		} else {
+			meta.SetExternalName(cr, "<"+cr.GetName()+">")
+		}
+		p.matches = append(p.matches, m)
+		objs = append(objs, cr)
+	}
+	return objs
+}
+
+func (p *planner[R, O]) plan(ctx context.Context, kube client.Reader) ([]Change, int) {
+	changes := make([]Change, 0, len(p.matches))
+	unchanged := 0
+	for _, m := range p.matches {
+		if meta.WasDeleted(m.cr) {
+			continue
+		}
+		c := Change{Kind: p.kind.Kind(), Resource: m.cr.GetName(), Name: p.kind.Parameters(m.cr).Name, ID: m.id}
+		if m.err == nil {
+			m.err = adapter.CheckIgnoreDrift(p.kind, m.cr)
+		}
+		if m.err == nil {
+			m.err = resolveReferences(ctx, kube, m.cr)
+		}
+
+		switch {
+		case m.err != nil:
+			c.Action, c.Error = Fail, m.err.Error()
+		case m.id == "":
+			c.Action = Create
+			c.Fields, _ = adapter.Drift(p.kind, m.cr, p.empty())
+		default:
+			obj := p.objs[m.id]
+			p.kind.FromAPI(m.cr, obj)
+			observeOnly := p.kind.Parameters(m.cr).ObserveOnly
+			if !observeOnly {
+				p.kind.LateInitialize(m.cr, obj)
+			}
+			diffs, _ := adapter.Drift(p.kind, m.cr, obj)
+			if len(diffs) == 0 || observeOnly {
+				unchanged++
+				continue
+			}
+			c.Action, c.Fields = Update, diffs
+		}
+		changes = append(changes, c)
+	}
+	return changes, unchanged
+}
+
+// deletes deletes the object of a resource that is being deleted like the
+// managed reconciler does: only if it exists and the deletion policy of the
+// resource is not Orphan.
+func (p *planner[R, O]) deletes(unmanaged bool) ([]Change, int) {
+	changes := make([]Change, 0)
+	kept := 0
+	for _, m := range p.matches {
+		if !meta.WasDeleted(m.cr) {
+			continue
+		}
+		if m.id == "" || m.err != nil || m.cr.GetDeletionPolicy() == xpv1.DeletionOrphan {
+			kept++
+			continue
+		}
+		changes = append(changes, Change{Action: Delete, Kind: p.kind.Kind(), Resource: m.cr.GetName(), Name: p.kind.Parameters(m.cr).Name, ID: m.id})
+	}
+	if !unmanaged {
+		return changes, kept
+	}
+	for i, id := range p.ids {
+		if !p.managed[id] {
+			changes = append(changes, Change{Action: Delete, Kind: p.kind.Kind(), Name: p.names[i].Name, ID: id})
+		}
+	}
+	return changes, kept
+}
+
+// empty returns an empty object, which a resource that would be created is
+// compared with to tell the fields it sets.
+func (p *planner[R, O]) empty() O {
+	var obj O
+	return reflect.New(reflect.TypeOf(obj).Elem()).Interface().(O)
+}
+
+// resolveReferences resolves the references of the supplied resource, if it
+// has any.
+func resolveReferences(ctx context.Context, kube client.Reader, mg resource.Managed) error {
+	r, ok := mg.(interface {
+		ResolveReferences(ctx context.Context, c client.Reader) error
+	})
+	if !ok {
+		return nil
+	}
+	return errors.Wrap(r.ResolveReferences(ctx, kube), errResolveReferences)
+}
@@ -0,0 +1,374 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-zpa/pkg/client/fake"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+	"github.com/crossplane-contrib/provider-zpa/pkg/tenant"
+)
+
+const manifests = `
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: ProviderConfig
+metadata:
+  name: default
+spec:
+  customerID: "216196257331281920"
+---
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: SegmentGroup
+metadata:
+  name: web-apps
+  annotations:
+    crossplane.io/external-name: "1001"
+spec:
+  forProvider:
+    name: Web Apps
+    description: Internal web applications
+    enabled: true
+---
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: ServerGroup
+metadata:
+  name: servers
+spec:
+  forProvider:
+    name: Servers
+    importByName: true
+    enabled: true
+---
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: ServerGroup
+metadata:
+  name: new-servers
+spec:
+  forProvider:
+    name: New Servers
+    enabled: true
+---
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: Server
+metadata:
+  name: db
+spec:
+  forProvider:
+    name: db.example.com
+    address: db.example.com
+    enabled: true
+    serverGroupRefs:
+    - name: servers
+---
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: Server
+metadata:
+  name: missing
+spec:
+  forProvider:
+    name: missing.example.com
+    address: missing.example.com
+    importByName: true
+---
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: ApplicationSegment
+metadata:
+  name: intranet
+spec:
+  forProvider:
+    name: Intranet
+    domainNames:
+    - intranet.example.com
+    segmentGroupIDRef:
+      name: web-apps
+    serverGroupRefs:
+    - name: new-servers
+    tcpPortRange:
+    - from: 80
+      to: 80
+`
+
+func TestPlan(t *testing.T) {
+	zpa := fake.NewServer()
+	defer zpa.Close()
+
+	zpa.Seed(fake.SegmentGroups, fake.Object{"id": "1001", "name": "Web Apps", "enabled": true})
+	zpa.Seed(fake.ServerGroups, fake.Object{"id": "2001", "name": "Servers", "enabled": true})
+	zpa.Seed(fake.Servers, fake.Object{"id": "3001", "name": "old.example.com", "address": "old.example.com", "enabled": true})
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tenant.yaml"), []byte(manifests), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mgs, err := ReadManifests(dir)
+	if err != nil {
+		t.Fatalf("ReadManifests(...): %v", err)
+	}
+	tn, err := tenant.Read(context.Background(), adapter.NewClient(zpa.Config(), nil))
+	if err != nil {
+		t.Fatalf("tenant.Read(...): %v", err)
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      Options
+		want   *Plan
+	}{
+		"DeleteUnmanaged": {
+			reason: "Objects no resource manages should be deleted with DeleteUnmanaged.",
+			o:      Options{DeleteUnmanaged: true},
+			want: &Plan{
+				Changes: []Change{
+					{Action: Update, Kind: "SegmentGroup", Resource: "web-apps", Name: "Web Apps", ID: "1001", Fields: []v1alpha1.FieldDrift{
+						{Path: "description", Desired: "Internal web applications"},
+					}},
+					{Action: Create, Kind: "ServerGroup", Resource: "new-servers", Name: "New Servers", Fields: []v1alpha1.FieldDrift{
+						{Path: "enabled", Desired: "true", Observed: "false"},
+						{Path: "name", Desired: "New Servers"},
+					}},
+					{Action: Create, Kind: "Server", Resource: "db", Name: "db.example.com", Fields: []v1alpha1.FieldDrift{
+						{Path: "address", Desired: "db.example.com"},
+						{Path: "serverGroups", Desired: "[2001]", Observed: "[]"},
+						{Path: "name", Desired: "db.example.com"},
+					}},
+					{Action: Fail, Kind: "Server", Resource: "missing", Name: "missing.example.com", Error: `cannot import Server: no object named "missing.example.com" exists`},
+					{Action: Create, Kind: "ApplicationSegment", Resource: "intranet", Name: "Intranet", Fields: []v1alpha1.FieldDrift{
+						{Path: "domainNames", Desired: "[intranet.example.com]", Observed: "[]"},
+						{Path: "segmentGroupID", Desired: "1001"},
+						{Path: "tcpPortRange", Desired: "[{80 80}]", Observed: "[]"},
+						{Path: "serverGroups", Desired: "[<new-servers>]", Observed: "[]"},
+						{Path: "name", Desired: "Intranet"},
+					}},
+					{Action: Delete, Kind: "Server", Name: "old.example.com", ID: "3001"},
+				},
+				Unchanged: 1,
+			},
+		},
+		"KeepUnmanaged": {
+			reason: "Objects no resource manages should be kept by default, like the provider does.",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := New(context.Background(), tn, mgs, tc.o)
+			if err != nil {
+				t.Fatalf("New(...): %v", err)
+			}
+			if tc.want == nil {
+				if n := got.Count(Delete); n != 0 {
+					t.Errorf("\n%s\nNew(...): want no deletions, got %d", tc.reason, n)
+				}
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nNew(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+const deletingManifests = `
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: Server
+metadata:
+  name: deleted
+  deletionTimestamp: "2022-04-15T05:20:00Z"
+  annotations:
+    crossplane.io/external-name: "3001"
+spec:
+  deletionPolicy: Delete
+  forProvider:
+    name: deleted.example.com
+    address: deleted.example.com
+---
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: Server
+metadata:
+  name: orphaned
+  deletionTimestamp: "2022-04-15T05:20:00Z"
+  annotations:
+    crossplane.io/external-name: "3002"
+spec:
+  deletionPolicy: Orphan
+  forProvider:
+    name: orphaned.example.com
+    address: orphaned.example.com
+---
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: Server
+metadata:
+  name: gone
+  deletionTimestamp: "2022-04-15T05:20:00Z"
+  annotations:
+    crossplane.io/external-name: "3004"
+spec:
+  deletionPolicy: Delete
+  forProvider:
+    name: gone.example.com
+    address: gone.example.com
+`
+
+func TestPlanDeletionPolicy(t *testing.T) {
+	zpa := fake.NewServer()
+	defer zpa.Close()
+
+	zpa.Seed(fake.Servers, fake.Object{"id": "3001", "name": "deleted.example.com", "address": "deleted.example.com"})
+	zpa.Seed(fake.Servers, fake.Object{"id": "3002", "name": "orphaned.example.com", "address": "orphaned.example.com"})
+	zpa.Seed(fake.Servers, fake.Object{"id": "3003", "name": "unmanaged.example.com", "address": "unmanaged.example.com"})
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tenant.yaml"), []byte(deletingManifests), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mgs, err := ReadManifests(dir)
+	if err != nil {
+		t.Fatalf("ReadManifests(...): %v", err)
+	}
+	tn, err := tenant.Read(context.Background(), adapter.NewClient(zpa.Config(), nil))
+	if err != nil {
+		t.Fatalf("tenant.Read(...): %v", err)
+	}
+
+	deleted := Change{Action: Delete, Kind: "Server", Resource: "deleted", Name: "deleted.example.com", ID: "3001"}
+	cases := map[string]struct {
+		reason string
+		o      Options
+		want   *Plan
+	}{
+		"DeletionPolicy": {
+			reason: "Only the existing object of a resource being deleted with the Delete policy should be deleted.",
+			want:   &Plan{Changes: []Change{deleted}, Unchanged: 2},
+		},
+		"DeleteUnmanaged": {
+			reason: "Orphaned objects should still be kept with DeleteUnmanaged.",
+			o:      Options{DeleteUnmanaged: true},
+			want: &Plan{
+				Changes:   []Change{deleted, {Action: Delete, Kind: "Server", Name: "unmanaged.example.com", ID: "3003"}},
+				Unchanged: 2,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := New(context.Background(), tn, mgs, tc.o)
+			if err != nil {
+				t.Fatalf("New(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nNew(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+const observeOnlyManifests = `
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: SegmentGroup
+metadata:
+  name: web-apps
+  annotations:
+    crossplane.io/external-name: "1001"
+spec:
+  forProvider:
+    name: Web Apps
+    description: Internal web applications
+    observeOnly: true
+---
+apiVersion: zpa.crossplane.io/v1alpha1
+kind: ServerGroup
+metadata:
+  name: servers
+spec:
+  forProvider:
+    name: Servers
+    enabled: true
+    observeOnly: true
+`
+
+func TestPlanObserveOnly(t *testing.T) {
+	zpa := fake.NewServer()
+	defer zpa.Close()
+
+	zpa.Seed(fake.SegmentGroups, fake.Object{"id": "1001", "name": "Web Apps", "enabled": true})
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tenant.yaml"), []byte(observeOnlyManifests), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mgs, err := ReadManifests(dir)
+	if err != nil {
+		t.Fatalf("ReadManifests(...): %v", err)
+	}
+	tn, err := tenant.Read(context.Background(), adapter.NewClient(zpa.Config(), nil))
+	if err != nil {
+		t.Fatalf("tenant.Read(...): %v", err)
+	}
+
+	got, err := New(context.Background(), tn, mgs, Options{})
+	if err != nil {
+		t.Fatalf("New(...): %v", err)
+	}
+	// Observe only resources are neither updated nor created.
+	want := &Plan{
+		Changes: []Change{
+			{Action: Fail, Kind: "ServerGroup", Resource: "servers", Name: "Servers", Error: "ServerGroup does not exist in ZPA and is observe only, so it is not created"},
+		},
+		Unchanged: 1,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("New(...): -want, +got:\n%s", diff)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	p := &Plan{
+		Changes: []Change{
+			{Action: Create, Kind: "ServerGroup", Resource: "servers", Name: "Servers", Fields: []v1alpha1.FieldDrift{{Path: "name", Desired: "Servers"}}},
+			{Action: Update, Kind: "SegmentGroup", Resource: "web-apps", Name: "Web Apps", ID: "1001", Fields: []v1alpha1.FieldDrift{{Path: "enabled", Desired: "false", Observed: "true"}}},
+			{Action: Delete, Kind: "Server", Name: "old.example.com", ID: "3001"},
+			{Action: Fail, Kind: "Server", Resource: "missing", Name: "missing.example.com", Error: "cannot resolve references"},
+		},
+		Unchanged: 2,
+	}
+	want := `+ create ServerGroup servers ("Servers")
+    name: Servers
+~ update SegmentGroup web-apps ("Web Apps", 1001)
+    enabled: "true" -> "false"
+- delete Server "old.example.com" (3001)
+! fail Server missing ("missing.example.com")
+    cannot resolve references
+Plan: 1 to create, 1 to update, 1 to delete, 2 unchanged, 1 failed.
+`
+
+	b := &bytes.Buffer{}
+	if err := p.Print(b); err != nil {
+		t.Fatalf("Print(...): %v", err)
+	}
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("Print(...): -want, +got:\n%s", diff)
+	}
+}
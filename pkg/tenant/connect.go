@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-zpa/apis"
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+)
+
+const (
+	errKubeClient     = "cannot create Kubernetes client"
+	errProviderConfig = "cannot get ProviderConfig %s"
+	errSignIn         = "cannot sign in to ZPA"
+)
+
+// Connect returns a client of the tenant of the named ProviderConfig in the
+// cluster of the supplied kubeconfig, or of the in-cluster config or
+// $KUBECONFIG if it is empty. It signs in like the provider does.
+func Connect(ctx context.Context, kubeconfig, providerConfig string) (*adapter.Client, error) {
+	kube, err := newKubeClient(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, errKubeClient)
+	}
+
+	pc := &v1alpha1.ProviderConfig{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: providerConfig}, pc); err != nil {
+		return nil, errors.Wrapf(err, errProviderConfig, providerConfig)
+	}
+
+	cfg, err := zpaclient.NewConfig(ctx, kube, pc)
+	if err != nil {
+		return nil, errors.Wrap(err, errSignIn)
+	}
+	return adapter.NewClient(cfg, kube), nil
+}
+
+// newKubeClient returns a client of the cluster of the supplied kubeconfig,
+// or of the in-cluster config or $KUBECONFIG if it is empty. It knows the
+// types of the provider.
+func newKubeClient(kubeconfig string) (client.Client, error) {
+	var cfg *rest.Config
+	var err error
+	if kubeconfig != "" {
+		cfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		cfg, err = ctrl.GetConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	if err := apis.AddToScheme(s); err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: s})
+}
@@ -0,0 +1,389 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/haarchri/zpa-go-client/pkg/models"
+
+	appv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	segmentv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/segmentgroup/v1alpha1"
+	serverv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/server/v1alpha1"
+	servergroupv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/servergroup/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+	application "github.com/crossplane-contrib/provider-zpa/pkg/controller/applicationsegment"
+	segment "github.com/crossplane-contrib/provider-zpa/pkg/controller/segmentgroup"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/server"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/servergroup"
+)
+
+// SnapshotVersion is the version of the snapshots this version of the
+// provider writes and reads.
+const SnapshotVersion = "v1alpha1"
+
+const (
+	errReadSnapshot     = "cannot read snapshot"
+	errWriteSnapshot    = "cannot write snapshot"
+	errSnapshotVersion  = "unsupported snapshot version %q, want %q"
+	errCopy             = "cannot copy %s %s"
+	errUnknownReference = "%s %s references %s, which is not part of the snapshot"
+	errRestore          = "cannot restore %s %s"
+)
+
+// A Snapshot of the objects of a tenant that the provider manages. Objects
+// reference each other by symbolic names of the form <Kind>/<name>, e.g.
+// ServerGroup/servers, instead of by ID, so that they can be restored into
+// any tenant. IDs of objects the provider does not manage, e.g. of connector
+// groups, are kept.
+type Snapshot struct {
+	Version             string                               `json:"version"`
+	SegmentGroups       []Entry[*models.SegmentGroup]        `json:"segmentGroups,omitempty"`
+	ServerGroups        []Entry[*models.ServerGroupDTO]      `json:"serverGroups,omitempty"`
+	Servers             []Entry[*models.ApplicationServer]   `json:"servers,omitempty"`
+	ApplicationSegments []Entry[*models.ApplicationResource] `json:"applicationSegments,omitempty"`
+}
+
+// An Entry of a Snapshot.
+type Entry[O any] struct {
+	// Name is the symbolic name other objects reference the object by. It
+	// is the name zpa-import gives the managed resource of the object.
+	Name string `json:"name"`
+
+	// ID is the ID of the object in the tenant the snapshot was taken of.
+	ID string `json:"id"`
+
+	// Object is the object without its ID and the fields ZPA computes
+	// from other objects, e.g. the applications of a segment group.
+	Object O `json:"object"`
+}
+
+// A Restored object.
+type Restored struct {
+	// Kind, Name and ID are the kind of the object, its symbolic name and
+	// its ID in the tenant it was restored into.
+	Kind string
+	Name string
+	ID   string
+
+	// Created is false if the object already existed, i.e. with the ID of
+	// the snapshot or with the same name, and was kept as it is.
+	Created bool
+}
+
+// A ref is a reference of an object to another object.
+type ref struct {
+	kind string
+	id   *string
+}
+
+// An objectType tells how to take a snapshot of the objects of type O.
+type objectType[O any] struct {
+	kind string
+
+	// id returns the ID field of the supplied object.
+	id func(obj O) *string
+
+	// name returns the name of the supplied object.
+	name func(obj O) string
+
+	// clear clears the fields ZPA computes from other objects.
+	clear func(obj O)
+
+	// refs returns the references of the supplied object.
+	refs func(obj O) []ref
+}
+
+var (
+	segmentGroupType = objectType[*models.SegmentGroup]{
+		kind: segmentv1alpha1.SegmentGroupKind,
+		id:   func(obj *models.SegmentGroup) *string { return &obj.ID },
+		name: func(obj *models.SegmentGroup) string { return zpaclient.StringValue(obj.Name) },
+		clear: func(obj *models.SegmentGroup) {
+			obj.Applications = nil
+		},
+		refs: func(obj *models.SegmentGroup) []ref { return nil },
+	}
+
+	serverGroupType = objectType[*models.ServerGroupDTO]{
+		kind: servergroupv1alpha1.ServerGroupKind,
+		id:   func(obj *models.ServerGroupDTO) *string { return &obj.ID },
+		name: func(obj *models.ServerGroupDTO) string { return obj.Name },
+		clear: func(obj *models.ServerGroupDTO) {
+			obj.Applications = nil
+			obj.Servers = nil
+		},
+		refs: func(obj *models.ServerGroupDTO) []ref { return nil },
+	}
+
+	serverType = objectType[*models.ApplicationServer]{
+		kind:  serverv1alpha1.ServerKind,
+		id:    func(obj *models.ApplicationServer) *string { return &obj.ID },
+		name:  func(obj *models.ApplicationServer) string { return zpaclient.StringValue(obj.Name) },
+		clear: func(obj *models.ApplicationServer) {},
+		refs: func(obj *models.ApplicationServer) []ref {
+			refs := make([]ref, 0, len(obj.AppServerGroupIds))
+			for i := range obj.AppServerGroupIds {
+				refs = append(refs, ref{kind: servergroupv1alpha1.ServerGroupKind, id: &obj.AppServerGroupIds[i]})
+			}
+			return refs
+		},
+	}
+
+	applicationType = objectType[*models.ApplicationResource]{
+		kind: appv1alpha1.ApplicationSegmentKind,
+		id:   func(obj *models.ApplicationResource) *string { return &obj.ID },
+		name: func(obj *models.ApplicationResource) string { return obj.Name },
+		clear: func(obj *models.ApplicationResource) {
+			obj.SegmentGroupName = ""
+		},
+		refs: func(obj *models.ApplicationResource) []ref {
+			refs := []ref{{kind: segmentv1alpha1.SegmentGroupKind, id: &obj.SegmentGroupID}}
+			for _, g := range obj.ServerGroups {
+				if g != nil {
+					refs = append(refs, ref{kind: servergroupv1alpha1.ServerGroupKind, id: &g.ID})
+				}
+			}
+			return refs
+		},
+	}
+)
+
+// Snapshot returns a snapshot of the tenant.
+func (t *Tenant) Snapshot() (*Snapshot, error) {
+	s := &Snapshot{Version: SnapshotVersion}
+
+	// symbols are the symbolic names of the objects of the tenant by ID.
+	symbols := map[string]string{}
+	var err error
+	if s.SegmentGroups, err = entries(segmentGroupType, t.SegmentGroups, symbols); err != nil {
+		return nil, err
+	}
+	if s.ServerGroups, err = entries(serverGroupType, t.ServerGroups, symbols); err != nil {
+		return nil, err
+	}
+	if s.Servers, err = entries(serverType, t.Servers, symbols); err != nil {
+		return nil, err
+	}
+	if s.ApplicationSegments, err = entries(applicationType, t.ApplicationSegments, symbols); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// entries returns the entries of the supplied objects, adding their symbolic
+// names to the supplied ones. References to objects with a symbolic name are
+// replaced by it.
+func entries[O any](ot objectType[O], objs []O, symbols map[string]string) ([]Entry[O], error) {
+	n := newNamer(strings.ToLower(ot.kind))
+	es := make([]Entry[O], 0, len(objs))
+	for _, o := range objs {
+		id := *ot.id(o)
+		obj, err := deepCopy(o)
+		if err != nil {
+			return nil, errors.Wrapf(err, errCopy, ot.kind, id)
+		}
+
+		*ot.id(obj) = ""
+		ot.clear(obj)
+		for _, r := range ot.refs(obj) {
+			if s, ok := symbols[r.kind+"/"+*r.id]; ok {
+				*r.id = s
+			}
+		}
+
+		name := n.name(id, ot.name(obj))
+		symbols[ot.kind+"/"+id] = ot.kind + "/" + name
+		es = append(es, Entry[O]{Name: name, ID: id, Object: obj})
+	}
+	return es, nil
+}
+
+// Tenant returns the tenant the snapshot was taken of, i.e. whose objects
+// have the IDs they had then.
+func (s *Snapshot) Tenant() (*Tenant, error) {
+	// ids are the IDs of the objects by their symbolic name.
+	ids := map[string]string{}
+	t := &Tenant{}
+	var err error
+	if t.SegmentGroups, err = objects(segmentGroupType, s.SegmentGroups, ids); err != nil {
+		return nil, err
+	}
+	if t.ServerGroups, err = objects(serverGroupType, s.ServerGroups, ids); err != nil {
+		return nil, err
+	}
+	if t.Servers, err = objects(serverType, s.Servers, ids); err != nil {
+		return nil, err
+	}
+	if t.ApplicationSegments, err = objects(applicationType, s.ApplicationSegments, ids); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// objects returns the objects of the supplied entries with their IDs, adding
+// them to the supplied IDs by symbolic name.
+func objects[O any](ot objectType[O], es []Entry[O], ids map[string]string) ([]O, error) {
+	objs := make([]O, 0, len(es))
+	for _, e := range es {
+		obj, err := resolve(ot, e, ids)
+		if err != nil {
+			return nil, err
+		}
+		*ot.id(obj) = e.ID
+		ids[ot.kind+"/"+e.Name] = e.ID
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// resolve returns a copy of the object of the supplied entry whose symbolic
+// references are replaced by the supplied IDs.
+func resolve[O any](ot objectType[O], e Entry[O], ids map[string]string) (O, error) {
+	obj, err := deepCopy(e.Object)
+	if err != nil {
+		return obj, errors.Wrapf(err, errCopy, ot.kind, e.Name)
+	}
+	for _, r := range ot.refs(obj) {
+		if !strings.HasPrefix(*r.id, r.kind+"/") {
+			continue
+		}
+		id, ok := ids[*r.id]
+		if !ok {
+			return obj, errors.Errorf(errUnknownReference, ot.kind, e.Name, *r.id)
+		}
+		*r.id = id
+	}
+	return obj, nil
+}
+
+// Restore restores the objects of the snapshot into the tenant of the
+// supplied client in the order they depend on each other: SegmentGroups,
+// ServerGroups, Servers and ApplicationSegments. Objects that exist, with
+// the ID of the snapshot or with the same name, are kept as they are; the
+// others are created. It returns the objects restored until an error
+// occurred.
+func (s *Snapshot) Restore(ctx context.Context, c *adapter.Client) ([]Restored, error) {
+	ids := map[string]string{}
+	restored := make([]Restored, 0, len(s.SegmentGroups)+len(s.ServerGroups)+len(s.Servers)+len(s.ApplicationSegments))
+	var err error
+	if restored, err = restore(ctx, c, segment.NewKind(), segmentGroupType, s.SegmentGroups, ids, restored); err != nil {
+		return restored, err
+	}
+	if restored, err = restore(ctx, c, servergroup.NewKind(), serverGroupType, s.ServerGroups, ids, restored); err != nil {
+		return restored, err
+	}
+	if restored, err = restore(ctx, c, server.NewKind(), serverType, s.Servers, ids, restored); err != nil {
+		return restored, err
+	}
+	return restore(ctx, c, application.NewKind(), applicationType, s.ApplicationSegments, ids, restored)
+}
+
+// restore restores the objects of the supplied entries, adding their IDs to
+// the supplied IDs by symbolic name and them to the supplied restored ones.
+func restore[R resource.Managed, O any](ctx context.Context, c *adapter.Client, k adapter.Kind[R, O], ot objectType[O], es []Entry[O], ids map[string]string, restored []Restored) ([]Restored, error) {
+	for _, e := range es {
+		r := Restored{Kind: ot.kind, Name: e.Name}
+		id, err := existing(ctx, c, k, e.ID, ot.name(e.Object))
+		if err != nil {
+			return restored, errors.Wrapf(err, errRestore, ot.kind, e.Name)
+		}
+
+		if id == "" {
+			obj, err := resolve(ot, e, ids)
+			if err != nil {
+				return restored, err
+			}
+			if id, err = k.Create(ctx, c, obj); err != nil {
+				return restored, errors.Wrapf(err, errRestore, ot.kind, e.Name)
+			}
+			r.Created = true
+		}
+
+		r.ID = id
+		ids[ot.kind+"/"+e.Name] = id
+		restored = append(restored, r)
+	}
+	return restored, nil
+}
+
+// existing returns the ID of the object with the supplied ID, or else of the
+// only object with the supplied name. It returns an empty ID if there is no
+// such object, and an error if several objects have the name.
+func existing[R resource.Managed, O any](ctx context.Context, c *adapter.Client, k adapter.Kind[R, O], id, name string) (string, error) {
+	if id != "" {
+		_, err := k.Get(ctx, c, id)
+		if err == nil {
+			return id, nil
+		}
+		if !zpaclient.IsNotFound(err) {
+			return "", err
+		}
+	}
+
+	objs, err := k.List(ctx, c, name)
+	if err != nil {
+		return "", err
+	}
+	return zpaclient.ResolveNameConflict(k.Kind(), name, zpaclient.NameConflictPolicyAdopt, objs, nil)
+}
+
+// ReadSnapshot reads the snapshot in the supplied file.
+func ReadSnapshot(path string) (*Snapshot, error) {
+	b, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrap(err, errReadSnapshot)
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, errors.Wrap(err, errReadSnapshot)
+	}
+	if s.Version != SnapshotVersion {
+		return nil, errors.Errorf(errSnapshotVersion, s.Version, SnapshotVersion)
+	}
+	return s, nil
+}
+
+// Write writes the snapshot to the supplied file.
+func (s *Snapshot) Write(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, errWriteSnapshot)
+	}
+	return errors.Wrap(ioutil.WriteFile(path, append(b, '\n'), 0600), errWriteSnapshot)
+}
+
+// deepCopy returns a copy of the supplied object that shares no values with
+// it.
+func deepCopy[O any](obj O) (O, error) {
+	var out O
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return out, err
+	}
+	err = json.Unmarshal(b, &out)
+	return out, err
+}
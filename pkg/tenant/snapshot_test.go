@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/haarchri/zpa-go-client/pkg/client/application_controller"
+	"github.com/haarchri/zpa-go-client/pkg/models"
+
+	"github.com/crossplane-contrib/provider-zpa/pkg/client/fake"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+)
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	src := fake.NewServer()
+	defer src.Close()
+	web := src.Seed(fake.SegmentGroups, fake.Object{"name": "Web Apps", "enabled": true})
+	group := src.Seed(fake.ServerGroups, fake.Object{"name": "Servers", "enabled": true, "appConnectorGroups": []interface{}{map[string]interface{}{"id": "72058000000088888"}}})
+	srv := src.Seed(fake.Servers, fake.Object{"name": "db.example.com", "address": "db.example.com", "enabled": true, "appServerGroupIds": []interface{}{group}})
+	app := src.Seed(fake.Applications, fake.Object{
+		"name":           "Intranet",
+		"domainNames":    []interface{}{"intranet.example.com"},
+		"segmentGroupId": web,
+		"serverGroups":   []interface{}{map[string]interface{}{"id": group}},
+		"tcpPortRanges":  []interface{}{"80", "80"},
+	})
+
+	tn, err := Read(ctx, adapter.NewClient(src.Config(), nil))
+	if err != nil {
+		t.Fatalf("Read(...): %v", err)
+	}
+	s, err := tn.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot(): %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := s.Write(path); err != nil {
+		t.Fatalf("Write(...): %v", err)
+	}
+	s, err = ReadSnapshot(path)
+	if err != nil {
+		t.Fatalf("ReadSnapshot(...): %v", err)
+	}
+
+	if got := s.Servers[0].Object.AppServerGroupIds; !cmp.Equal([]string{"ServerGroup/servers"}, got) {
+		t.Errorf("Snapshot(): server should reference its server group by symbolic name, got %v", got)
+	}
+	if got := s.ApplicationSegments[0].Object.SegmentGroupID; got != "SegmentGroup/web-apps" {
+		t.Errorf("Snapshot(): application segment should reference its segment group by symbolic name, got %q", got)
+	}
+	if got := s.ServerGroups[0].Object.AppConnectorGroups[0].ID; got != "72058000000088888" {
+		t.Errorf("Snapshot(): server group should keep the ID of its connector group, got %q", got)
+	}
+
+	restored, err := s.Tenant()
+	if err != nil {
+		t.Fatalf("Tenant(): %v", err)
+	}
+	// Snapshots do not keep the fields ZPA computes from other objects.
+	computed := []cmp.Option{
+		cmpopts.IgnoreFields(models.SegmentGroup{}, "Applications"),
+		cmpopts.IgnoreFields(models.ServerGroupDTO{}, "Applications", "Servers"),
+		cmpopts.IgnoreFields(models.ApplicationResource{}, "SegmentGroupName"),
+	}
+	if diff := cmp.Diff(tn, restored, computed...); diff != "" {
+		t.Errorf("Tenant(): -want, +got:\n%s", diff)
+	}
+
+	t.Run("DifferentTenant", func(t *testing.T) {
+		dst := fake.NewServer()
+		defer dst.Close()
+
+		got, err := s.Restore(ctx, adapter.NewClient(dst.Config(), nil))
+		if err != nil {
+			t.Fatalf("Restore(...): %v", err)
+		}
+		for _, r := range got {
+			if !r.Created {
+				t.Errorf("Restore(...): %s %s should have been created", r.Kind, r.Name)
+			}
+		}
+		ids := map[string]string{}
+		for _, r := range got {
+			ids[r.Kind+"/"+r.Name] = r.ID
+		}
+
+		if got := dst.Get(fake.Servers, ids["Server/db.example.com"])["appServerGroupIds"]; !cmp.Equal([]interface{}{ids["ServerGroup/servers"]}, got) {
+			t.Errorf("Restore(...): server should reference the restored server group, got %v", got)
+		}
+		a := dst.Get(fake.Applications, ids["ApplicationSegment/intranet"])
+		if got := a["segmentGroupId"]; got != ids["SegmentGroup/web-apps"] {
+			t.Errorf("Restore(...): application segment should reference the restored segment group, got %v", got)
+		}
+		if got := a["domainNames"]; !cmp.Equal([]interface{}{"intranet.example.com"}, got) {
+			t.Errorf("Restore(...): application segment should have its domain names, got %v", got)
+		}
+	})
+
+	t.Run("SameTenant", func(t *testing.T) {
+		// Someone deleted the application segment in the ZPA portal.
+		c := adapter.NewClient(src.Config(), nil)
+		if _, err := c.ApplicationController.DeleteApplicationUsingDELETE1(&application_controller.DeleteApplicationUsingDELETE1Params{
+			Context:       ctx,
+			ApplicationID: app,
+			CustomerID:    c.CustomerID,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := s.Restore(ctx, c)
+		if err != nil {
+			t.Fatalf("Restore(...): %v", err)
+		}
+		if len(got) != 4 {
+			t.Fatalf("Restore(...): want 4 restored objects, got %d", len(got))
+		}
+		want := []Restored{
+			{Kind: "SegmentGroup", Name: "web-apps", ID: web},
+			{Kind: "ServerGroup", Name: "servers", ID: group},
+			{Kind: "Server", Name: "db.example.com", ID: srv},
+			{Kind: "ApplicationSegment", Name: "intranet", ID: got[3].ID, Created: true},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Restore(...): -want, +got:\n%s", diff)
+		}
+		if got[3].ID == app {
+			t.Errorf("Restore(...): application segment should have been created with a new ID")
+		}
+		if got := src.Get(fake.Applications, got[3].ID)["segmentGroupId"]; got != web {
+			t.Errorf("Restore(...): application segment should reference the existing segment group, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tenant reads the objects of a ZPA tenant that the provider manages
+// and describes them as managed resources.
+package tenant
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/haarchri/zpa-go-client/pkg/models"
+
+	appv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	segmentv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/segmentgroup/v1alpha1"
+	serverv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/server/v1alpha1"
+	servergroupv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/servergroup/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+	application "github.com/crossplane-contrib/provider-zpa/pkg/controller/applicationsegment"
+	segment "github.com/crossplane-contrib/provider-zpa/pkg/controller/segmentgroup"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/server"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/servergroup"
+)
+
+const (
+	errList = "cannot list %ss"
+	errGet  = "cannot get %s %s"
+)
+
+// A Tenant holds the objects of a ZPA tenant that the provider manages.
+type Tenant struct {
+	SegmentGroups       []*models.SegmentGroup        `json:"segmentGroups,omitempty"`
+	ServerGroups        []*models.ServerGroupDTO      `json:"serverGroups,omitempty"`
+	Servers             []*models.ApplicationServer   `json:"servers,omitempty"`
+	ApplicationSegments []*models.ApplicationResource `json:"applicationSegments,omitempty"`
+}
+
+// Read reads the objects of the tenant of the supplied client.
+func Read(ctx context.Context, c *adapter.Client) (*Tenant, error) {
+	t := &Tenant{}
+	var err error
+	if t.SegmentGroups, err = read(ctx, c, segment.NewKind()); err != nil {
+		return nil, err
+	}
+	if t.ServerGroups, err = read(ctx, c, servergroup.NewKind()); err != nil {
+		return nil, err
+	}
+	if t.Servers, err = read(ctx, c, server.NewKind()); err != nil {
+		return nil, err
+	}
+	if t.ApplicationSegments, err = read(ctx, c, application.NewKind()); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// read reads the objects of the supplied kind.
+func read[R resource.Managed, O any](ctx context.Context, c *adapter.Client, k adapter.Kind[R, O]) ([]O, error) {
+	l, err := k.List(ctx, c, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, errList, k.Kind())
+	}
+
+	objs := make([]O, 0, len(l))
+	for _, o := range l {
+		obj, err := k.Get(ctx, c, o.ID)
+		if zpaclient.IsNotFound(err) {
+			// Deleted since it was listed.
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errGet, k.Kind(), o.ID)
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// Resources returns managed resources with the supplied spec that manage the
+// objects of the tenant, in the order they depend on each other:
+// SegmentGroups, ServerGroups, Servers and ApplicationSegments. Resources
+// reference the resources of the other objects of the tenant they use rather
+// than their IDs, so that they can be applied to a cluster together.
+func (t *Tenant) Resources(spec xpv1.ResourceSpec) []resource.Managed {
+	mgs := make([]resource.Managed, 0, len(t.SegmentGroups)+len(t.ServerGroups)+len(t.Servers)+len(t.ApplicationSegments))
+
+	segmentGroups := newNamer("segmentgroup")
+	for _, obj := range t.SegmentGroups {
+		cr := segment.NewKind().Import(obj)
+		cr.SetGroupVersionKind(segmentv1alpha1.SegmentGroupGroupVersionKind)
+		cr.SetName(segmentGroups.name(obj.ID, zpaclient.StringValue(obj.Name)))
+		cr.Spec.ResourceSpec = *spec.DeepCopy()
+		mgs = append(mgs, cr)
+	}
+
+	serverGroups := newNamer("servergroup")
+	for _, obj := range t.ServerGroups {
+		cr := servergroup.NewKind().Import(obj)
+		cr.SetGroupVersionKind(servergroupv1alpha1.ServerGroupGroupVersionKind)
+		cr.SetName(serverGroups.name(obj.ID, obj.Name))
+		cr.Spec.ResourceSpec = *spec.DeepCopy()
+		mgs = append(mgs, cr)
+	}
+
+	servers := newNamer("server")
+	for _, obj := range t.Servers {
+		cr := server.NewKind().Import(obj)
+		cr.SetGroupVersionKind(serverv1alpha1.ServerGroupVersionKind)
+		cr.SetName(servers.name(obj.ID, zpaclient.StringValue(obj.Name)))
+		cr.Spec.ResourceSpec = *spec.DeepCopy()
+		if refs, ok := serverGroups.refs(cr.Spec.ForProvider.ServerGroups); ok {
+			cr.Spec.ForProvider.ServerGroupRefs = refs
+			cr.Spec.ForProvider.ServerGroups = nil
+		}
+		mgs = append(mgs, cr)
+	}
+
+	applications := newNamer("applicationsegment")
+	for _, obj := range t.ApplicationSegments {
+		cr := application.NewKind().Import(obj)
+		cr.SetGroupVersionKind(appv1alpha1.ApplicationSegmentGroupVersionKind)
+		cr.SetName(applications.name(obj.ID, obj.Name))
+		cr.Spec.ResourceSpec = *spec.DeepCopy()
+		p := &cr.Spec.ForProvider
+		if refs, ok := segmentGroups.refs([]string{zpaclient.StringValue(p.SegmentGroupID)}); ok {
+			p.SegmentGroupIDRef = &refs[0]
+			p.SegmentGroupID = nil
+		}
+		if refs, ok := serverGroups.refs(p.ServerGroups); ok {
+			p.ServerGroupRefs = refs
+			p.ServerGroups = nil
+		}
+		mgs = append(mgs, cr)
+	}
+
+	return mgs
+}
+
+// invalid matches what may not be part of the name of a resource.
+var invalid = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// A namer names the resources of a kind after the names of their objects.
+type namer struct {
+	kind  string
+	used  map[string]bool
+	names map[string]string
+}
+
+func newNamer(kind string) *namer {
+	return &namer{kind: kind, used: map[string]bool{}, names: map[string]string{}}
+}
+
+// name returns a unique name for the resource of the object with the supplied
+// ID and name. Names are lower case, with runs of characters that may not be
+// part of a name replaced by a hyphen, and a number appended if a resource
+// already has the name.
+func (n *namer) name(id, name string) string {
+	base := strings.Trim(invalid.ReplaceAllString(strings.ToLower(name), "-"), "-.")
+	if len(base) > 240 {
+		base = strings.Trim(base[:240], "-.")
+	}
+	if base == "" {
+		base = n.kind
+	}
+
+	unique := base
+	for i := 2; n.used[unique]; i++ {
+		unique = base + "-" + strconv.Itoa(i)
+	}
+	n.used[unique] = true
+	n.names[id] = unique
+	return unique
+}
+
+// refs returns references to the resources of the objects with the supplied
+// IDs. It returns false if there are none, or if an object has no resource.
+func (n *namer) refs(ids []string) ([]xpv1.Reference, bool) {
+	if len(ids) == 0 {
+		return nil, false
+	}
+	refs := make([]xpv1.Reference, 0, len(ids))
+	for _, id := range ids {
+		name, ok := n.names[id]
+		if !ok {
+			return nil, false
+		}
+		refs = append(refs, xpv1.Reference{Name: name})
+	}
+	return refs, true
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	appv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	serverv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/server/v1alpha1"
+	"github.com/crossplane-contrib/provider-zpa/pkg/client/fake"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller/adapter"
+)
+
+func TestResources(t *testing.T) {
+	zpa := fake.NewServer()
+	defer zpa.Close()
+
+	web := zpa.Seed(fake.SegmentGroups, fake.Object{"name": "Web Apps", "enabled": true})
+	zpa.Seed(fake.SegmentGroups, fake.Object{"name": "web apps", "enabled": true})
+	group := zpa.Seed(fake.ServerGroups, fake.Object{"name": "Servers", "enabled": true})
+	srv := zpa.Seed(fake.Servers, fake.Object{"name": "db.example.com", "address": "db.example.com", "appServerGroupIds": []interface{}{group}})
+	orphan := zpa.Seed(fake.Servers, fake.Object{"name": "orphan", "address": "orphan.example.com", "appServerGroupIds": []interface{}{"72058000000099999"}})
+	app := zpa.Seed(fake.Applications, fake.Object{
+		"name":           "Intranet",
+		"domainNames":    []interface{}{"intranet.example.com"},
+		"segmentGroupId": web,
+		"serverGroups":   []interface{}{map[string]interface{}{"id": group}},
+	})
+
+	tn, err := Read(context.Background(), adapter.NewClient(zpa.Config(), nil))
+	if err != nil {
+		t.Fatalf("Read(...): %v", err)
+	}
+
+	spec := xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "tenant"}, DeletionPolicy: xpv1.DeletionOrphan}
+	mgs := tn.Resources(spec)
+
+	type resourceName struct{ Kind, Name, ExternalName string }
+	names := make([]resourceName, 0, len(mgs))
+	byExternalName := map[string]resource.Managed{}
+	for _, mg := range mgs {
+		names = append(names, resourceName{mg.GetObjectKind().GroupVersionKind().Kind, mg.GetName(), meta.GetExternalName(mg)})
+		byExternalName[meta.GetExternalName(mg)] = mg
+		if diff := cmp.Diff(spec.ProviderConfigReference, mg.GetProviderConfigReference()); diff != "" {
+			t.Errorf("%s: -want providerConfigRef, +got:\n%s", mg.GetName(), diff)
+		}
+	}
+
+	wantNames := []resourceName{
+		{"SegmentGroup", "web-apps", web},
+		{"SegmentGroup", "web-apps-2", names[1].ExternalName},
+		{"ServerGroup", "servers", group},
+		{"Server", "db.example.com", srv},
+		{"Server", "orphan", orphan},
+		{"ApplicationSegment", "intranet", app},
+	}
+	if diff := cmp.Diff(wantNames, names); diff != "" {
+		t.Errorf("Resources(...): -want names, +got:\n%s", diff)
+	}
+
+	s := byExternalName[srv].(*serverv1alpha1.Server).Spec.ForProvider
+	if diff := cmp.Diff([]xpv1.Reference{{Name: "servers"}}, s.ServerGroupRefs); diff != "" || s.ServerGroups != nil {
+		t.Errorf("Resources(...): server should reference its server group instead of its ID: %v\n%s", s.ServerGroups, diff)
+	}
+
+	o := byExternalName[orphan].(*serverv1alpha1.Server).Spec.ForProvider
+	if diff := cmp.Diff([]string{"72058000000099999"}, o.ServerGroups); diff != "" || o.ServerGroupRefs != nil {
+		t.Errorf("Resources(...): server should keep IDs of server groups outside the tenant: %v\n%s", o.ServerGroupRefs, diff)
+	}
+
+	a := byExternalName[app].(*appv1alpha1.ApplicationSegment).Spec.ForProvider
+	if diff := cmp.Diff(&xpv1.Reference{Name: "web-apps"}, a.SegmentGroupIDRef); diff != "" || a.SegmentGroupID != nil {
+		t.Errorf("Resources(...): application segment should reference its segment group instead of its ID:\n%s", diff)
+	}
+	if diff := cmp.Diff([]xpv1.Reference{{Name: "servers"}}, a.ServerGroupRefs); diff != "" || a.ServerGroups != nil {
+		t.Errorf("Resources(...): application segment should reference its server groups instead of their IDs:\n%s", diff)
+	}
+}
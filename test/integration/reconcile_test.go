@@ -0,0 +1,294 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	appv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/applicationsegment/v1alpha1"
+	segmentv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/segmentgroup/v1alpha1"
+	serverv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/server/v1alpha1"
+	servergroupv1alpha1 "github.com/crossplane-contrib/provider-zpa/apis/servergroup/v1alpha1"
+	zpaclient "github.com/crossplane-contrib/provider-zpa/pkg/client"
+	"github.com/crossplane-contrib/provider-zpa/pkg/client/fake"
+)
+
+const description = "managed by crossplane"
+
+// A graph is an ApplicationSegment with the SegmentGroup, ServerGroup and
+// Server it references.
+type graph struct {
+	segmentGroup *segmentv1alpha1.SegmentGroup
+	serverGroup  *servergroupv1alpha1.ServerGroup
+	server       *serverv1alpha1.Server
+	application  *appv1alpha1.ApplicationSegment
+}
+
+// newGraph returns a graph whose objects are named after the supplied prefix.
+// Its ServerGroup uses a connector group seeded in the fake, and all
+// references between its objects are by name.
+func newGraph(prefix string) graph {
+	connectorGroup := zpa.Seed(fake.ConnectorGroups, fake.Object{"name": prefix + "-connectors", "enabled": true})
+	spec := xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: providerConfigName}}
+
+	return graph{
+		segmentGroup: &segmentv1alpha1.SegmentGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: prefix},
+			Spec: segmentv1alpha1.SegmentGroupSpec{
+				ResourceSpec: spec,
+				ForProvider: segmentv1alpha1.SegmentGroupParameters{
+					Name:        zpaclient.String(prefix),
+					Description: description,
+					Enabled:     zpaclient.Bool(true),
+				},
+			},
+		},
+		serverGroup: &servergroupv1alpha1.ServerGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: prefix},
+			Spec: servergroupv1alpha1.ServerGroupSpec{
+				ResourceSpec: spec,
+				ForProvider: servergroupv1alpha1.ServerGroupParameters{
+					Name:               prefix,
+					Description:        description,
+					Enabled:            zpaclient.Bool(true),
+					AppConnectorGroups: []string{connectorGroup},
+				},
+			},
+		},
+		server: &serverv1alpha1.Server{
+			ObjectMeta: metav1.ObjectMeta{Name: prefix},
+			Spec: serverv1alpha1.ServerSpec{
+				ResourceSpec: spec,
+				ForProvider: serverv1alpha1.ServerParameters{
+					ServerGroupRefs: []xpv1.Reference{{Name: prefix}},
+					Name:            zpaclient.String(prefix),
+					Description:     description,
+					Address:         prefix + ".internal.example.com",
+					Enabled:         zpaclient.Bool(true),
+				},
+			},
+		},
+		application: &appv1alpha1.ApplicationSegment{
+			ObjectMeta: metav1.ObjectMeta{Name: prefix},
+			Spec: appv1alpha1.ApplicationSegmentSpec{
+				ResourceSpec: spec,
+				ForProvider: appv1alpha1.ApplicationSegmentParameters{
+					CustomApplicationSegmentParameters: appv1alpha1.CustomApplicationSegmentParameters{
+						SegmentGroupIDRef: &xpv1.Reference{Name: prefix},
+						ServerGroupRefs:   []xpv1.Reference{{Name: prefix}},
+					},
+					Name:         prefix,
+					Description:  description,
+					Enabled:      zpaclient.Bool(true),
+					DomainNames:  []string{prefix + ".example.com"},
+					TCPPortRange: []appv1alpha1.PortRange{{From: 443, To: 443}},
+				},
+			},
+		},
+	}
+}
+
+// objects returns the objects of the graph, dependents first.
+func (g graph) objects() []resource.Managed {
+	return []resource.Managed{g.application, g.server, g.serverGroup, g.segmentGroup}
+}
+
+// create creates the objects of the graph, dependents first, so that the
+// controllers have to wait for references to resolve, and waits until they
+// are ready.
+func (g graph) create(t *testing.T) {
+	t.Helper()
+	for _, o := range g.objects() {
+		if err := kube.Create(context.Background(), o); err != nil {
+			t.Fatalf("cannot create %s: %v", o.GetName(), err)
+		}
+	}
+	for _, o := range g.objects() {
+		o := o
+		eventually(t, "waiting for "+o.GetName()+" to become ready", func() error { return ready(o) })
+	}
+}
+
+// delete deletes the objects of the graph, dependencies first, and waits
+// until they are gone.
+func (g graph) delete(t *testing.T) {
+	t.Helper()
+	objs := g.objects()
+	for i := len(objs) - 1; i >= 0; i-- {
+		if err := kube.Delete(context.Background(), objs[i]); resource.IgnoreNotFound(err) != nil {
+			t.Fatalf("cannot delete %s: %v", objs[i].GetName(), err)
+		}
+	}
+	for _, o := range objs {
+		o := o
+		eventually(t, "waiting for "+o.GetName()+" to be deleted", func() error {
+			err := kube.Get(context.Background(), client.ObjectKeyFromObject(o), o)
+			if kerrors.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return errors.Errorf("still exists with conditions %v", o.GetCondition(xpv1.TypeReady))
+		})
+	}
+}
+
+// ready reads the supplied object and returns an error unless it is ready.
+func ready(o resource.Managed) error {
+	if err := kube.Get(context.Background(), client.ObjectKeyFromObject(o), o); err != nil {
+		return err
+	}
+	if c := o.GetCondition(xpv1.TypeReady); c.Status != corev1.ConditionTrue {
+		return errors.Errorf("not ready: %s %s: %s", c.Reason, c.Status, c.Message)
+	}
+	return nil
+}
+
+// requestIndex returns the index of the first of the supplied requests with
+// the supplied method, collection and id, or -1.
+func requestIndex(reqs []fake.Request, method, collection, id string) int {
+	for i, r := range reqs {
+		if r.Method == method && r.Collection == collection && r.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestReferencesAndCreationOrder(t *testing.T) {
+	since := len(zpa.Requests())
+	g := newGraph("references")
+	g.create(t)
+	defer g.delete(t)
+
+	segmentGroup := meta.GetExternalName(g.segmentGroup)
+	serverGroup := meta.GetExternalName(g.serverGroup)
+
+	if diff := cmp.Diff(&segmentGroup, g.application.Spec.ForProvider.SegmentGroupID); diff != "" {
+		t.Errorf("ApplicationSegment segmentGroupID: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{serverGroup}, g.application.Spec.ForProvider.ServerGroups); diff != "" {
+		t.Errorf("ApplicationSegment serverGroups: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{serverGroup}, g.server.Spec.ForProvider.ServerGroups); diff != "" {
+		t.Errorf("Server serverGroups: -want, +got:\n%s", diff)
+	}
+
+	app := zpa.Get(fake.Applications, meta.GetExternalName(g.application))
+	if diff := cmp.Diff(segmentGroup, app["segmentGroupId"]); diff != "" {
+		t.Errorf("ZPA application segmentGroupId: -want, +got:\n%s", diff)
+	}
+	srv := zpa.Get(fake.Servers, meta.GetExternalName(g.server))
+	if diff := cmp.Diff([]interface{}{serverGroup}, srv["appServerGroupIds"]); diff != "" {
+		t.Errorf("ZPA server appServerGroupIds: -want, +got:\n%s", diff)
+	}
+
+	// The dependents were created first in the cluster, but must only be
+	// created in ZPA once the objects they reference exist there.
+	reqs := zpa.Requests()[since:]
+	createApplication := requestIndex(reqs, http.MethodPost, fake.Applications, "")
+	createServer := requestIndex(reqs, http.MethodPost, fake.Servers, "")
+	createServerGroup := requestIndex(reqs, http.MethodPost, fake.ServerGroups, "")
+	createSegmentGroup := requestIndex(reqs, http.MethodPost, fake.SegmentGroups, "")
+	if createApplication < createSegmentGroup || createApplication < createServerGroup {
+		t.Errorf("application created at request %d, before segment group (%d) or server group (%d)", createApplication, createSegmentGroup, createServerGroup)
+	}
+	if createServer < createServerGroup {
+		t.Errorf("server created at request %d, before server group (%d)", createServer, createServerGroup)
+	}
+}
+
+func TestDriftCorrection(t *testing.T) {
+	g := newGraph("drift")
+	g.create(t)
+	defer g.delete(t)
+
+	id := meta.GetExternalName(g.application)
+	if !zpa.Modify(fake.Applications, id, func(o fake.Object) { o["description"] = "changed in the portal" }) {
+		t.Fatalf("application %s does not exist in ZPA", id)
+	}
+
+	eventually(t, "waiting for the drift to be corrected", func() error {
+		if d := zpa.Get(fake.Applications, id)["description"]; d != description {
+			return errors.Errorf("description is %q", d)
+		}
+		return nil
+	})
+
+	eventually(t, "waiting for the drift to be reported", func() error {
+		cr := &appv1alpha1.ApplicationSegment{}
+		if err := kube.Get(context.Background(), client.ObjectKeyFromObject(g.application), cr); err != nil {
+			return err
+		}
+		d := cr.Status.AtProvider.LastDrift
+		if d == nil {
+			return errors.New("no drift reported")
+		}
+		if d.ModifiedBy != fake.PortalModifiedBy {
+			return errors.Errorf("drift reported as modified by %q", d.ModifiedBy)
+		}
+		return nil
+	})
+}
+
+func TestDeletion(t *testing.T) {
+	g := newGraph("deletion")
+	g.create(t)
+
+	since := len(zpa.Requests())
+	ids := map[string]string{
+		fake.Applications:  meta.GetExternalName(g.application),
+		fake.Servers:       meta.GetExternalName(g.server),
+		fake.ServerGroups:  meta.GetExternalName(g.serverGroup),
+		fake.SegmentGroups: meta.GetExternalName(g.segmentGroup),
+	}
+
+	// Delete dependencies first; their controllers must wait for the
+	// dependents to be gone.
+	g.delete(t)
+
+	for collection, id := range ids {
+		if o := zpa.Get(collection, id); o != nil {
+			t.Errorf("%s %s still exists in ZPA", collection, id)
+		}
+	}
+
+	reqs := zpa.Requests()[since:]
+	deleteApplication := requestIndex(reqs, http.MethodDelete, fake.Applications, ids[fake.Applications])
+	deleteServerGroup := requestIndex(reqs, http.MethodDelete, fake.ServerGroups, ids[fake.ServerGroups])
+	deleteSegmentGroup := requestIndex(reqs, http.MethodDelete, fake.SegmentGroups, ids[fake.SegmentGroups])
+	if deleteApplication < 0 || deleteApplication > deleteSegmentGroup || deleteApplication > deleteServerGroup {
+		t.Errorf("application deleted at request %d, after segment group (%d) or server group (%d)", deleteApplication, deleteSegmentGroup, deleteServerGroup)
+	}
+}
@@ -0,0 +1,201 @@
+//go:build integration
+// +build integration
+
+/*
+Copyright 2022 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package integration runs the controllers of the provider against a local
+// API server started by envtest and the in-process fake of the ZPA API. It
+// needs the envtest binaries, see KUBEBUILDER_ASSETS, but no network access.
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	xpcontroller "github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+
+	"github.com/crossplane-contrib/provider-zpa/apis"
+	"github.com/crossplane-contrib/provider-zpa/apis/v1alpha1"
+	"github.com/crossplane-contrib/provider-zpa/pkg/client/fake"
+	"github.com/crossplane-contrib/provider-zpa/pkg/controller"
+)
+
+const (
+	namespace          = "crossplane-system"
+	providerConfigName = "default"
+	credentialsName    = "zpa-credentials"
+
+	// pollInterval is short so drift is corrected while the tests wait.
+	pollInterval = 1 * time.Second
+
+	// timeout bounds how long the tests wait for the controllers.
+	timeout = 60 * time.Second
+)
+
+var (
+	// kube is a client of the API server started by envtest.
+	kube client.Client
+
+	// zpa is the fake ZPA API the controllers talk to.
+	zpa *fake.Server
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	zpa = fake.NewServer()
+	defer zpa.Close()
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "package", "crds")},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot start envtest: %v\n", err)
+		return 1
+	}
+	defer env.Stop() // nolint:errcheck
+
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot add Kubernetes APIs to scheme: %v\n", err)
+		return 1
+	}
+	if err := apis.AddToScheme(s); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot add zpa APIs to scheme: %v\n", err)
+		return 1
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: s, MetricsBindAddress: "0"})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot create controller manager: %v\n", err)
+		return 1
+	}
+
+	o := xpcontroller.Options{
+		Logger:                  logging.NewNopLogger(),
+		MaxConcurrentReconciles: 10,
+		PollInterval:            pollInterval,
+		GlobalRateLimiter:       ratelimiter.NewGlobal(100),
+		Features:                &feature.Flags{},
+	}
+	if err := controller.Setup(mgr, o); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot setup zpa controllers: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		if err := mgr.Start(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot start controller manager: %v\n", err)
+		}
+	}()
+
+	kube = mgr.GetClient()
+	if err := createProviderConfig(ctx, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot create ProviderConfig: %v\n", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// createProviderConfig creates the ProviderConfig the managed resources of
+// the tests use, which connects to the fake.
+func createProviderConfig(ctx context.Context, cfg *rest.Config) error {
+	// The cache of the manager may not have started yet, so write with a
+	// client that does not read from it.
+	c, err := client.New(cfg, client.Options{Scheme: kube.Scheme()})
+	if err != nil {
+		return err
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+	if err := c.Create(ctx, ns); err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: credentialsName},
+		Data: map[string][]byte{
+			"clientID":     []byte(fake.DefaultClientID),
+			"clientSecret": []byte(fake.DefaultClientSecret),
+		},
+	}
+	if err := c.Create(ctx, secret); err != nil {
+		return err
+	}
+
+	credentials := func(key string) v1alpha1.ProviderCredentials {
+		return v1alpha1.ProviderCredentials{
+			Source: xpv1.CredentialsSourceSecret,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+				SecretRef: &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Namespace: namespace, Name: credentialsName},
+					Key:             key,
+				},
+			},
+		}
+	}
+	pc := &v1alpha1.ProviderConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: providerConfigName},
+		Spec: v1alpha1.ProviderConfigSpec{
+			ClientID:     credentials("clientID"),
+			ClientSecret: credentials("clientSecret"),
+			CustomerID:   zpa.CustomerID(),
+			Host:         zpa.Host(),
+			// The provider trusts the certificate of the fake only for
+			// this ProviderConfig.
+			CABundle: zpa.CABundle(),
+		},
+	}
+	return c.Create(ctx, pc)
+}
+
+// eventually calls fn until it returns nil and fails the test if it does not
+// do so before the timeout.
+func eventually(t *testing.T, what string, fn func() error) {
+	t.Helper()
+	var err error
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(250 * time.Millisecond) {
+		if err = fn(); err == nil {
+			return
+		}
+	}
+	t.Fatalf("%s: %v", what, err)
+}